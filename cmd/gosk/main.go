@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/jasonsites/gosk/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Execute())
+}
@@ -3,19 +3,23 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/go-viper/mapstructure/v2"
 	"github.com/jasonsites/gosk/internal/app"
 	"github.com/spf13/viper"
 )
 
 // Configuration defines application configuration
 type Configuration struct {
-	App      App      `validate:"required"`
-	External External `validate:"required"`
-	HTTP     HTTP     `validate:"required"`
-	Logger   Logger   `validate:"required"`
-	Postgres Postgres `validate:"required"`
+	App       App       `validate:"required"`
+	External  External  `validate:"required"`
+	HTTP      HTTP      `validate:"required"`
+	Jobs      Jobs      `validate:"required"`
+	Logger    Logger    `validate:"required"`
+	Postgres  Postgres  `validate:"required"`
+	Scheduler Scheduler `validate:"required"`
 }
 
 type App struct {
@@ -32,22 +36,110 @@ type External struct {
 
 // HTTP defines HTTP Server configuration
 type HTTP struct {
-	Router struct {
-		Namespace string `validate:"required"`
-		Paging    struct {
+	CORS struct {
+		AllowedOrigins []string `validate:"required,min=1"`
+	} `validate:"required"`
+	RateLimit RateLimit `validate:"required"`
+	Router    struct {
+		// BodyLimit defines the default maximum size (bytes) of request bodies, and route-specific maximum sizes
+		// (the maximum size of import request bodies is defined by Import.MaxSize)
+		BodyLimit struct {
+			Default int64            `validate:"required,min=1"`
+			Routes  []BodyLimitRoute `validate:"dive"`
+		}
+		// ErrorAbout defines the URL template of JSON:API error object links.about members, in which {code} is
+		// replaced by the error code (e.g. https://docs.example.com/errors/{code}). Omitted when empty
+		ErrorAbout string `validate:"omitempty,url"`
+		// ErrorFormat defines the default error response format (jsonapi or problem), used unless the request Accept
+		// header prefers another format
+		ErrorFormat string `validate:"required,oneof=jsonapi problem"`
+		// Import defines the maximum size (bytes) of import request bodies, and the maximum size of imports run
+		// synchronously (larger imports, or those without a Content-Length, are run asynchronously by import jobs)
+		Import struct {
+			MaxSize     int64 `validate:"required,min=1"`
+			SyncMaxSize int64 `validate:"required,min=1,ltefield=MaxSize"`
+		}
+		// JSONAPI defines the supported JSON:API extension and profile URIs, accepted as ext and profile media type
+		// parameters by content negotiation
+		JSONAPI struct {
+			Extensions []string `validate:"dive,url"`
+			Profiles   []string `validate:"dive,url"`
+		}
+		// MaxIncludeDepth defines the maximum relationship path depth of include query parameters
+		MaxIncludeDepth uint   `validate:"required"`
+		Namespace       string `validate:"required"`
+		// OpenAPI configures the generated OpenAPI document (/{namespace}/openapi.json) and its viewer
+		// (/{namespace}/docs)
+		OpenAPI struct {
+			Enabled bool
+			Viewer  bool
+			// ViewerScript defines the (version pinned) URL of the Redoc viewer bundle, loaded with the subresource
+			// integrity hash ViewerIntegrity (e.g. sha384-...), required when the viewer is enabled
+			ViewerIntegrity string `validate:"required_if=Viewer true,omitempty,startswith=sha384-|startswith=sha512-"`
+			ViewerScript    string `validate:"required,url"`
+		}
+		Paging struct {
 			DefaultLimit uint `validate:"required"`
 		}
 	} `validate:"required"`
 	Server struct {
 		Host string
 		Port uint `validate:"required,max=65535"`
+		// TrustedProxies defines the IP addresses and CIDR ranges of trusted reverse proxies (e.g. load balancers),
+		// whose X-Forwarded-For and X-Real-IP headers identify the client IP address
+		TrustedProxies []string `validate:"dive,cidr|ip"`
 	} `validate:"required"`
 }
 
+// BodyLimitRoute defines a maximum request body size (bytes) for a single route (e.g. method "POST", path
+// "/domain/examples")
+type BodyLimitRoute struct {
+	Method string `validate:"required"`
+	Path   string `validate:"required"`
+	Limit  int64  `validate:"required,min=1"`
+}
+
+// RateLimit defines the HTTP rate limiting configuration
+type RateLimit struct {
+	Default RateLimitQuota `validate:"required"`
+	Enabled bool
+	Routes  []RateLimitRoute `validate:"dive"`
+	Store   string           `validate:"oneof=memory postgres"`
+}
+
+// RateLimitQuota defines a quota of Limit requests per Period
+type RateLimitQuota struct {
+	Limit  uint          `validate:"required"`
+	Period time.Duration `validate:"required"`
+}
+
+// RateLimitRoute defines a quota for a single route (e.g. method "POST", path "/domain/examples")
+type RateLimitRoute struct {
+	Method string        `validate:"required"`
+	Path   string        `validate:"required"`
+	Limit  uint          `validate:"required"`
+	Period time.Duration `validate:"required"`
+}
+
+// Jobs defines the background job worker configuration
+type Jobs struct {
+	Concurrency  int           `validate:"required,min=1"`
+	PollInterval time.Duration `validate:"required"`
+	Queues       []string      `validate:"required,min=1"`
+	Timeout      time.Duration `validate:"required"`
+}
+
 // Logger defines the primary logger configuration
 type Logger struct {
-	Format  string `validate:"oneof=json styled"`
-	Level   string `validate:"oneof=debug info warn error"`
+	Format string `validate:"oneof=json styled"`
+	Level  string `validate:"oneof=debug info warn error"`
+	// Redact defines the sensitive values redacted from all logs: the names of HTTP headers, the JSON paths of body
+	// fields (e.g. data.attributes.password) and the object keys (and form fields) redacted at any depth
+	Redact struct {
+		Fields  []string
+		Headers []string
+		Keys    []string
+	}
 	Verbose bool
 }
 
@@ -58,61 +150,172 @@ type Metadata struct {
 	Version     string
 }
 
-// Postgres defines the postgres connection parameters
+// Postgres defines the postgres connection parameters, with read replicas defined as data source names
+// (URL or keyword/value, which may be secret references)
 type Postgres struct {
-	Database string `validate:"required"`
-	Host     string `validate:"required"`
-	Password string `validate:"required"`
-	Port     uint   `validate:"required,max=65535"`
-	User     string `validate:"required"`
+	ApplicationName  string
+	Database         string `validate:"required"`
+	Host             string `validate:"required"`
+	MigrateOnStartup bool
+	Password         Secret `validate:"required"`
+	Pool             PostgresPool
+	Port             uint          `validate:"required,max=65535"`
+	Replicas         []Secret      `validate:"dive,required"`
+	StatementTimeout time.Duration `validate:"min=0"`
+	TLS              PostgresTLS
+	User             string `validate:"required"`
+}
+
+// PostgresPool defines the postgres connection pool parameters
+type PostgresPool struct {
+	HealthCheckPeriod time.Duration `validate:"required"`
+	MaxConnIdleTime   time.Duration `validate:"required"`
+	MaxConnLifetime   time.Duration `validate:"required"`
+	MaxConns          int32         `validate:"required,min=1"`
+	MinConns          int32         `validate:"min=0,ltefield=MaxConns"`
+}
+
+// Scheduler defines the scheduled task configuration
+type Scheduler struct {
+	Enabled bool
+	Tasks   struct {
+		ExamplePurge   ScheduledTask
+		JobsPrune      ScheduledTask
+		RateLimitPrune ScheduledTask
+	}
+}
+
+// ScheduledTask defines the cron schedule (empty to disable) and retention period of a maintenance task
+type ScheduledTask struct {
+	Retention time.Duration `validate:"required"`
+	Schedule  string
+}
+
+// PostgresTLS defines the postgres connection TLS parameters
+type PostgresTLS struct {
+	CertFile     string `validate:"required_with=KeyFile,omitempty,file"`
+	KeyFile      string `validate:"required_with=CertFile,omitempty,file"`
+	RootCertFile string `validate:"omitempty,file"`
+	SSLMode      string `validate:"oneof=disable allow prefer require verify-ca verify-full"`
 }
 
+// configFile defines an explicit config file path (see SetConfigFile)
+var configFile string
+
 // LoadConfiguration loads config parameters on startup
 func LoadConfiguration() (*Configuration, error) {
-	var conf Configuration
-
-	viper.SetConfigName("config")
-	viper.AddConfigPath("/app/config")
+	if configFile != "" {
+		viper.SetConfigFile(configFile)
+	} else {
+		viper.SetConfigName("config")
+		viper.AddConfigPath("/app/config")
+	}
 	viper.AllowEmptyEnv(true)
 
-	viper.OnConfigChange(func(e fsnotify.Event) {
-		fmt.Println("config file changed:", e.Name)
-	})
-	viper.WatchConfig()
-
 	// default values
 	viper.SetDefault("app.metadata.environment", "production")
 	viper.SetDefault("app.metadata.name", "gosk")
 	viper.SetDefault("app.metadata.version", "local")
 	viper.SetDefault("external.example.baseURL", "http://www.example.com")
 	viper.SetDefault("external.example.timeout", 25000)
+	viper.SetDefault("http.cors.allowedOrigins", []string{"http://*", "https://*"})
+	viper.SetDefault("http.rateLimit.default.limit", 100)
+	viper.SetDefault("http.rateLimit.default.period", "1m")
+	viper.SetDefault("http.rateLimit.enabled", true)
+	viper.SetDefault("http.rateLimit.store", "memory")
+	viper.SetDefault("http.router.bodyLimit.default", 1<<20)
+	viper.SetDefault("http.router.errorAbout", "")
+	viper.SetDefault("http.router.errorFormat", "jsonapi")
+	viper.SetDefault("http.router.import.maxSize", 32<<20)
+	viper.SetDefault("http.router.import.syncMaxSize", 1<<20)
+	viper.SetDefault("http.router.jsonapi.extensions", []string{})
+	viper.SetDefault("http.router.jsonapi.profiles", []string{})
+	viper.SetDefault("http.router.maxIncludeDepth", 3)
 	viper.SetDefault("http.router.namespace", "domain")
+	viper.SetDefault("http.router.openapi.enabled", true)
+	viper.SetDefault("http.router.openapi.viewer", false)
+	viper.SetDefault("http.router.openapi.viewerScript", "https://cdn.redoc.ly/redoc/v2.1.5/bundles/redoc.standalone.js")
 	viper.SetDefault("http.router.paging.defaultLimit", 20)
 	viper.SetDefault("http.server.host", "localhost")
 	viper.SetDefault("http.server.port", 9202)
+	viper.SetDefault("http.server.trustedProxies", []string{})
+	viper.SetDefault("jobs.concurrency", 4)
+	viper.SetDefault("jobs.pollInterval", "1s")
+	viper.SetDefault("jobs.queues", []string{"default"})
+	viper.SetDefault("jobs.timeout", "5m")
 	viper.SetDefault("logger.enabled", true)
 	viper.SetDefault("logger.format", "json")
 	viper.SetDefault("logger.level", "info")
+	viper.SetDefault("logger.redact.fields", []string{})
+	viper.SetDefault("logger.redact.headers", []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie", "X-API-Key"})
+	viper.SetDefault("logger.redact.keys", []string{"accessToken", "apiKey", "clientSecret", "password", "refreshToken", "secret", "token"})
 	viper.SetDefault("logger.verbose", false)
 	viper.SetDefault("postgres.database", "svcdb")
 	viper.SetDefault("postgres.host", "postgres")
+	viper.SetDefault("postgres.migrateOnStartup", false)
 	viper.SetDefault("postgres.password", "postgres")
+	viper.SetDefault("postgres.pool.healthCheckPeriod", "1m")
+	viper.SetDefault("postgres.pool.maxConnIdleTime", "30m")
+	viper.SetDefault("postgres.pool.maxConnLifetime", "1h")
+	viper.SetDefault("postgres.pool.maxConns", 10)
+	viper.SetDefault("postgres.pool.minConns", 0)
 	viper.SetDefault("postgres.port", 5432)
+	viper.SetDefault("postgres.replicas", []Secret{})
+	viper.SetDefault("postgres.statementTimeout", "0s")
+	viper.SetDefault("postgres.tls.sslMode", "disable")
 	viper.SetDefault("postgres.user", "postgres")
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.tasks.examplePurge.retention", "720h")
+	viper.SetDefault("scheduler.tasks.examplePurge.schedule", "@daily")
+	viper.SetDefault("scheduler.tasks.jobsPrune.retention", "168h")
+	viper.SetDefault("scheduler.tasks.jobsPrune.schedule", "@hourly")
+	viper.SetDefault("scheduler.tasks.rateLimitPrune.retention", "1h")
+	viper.SetDefault("scheduler.tasks.rateLimitPrune.schedule", "*/15 * * * *")
 
 	// environment variables
 	viper.BindEnv("app.metadata.environment", "APP_ENV")
 	viper.BindEnv("app.metadata.version", "APP_VERSION")
+	viper.BindEnv("http.cors.allowedOrigins", "HTTP_CORS_ALLOWED_ORIGINS")
+	viper.BindEnv("http.rateLimit.enabled", "HTTP_RATE_LIMIT_ENABLED")
+	viper.BindEnv("http.rateLimit.store", "HTTP_RATE_LIMIT_STORE")
+	viper.BindEnv("http.router.bodyLimit.default", "HTTP_BODY_LIMIT")
+	viper.BindEnv("http.router.errorAbout", "HTTP_ERROR_ABOUT")
+	viper.BindEnv("http.router.errorFormat", "HTTP_ERROR_FORMAT")
+	viper.BindEnv("http.router.import.maxSize", "HTTP_IMPORT_MAX_SIZE")
+	viper.BindEnv("http.router.import.syncMaxSize", "HTTP_IMPORT_SYNC_MAX_SIZE")
+	viper.BindEnv("http.router.jsonapi.extensions", "HTTP_JSONAPI_EXTENSIONS")
+	viper.BindEnv("http.router.jsonapi.profiles", "HTTP_JSONAPI_PROFILES")
+	viper.BindEnv("http.router.openapi.enabled", "HTTP_OPENAPI_ENABLED")
+	viper.BindEnv("http.router.openapi.viewer", "HTTP_OPENAPI_VIEWER")
+	viper.BindEnv("http.router.openapi.viewerIntegrity", "HTTP_OPENAPI_VIEWER_INTEGRITY")
+	viper.BindEnv("http.router.openapi.viewerScript", "HTTP_OPENAPI_VIEWER_SCRIPT")
 	viper.BindEnv("http.server.host", "HTTP_SERVER_HOST")
 	viper.BindEnv("http.server.port", "HTTP_SERVER_PORT")
+	viper.BindEnv("http.server.trustedProxies", "HTTP_SERVER_TRUSTED_PROXIES")
+	viper.BindEnv("jobs.concurrency", "JOBS_CONCURRENCY")
+	viper.BindEnv("jobs.queues", "JOBS_QUEUES")
 	viper.BindEnv("logger.format", "LOGGER_FORMAT")
 	viper.BindEnv("logger.level", "LOGGER_LEVEL")
+	viper.BindEnv("logger.redact.fields", "LOGGER_REDACT_FIELDS")
+	viper.BindEnv("logger.redact.headers", "LOGGER_REDACT_HEADERS")
+	viper.BindEnv("logger.redact.keys", "LOGGER_REDACT_KEYS")
 	viper.BindEnv("logger.verbose", "LOGGER_VERBOSE")
+	viper.BindEnv("postgres.applicationName", "POSTGRES_APPLICATION_NAME")
 	viper.BindEnv("postgres.database", "POSTGRES_DB")
 	viper.BindEnv("postgres.host", "POSTGRES_HOST")
-	viper.BindEnv("postgres.password", "POSTGRES_PASSWORD")
+	viper.BindEnv("postgres.migrateOnStartup", "POSTGRES_MIGRATE_ON_STARTUP")
+	viper.BindEnv("postgres.password", "POSTGRES_PASSWORD") // supports secret references (e.g. ${file:///run/secrets/db})
+	viper.BindEnv("postgres.pool.maxConns", "POSTGRES_POOL_MAX_CONNS")
+	viper.BindEnv("postgres.pool.minConns", "POSTGRES_POOL_MIN_CONNS")
 	viper.BindEnv("postgres.port", "POSTGRES_PORT")
+	viper.BindEnv("postgres.replicas", "POSTGRES_REPLICAS") // comma separated data source names (e.g. postgres://replica:5432/db)
+	viper.BindEnv("postgres.statementTimeout", "POSTGRES_STATEMENT_TIMEOUT")
+	viper.BindEnv("postgres.tls.certFile", "POSTGRES_SSLCERT")
+	viper.BindEnv("postgres.tls.keyFile", "POSTGRES_SSLKEY")
+	viper.BindEnv("postgres.tls.rootCertFile", "POSTGRES_SSLROOTCERT")
+	viper.BindEnv("postgres.tls.sslMode", "POSTGRES_SSLMODE")
 	viper.BindEnv("postgres.user", "POSTGRES_USER")
+	viper.BindEnv("scheduler.enabled", "SCHEDULER_ENABLED")
 
 	// read, unmarshal, and validate configuration
 	if err := viper.ReadInConfig(); err != nil {
@@ -121,20 +324,70 @@ func LoadConfiguration() (*Configuration, error) {
 		} else {
 			err := fmt.Errorf("configuration read error: %w", err)
 			slog.Error(err.Error())
-			return &conf, err
+			return &Configuration{}, err
 		}
 
 	}
-	if err := viper.Unmarshal(&conf); err != nil {
+
+	conf, err := decodeConfiguration()
+	if err != nil {
+		return conf, err
+	}
+
+	// secrets are redacted when printed or logged
+	slog.Debug("configuration loaded", slog.Any("config", conf))
+
+	return conf, nil
+}
+
+// SetConfigFile overrides the default config file lookup (/app/config/config.*) with an explicit path,
+// and must be called before LoadConfiguration
+func SetConfigFile(path string) {
+	configFile = path
+}
+
+// WatchConfiguration watches the config file (if one was read on load), calling fn with the
+// decoded configuration (or the decode error) on each change. The configuration is not validated, which is left
+// to fn
+func WatchConfiguration(fn func(*Configuration, error)) {
+	if viper.ConfigFileUsed() == "" {
+		return
+	}
+
+	viper.OnConfigChange(func(e fsnotify.Event) {
+		slog.Info(fmt.Sprintf("config file changed: %s", e.Name))
+		fn(unmarshalConfiguration())
+	})
+	viper.WatchConfig()
+}
+
+// decodeConfiguration unmarshals and validates the current viper configuration
+func decodeConfiguration() (*Configuration, error) {
+	conf, err := unmarshalConfiguration()
+	if err != nil {
+		return conf, err
+	}
+	if err := app.Validator.Validate.Struct(conf); err != nil {
+		return conf, fmt.Errorf("invalid configuration: %v", err)
+	}
+
+	return conf, nil
+}
+
+// unmarshalConfiguration unmarshals the current viper configuration
+func unmarshalConfiguration() (*Configuration, error) {
+	var conf Configuration
+
+	hook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		secretDecodeHook(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+	if err := viper.Unmarshal(&conf, hook); err != nil {
 		err := fmt.Errorf("configuration unmarshal error: %w", err)
 		slog.Error(err.Error())
 		return &conf, err
 	}
-	if err := app.Validator.Validate.Struct(&conf); err != nil {
-		return &conf, fmt.Errorf("invalid configuration: %v", err)
-	}
-
-	// fmt.Printf("%+v\n", conf)
 
 	return &conf, nil
 }
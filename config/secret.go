@@ -0,0 +1,182 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/go-viper/mapstructure/v2"
+)
+
+// Redacted is the placeholder written in place of secret values
+const Redacted = "[REDACTED]"
+
+// Secret wraps a sensitive configuration value, redacting it whenever it is printed, logged or marshaled
+type Secret string
+
+// Reveal returns the underlying secret value
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String implements the Stringer interface for print statements
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// GoString implements the GoStringer interface for %#v print statements
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+// LogValue implements the slog.LogValuer interface
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// SecretProvider resolves secret references (e.g. `${file:///run/secrets/db}`, `${env:NAME}`) for a given scheme
+type SecretProvider interface {
+	Resolve(ctx context.Context, ref *url.URL) (string, error)
+}
+
+// SecretProviderFunc adapts a function to the SecretProvider interface
+type SecretProviderFunc func(ctx context.Context, ref *url.URL) (string, error)
+
+// Resolve calls f(ctx, ref)
+func (f SecretProviderFunc) Resolve(ctx context.Context, ref *url.URL) (string, error) {
+	return f(ctx, ref)
+}
+
+var secretProviders = struct {
+	mutex     sync.RWMutex
+	providers map[string]SecretProvider
+}{
+	providers: map[string]SecretProvider{
+		"env":  SecretProviderFunc(resolveEnvSecret),
+		"file": SecretProviderFunc(resolveFileSecret),
+	},
+}
+
+// RegisterSecretProvider registers a SecretProvider for the given reference scheme,
+// and must be called before LoadConfiguration
+func RegisterSecretProvider(scheme string, p SecretProvider) {
+	secretProviders.mutex.Lock()
+	defer secretProviders.mutex.Unlock()
+
+	secretProviders.providers[strings.ToLower(scheme)] = p
+}
+
+// ResolveSecret resolves the given value if it is a secret reference (`${scheme:ref}`) to a registered secret
+// provider, otherwise the value is returned as a literal secret. A literal value starting with `${` is escaped as `$${`
+func ResolveSecret(ctx context.Context, value string) (Secret, error) {
+	if strings.HasPrefix(value, "$${") {
+		return Secret(value[1:]), nil
+	}
+	if !strings.HasPrefix(value, "${") || !strings.HasSuffix(value, "}") {
+		return Secret(value), nil
+	}
+
+	reference := value[2 : len(value)-1]
+	scheme, _, found := strings.Cut(reference, ":")
+	if !found {
+		return "", fmt.Errorf("invalid secret reference, expected ${scheme:ref}")
+	}
+
+	secretProviders.mutex.RLock()
+	provider, ok := secretProviders.providers[strings.ToLower(scheme)]
+	secretProviders.mutex.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown secret provider %q", scheme)
+	}
+
+	ref, err := url.Parse(reference)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s secret reference", scheme)
+	}
+
+	secret, err := provider.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("%s secret resolution error: %w", scheme, err)
+	}
+
+	return Secret(secret), nil
+}
+
+// secretDecodeHook resolves string configuration values into Secret fields, splitting comma separated values
+// (e.g. from environment variables) into Secret slice fields
+func secretDecodeHook() mapstructure.DecodeHookFuncType {
+	secretType := reflect.TypeOf(Secret(""))
+	secretsType := reflect.TypeOf([]Secret{})
+
+	return func(f reflect.Type, t reflect.Type, data any) (any, error) {
+		value, ok := data.(string)
+		if !ok {
+			return data, nil
+		}
+
+		switch t {
+		case secretType:
+			return ResolveSecret(context.Background(), value)
+		case secretsType:
+			if value == "" {
+				return []Secret{}, nil
+			}
+			values := strings.Split(value, ",")
+			secrets := make([]Secret, 0, len(values))
+			for _, value := range values {
+				secret, err := ResolveSecret(context.Background(), value)
+				if err != nil {
+					return nil, err
+				}
+				secrets = append(secrets, secret)
+			}
+			return secrets, nil
+		default:
+			return data, nil
+		}
+	}
+}
+
+// resolveEnvSecret resolves `${env:NAME}` references from the environment
+func resolveEnvSecret(ctx context.Context, ref *url.URL) (string, error) {
+	name := ref.Opaque
+	if name == "" {
+		name = ref.Host
+	}
+
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q not set", name)
+	}
+
+	return value, nil
+}
+
+// resolveFileSecret resolves `${file:///path/to/secret}` references from the filesystem
+func resolveFileSecret(ctx context.Context, ref *url.URL) (string, error) {
+	path := ref.Path
+	if path == "" {
+		path = ref.Opaque
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,13 @@
+package database
+
+import "embed"
+
+// Migrations embeds all sql migrations for use by the migration runner
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// Seeds embeds all per-environment seed sets for use by the seeder
+//
+//go:embed seeds
+var Seeds embed.FS
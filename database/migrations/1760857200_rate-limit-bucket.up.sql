@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS rate_limit_bucket (
+  key               text                                PRIMARY KEY,
+  tokens            double precision  NOT NULL,
+  updated_on        timestamptz       NOT NULL    DEFAULT (now() at time zone 'utc')
+);
+
+CREATE INDEX rate_limit_bucket_updated_on_idx ON rate_limit_bucket (updated_on);
@@ -0,0 +1,31 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_catalog.pg_type WHERE typname = 'job_status' AND typcategory = 'E') THEN
+        CREATE TYPE job_status AS ENUM ('pending', 'running', 'succeeded', 'dead');
+    END IF;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS jobs (
+  id                uuid                                PRIMARY KEY DEFAULT gen_random_uuid(),
+  queue             text              NOT NULL    DEFAULT 'default',
+  kind              text              NOT NULL,
+  payload           jsonb             NOT NULL    DEFAULT '{}'::jsonb,
+  unique_key        text,
+
+  status            job_status        NOT NULL    DEFAULT 'pending',
+  attempts          integer           NOT NULL    DEFAULT 0,
+  max_attempts      integer           NOT NULL    DEFAULT 5,
+  last_error        text,
+  run_at            timestamptz       NOT NULL    DEFAULT (now() at time zone 'utc'),
+  locked_by         text,
+  locked_on         timestamptz,
+  finished_on       timestamptz,
+
+  created_on        timestamptz       NOT NULL    DEFAULT (now() at time zone 'utc'),
+  modified_on       timestamptz       NOT NULL    DEFAULT (now() at time zone 'utc')
+);
+
+CREATE INDEX jobs_claim_idx ON jobs (queue, run_at) WHERE status = 'pending';
+CREATE INDEX jobs_running_idx ON jobs (locked_on) WHERE status = 'running';
+CREATE UNIQUE INDEX jobs_unique_key_idx ON jobs (kind, unique_key) WHERE unique_key IS NOT NULL AND status IN ('pending', 'running');
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS scheduled_task (
+  name              text                                PRIMARY KEY,
+  last_scheduled_on timestamptz       NOT NULL,
+  last_started_on   timestamptz       NOT NULL,
+  last_finished_on  timestamptz,
+  last_status       text              NOT NULL,
+  last_error        text,
+  last_trace_id     text              NOT NULL,
+  last_run_by       text              NOT NULL
+);
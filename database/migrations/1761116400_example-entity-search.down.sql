@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS example_entity_search_idx;
+
+ALTER TABLE example_entity DROP COLUMN IF EXISTS search;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS example_entity_tag;
+
+DROP TABLE IF EXISTS tag;
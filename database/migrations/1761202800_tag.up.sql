@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS tag (
+  id                uuid                                PRIMARY KEY DEFAULT gen_random_uuid(),
+  name              text                                NOT NULL,
+
+  created_on        timestamptz       NOT NULL    DEFAULT (now() at time zone 'utc')
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tag_name_idx ON tag (lower(name));
+
+CREATE TABLE IF NOT EXISTS example_entity_tag (
+  example_id        uuid              NOT NULL    REFERENCES example_entity (id) ON DELETE CASCADE,
+  tag_id            uuid              NOT NULL    REFERENCES tag (id) ON DELETE CASCADE,
+
+  created_on        timestamptz       NOT NULL    DEFAULT (now() at time zone 'utc'),
+
+  PRIMARY KEY (example_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS example_entity_tag_tag_id_idx ON example_entity_tag (tag_id);
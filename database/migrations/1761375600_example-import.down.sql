@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS example_import_chunk;
+DROP TABLE IF EXISTS example_import;
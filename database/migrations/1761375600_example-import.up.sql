@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS example_import (
+  job_id            uuid                                PRIMARY KEY REFERENCES jobs (id) ON DELETE CASCADE,
+  media_type        text              NOT NULL,
+  mode              text              NOT NULL,
+
+  created_on        timestamptz       NOT NULL    DEFAULT (now() at time zone 'utc')
+);
+
+CREATE TABLE IF NOT EXISTS example_import_chunk (
+  job_id            uuid                                REFERENCES example_import (job_id) ON DELETE CASCADE,
+  seq               integer           NOT NULL,
+  data              bytea             NOT NULL,
+
+  PRIMARY KEY (job_id, seq)
+);
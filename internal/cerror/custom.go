@@ -1,11 +1,18 @@
 package cerror
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"maps"
+)
 
 // CustomError
 type CustomError struct {
+	code      string
 	errorType string
 	message   string
+	// meta is referenced by pointer so that CustomError remains comparable (e.g. by errors.Is)
+	meta      *map[string]any
 	sourceErr error
 }
 
@@ -18,11 +25,27 @@ func (e CustomError) Error() string {
 	return e.message
 }
 
+// Code returns the stable machine-readable error code, defaulting to the code of the error type
+func (e CustomError) Code() string {
+	if e.code != "" {
+		return e.code
+	}
+	return typeCodes[e.errorType]
+}
+
 // ErrorMessage returns the custom error message
 func (e CustomError) ErrorMessage() string {
 	return e.message
 }
 
+// Meta returns the error metadata (nil if none)
+func (e CustomError) Meta() map[string]any {
+	if e.meta == nil {
+		return nil
+	}
+	return *e.meta
+}
+
 // Type returns the error type string constant
 func (e CustomError) Type() string {
 	return e.errorType
@@ -33,6 +56,60 @@ func (e CustomError) Unwrap() error {
 	return e.sourceErr
 }
 
+// WithCode returns a copy of a CustomError (or of an error wrapping one, keeping its wrap chain) with the given error
+// code (e.g. WithCode(err, "example_title_taken")). Other errors are returned unchanged
+func WithCode(err error, code string) error {
+	return replace(err, func(e *CustomError) bool {
+		e.code = code
+		return true
+	})
+}
+
+// WithMeta returns a copy of a CustomError (or of an error wrapping one, keeping its wrap chain) with the given
+// metadata added to its existing metadata. Other errors (and empty metadata) are returned unchanged
+func WithMeta(err error, meta map[string]any) error {
+	return replace(err, func(e *CustomError) bool {
+		if len(meta) == 0 {
+			return false
+		}
+		merged := make(map[string]any, len(e.Meta())+len(meta))
+		maps.Copy(merged, e.Meta())
+		maps.Copy(merged, meta)
+		e.meta = &merged
+		return true
+	})
+}
+
+// replace returns err with a modified copy of its (first wrapped) CustomError, unless fn reports no change. Errors
+// wrapping a CustomError are rewrapped, so that the copy is found first by errors.As while the error message and the
+// original wrap chain (e.g. for errors.Is) are kept
+func replace(err error, fn func(e *CustomError) bool) error {
+	var e CustomError
+	if !errors.As(err, &e) || !fn(&e) {
+		return err
+	}
+	if _, ok := err.(CustomError); ok {
+		return e
+	}
+	return rewrappedError{custom: e, err: err}
+}
+
+// rewrappedError defines an error wrapping a CustomError, with the CustomError replaced by a modified copy
+type rewrappedError struct {
+	custom CustomError
+	err    error
+}
+
+// Error returns the message of the original error
+func (e rewrappedError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the modified CustomError (searched first) and the original error
+func (e rewrappedError) Unwrap() []error {
+	return []error{e.custom, e.err}
+}
+
 // wrapErrorf
 func wrapErrorf(err error, errtype, message string, a ...any) error {
 	if message == "" {
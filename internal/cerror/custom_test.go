@@ -0,0 +1,105 @@
+package cerror
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type ModifySetup struct {
+	Name        string
+	Description string
+	Err         func(source error) error
+	Expected    ModifyExpected
+}
+
+// ModifyExpected defines the expected error message, and whether a CustomError is expected to be found
+type ModifyExpected struct {
+	Custom  bool
+	Message string
+}
+
+// Test_WithCode_WithMeta verifies that the code and metadata of CustomErrors are replaced, including CustomErrors
+// wrapped by other errors (keeping their message and wrap chain), and that other errors are returned unchanged
+func Test_WithCode_WithMeta(t *testing.T) {
+	source := errors.New("duplicate key")
+
+	tests := []ModifySetup{
+		{
+			Name:        "custom",
+			Description: "modifies custom errors",
+			Err:         func(source error) error { return NewConflictError(source, "title taken") },
+			Expected:    ModifyExpected{Custom: true, Message: "title taken: duplicate key"},
+		},
+		{
+			Name:        "wrapped",
+			Description: "modifies wrapped custom errors, keeping the wrap chain",
+			Err: func(source error) error {
+				return fmt.Errorf("example create error: %w", NewConflictError(source, "title taken"))
+			},
+			Expected: ModifyExpected{Custom: true, Message: "example create error: title taken: duplicate key"},
+		},
+		{
+			Name:        "wrapped_twice",
+			Description: "modifies custom errors wrapped more than once",
+			Err: func(source error) error {
+				return fmt.Errorf("service: %w", fmt.Errorf("repo: %w", NewConflictError(source, "title taken")))
+			},
+			Expected: ModifyExpected{Custom: true, Message: "service: repo: title taken: duplicate key"},
+		},
+		{
+			Name:        "other",
+			Description: "returns other errors unchanged",
+			Err:         func(source error) error { return fmt.Errorf("wrapped: %w", source) },
+			Expected:    ModifyExpected{Custom: false, Message: "wrapped: duplicate key"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			original := tc.Err(source)
+			err := WithMeta(WithCode(original, "example_title_taken"), map[string]any{"field": "title"})
+			err = WithMeta(err, map[string]any{"attempt": 1})
+
+			if err.Error() != tc.Expected.Message {
+				t.Errorf("expected '%s', actual '%s'", tc.Expected.Message, err.Error())
+			}
+			if !errors.Is(err, source) {
+				t.Errorf("expected source error in wrap chain")
+			}
+
+			var custom CustomError
+			if found := errors.As(err, &custom); found != tc.Expected.Custom {
+				t.Fatalf("expected custom error '%t', actual '%t'", tc.Expected.Custom, found)
+			}
+			if !tc.Expected.Custom {
+				if err != original {
+					t.Errorf("expected error to be unchanged")
+				}
+				return
+			}
+
+			if custom.Code() != "example_title_taken" {
+				t.Errorf("expected '%s', actual '%s'", "example_title_taken", custom.Code())
+			}
+			expected := map[string]any{"field": "title", "attempt": 1}
+			if !reflect.DeepEqual(custom.Meta(), expected) {
+				t.Errorf("expected '%v', actual '%v'", expected, custom.Meta())
+			}
+
+			// the original error is not modified
+			errors.As(original, &custom)
+			if custom.Code() != ErrorCode.Conflict || custom.Meta() != nil {
+				t.Errorf("expected original error to be unchanged, actual code '%s' meta '%v'", custom.Code(), custom.Meta())
+			}
+		})
+	}
+
+	t.Run("empty_meta", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", NewConflictError(nil, "title taken"))
+		if WithMeta(err, nil) != err {
+			t.Errorf("expected error to be unchanged for empty metadata")
+		}
+	})
+}
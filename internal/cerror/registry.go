@@ -2,22 +2,62 @@ package cerror
 
 // ErrorRegistry defines a registry for all errors to be used across the application
 type ErrorRegistry struct {
-	Conflict       string
-	Forbidden      string
-	InternalServer string
-	NotFound       string
-	Unauthorized   string
-	Validation     string
+	Conflict             string
+	Forbidden            string
+	InternalServer       string
+	NotAcceptable        string
+	NotFound             string
+	PayloadTooLarge      string
+	Timeout              string
+	TooManyRequests      string
+	Unauthorized         string
+	UnsupportedMediaType string
+	Validation           string
 }
 
 // ErrorType exposes constants for all error types
 var ErrorType = ErrorRegistry{
-	Conflict:       "ConflictError",
-	Forbidden:      "ForbiddenError",
-	InternalServer: "InternalServerError",
-	NotFound:       "NotFoundError",
-	Unauthorized:   "UnauthorizedError",
-	Validation:     "ValidationError",
+	Conflict:             "ConflictError",
+	Forbidden:            "ForbiddenError",
+	InternalServer:       "InternalServerError",
+	NotAcceptable:        "NotAcceptableError",
+	NotFound:             "NotFoundError",
+	PayloadTooLarge:      "PayloadTooLargeError",
+	Timeout:              "TimeoutError",
+	TooManyRequests:      "TooManyRequestsError",
+	Unauthorized:         "UnauthorizedError",
+	UnsupportedMediaType: "UnsupportedMediaTypeError",
+	Validation:           "ValidationError",
+}
+
+// ErrorCode exposes the default (stable, machine-readable) error codes of all error types
+var ErrorCode = ErrorRegistry{
+	Conflict:             "conflict",
+	Forbidden:            "forbidden",
+	InternalServer:       "internal_server_error",
+	NotAcceptable:        "not_acceptable",
+	NotFound:             "not_found",
+	PayloadTooLarge:      "payload_too_large",
+	Timeout:              "timeout",
+	TooManyRequests:      "too_many_requests",
+	Unauthorized:         "unauthorized",
+	UnsupportedMediaType: "unsupported_media_type",
+	Validation:           "validation_failed",
+}
+
+// typeCodes maps error types to their default error codes
+var typeCodes = map[string]string{
+	ErrorType.Conflict:             ErrorCode.Conflict,
+	ErrorType.Forbidden:            ErrorCode.Forbidden,
+	ErrorType.InternalServer:       ErrorCode.InternalServer,
+	ErrorType.NotAcceptable:        ErrorCode.NotAcceptable,
+	ErrorType.NotFound:             ErrorCode.NotFound,
+	ErrorType.PayloadTooLarge:      ErrorCode.PayloadTooLarge,
+	ErrorType.Timeout:              ErrorCode.Timeout,
+	ErrorType.TooManyRequests:      ErrorCode.TooManyRequests,
+	ErrorType.Unauthorized:         ErrorCode.Unauthorized,
+	ErrorType.UnsupportedMediaType: ErrorCode.UnsupportedMediaType,
+	ErrorType.Validation:           ErrorCode.Validation,
 }
 
 // NewConflictError returns a new CustomError with the Conflict error type
@@ -38,18 +78,48 @@ func NewInternalServerError(err error, message string, a ...any) error {
 	return wrapErrorf(err, et, message, a...)
 }
 
+// NewNotAcceptableError returns a new CustomError with the NotAcceptable error type
+func NewNotAcceptableError(err error, message string, a ...any) error {
+	et := ErrorType.NotAcceptable
+	return wrapErrorf(err, et, message, a...)
+}
+
 // NewNotFoundError returns a new CustomError with the NotFound error type
 func NewNotFoundError(err error, message string, a ...any) error {
 	et := ErrorType.NotFound
 	return wrapErrorf(err, et, message, a...)
 }
 
+// NewPayloadTooLargeError returns a new CustomError with the PayloadTooLarge error type
+func NewPayloadTooLargeError(err error, message string, a ...any) error {
+	et := ErrorType.PayloadTooLarge
+	return wrapErrorf(err, et, message, a...)
+}
+
+// NewTimeoutError returns a new CustomError with the Timeout error type
+func NewTimeoutError(err error, message string, a ...any) error {
+	et := ErrorType.Timeout
+	return wrapErrorf(err, et, message, a...)
+}
+
+// NewTooManyRequestsError returns a new CustomError with the TooManyRequests error type
+func NewTooManyRequestsError(err error, message string, a ...any) error {
+	et := ErrorType.TooManyRequests
+	return wrapErrorf(err, et, message, a...)
+}
+
 // NewUnauthorizedError returns a new CustomError with the Unauthorized error type
 func NewUnauthorizedError(err error, message string, a ...any) error {
 	et := ErrorType.Unauthorized
 	return wrapErrorf(err, et, message, a...)
 }
 
+// NewUnsupportedMediaTypeError returns a new CustomError with the UnsupportedMediaType error type
+func NewUnsupportedMediaTypeError(err error, message string, a ...any) error {
+	et := ErrorType.UnsupportedMediaType
+	return wrapErrorf(err, et, message, a...)
+}
+
 // NewValidationError returns a new CustomError with the Validation error type
 func NewValidationError(err error, message string, a ...any) error {
 	et := ErrorType.Validation
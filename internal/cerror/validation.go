@@ -0,0 +1,37 @@
+package cerror
+
+import "strings"
+
+// FieldError defines a single invalid request field, located by a JSON pointer (request body), a query parameter
+// name or a request header name
+type FieldError struct {
+	// Code defines the stable machine-readable error code (e.g. validation_required), defaulting to
+	// ErrorCode.Validation
+	Code      string
+	Detail    string
+	Header    string
+	Parameter string
+	Pointer   string
+}
+
+// FieldErrors defines all invalid fields of a request, wrapped by a validation error (e.g.
+// NewValidationError(errs, "request body validation error"))
+type FieldErrors []FieldError
+
+// Error returns all field errors as a single message
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fe := range e {
+		switch {
+		case fe.Pointer != "":
+			messages = append(messages, fe.Pointer+": "+fe.Detail)
+		case fe.Parameter != "":
+			messages = append(messages, fe.Parameter+": "+fe.Detail)
+		case fe.Header != "":
+			messages = append(messages, fe.Header+": "+fe.Detail)
+		default:
+			messages = append(messages, fe.Detail)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jasonsites/gosk/internal/resolver"
+	"github.com/spf13/cobra"
+)
+
+// newConfigCommand returns the `config` command and its subcommands
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect application configuration",
+	}
+
+	printCmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print the resolved configuration (with secrets redacted)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resolve(func() error {
+				r := resolver.NewResolver(cmd.Context(), nil)
+
+				// secrets are redacted by config.Secret json marshaling
+				b, err := json.MarshalIndent(r.Config(), "", "  ")
+				if err != nil {
+					return fmt.Errorf("configuration marshal error: %w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(b))
+
+				return nil
+			})
+		},
+	}
+
+	validateCmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the resolved configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resolve(func() error {
+				resolver.NewResolver(cmd.Context(), nil).Config()
+				fmt.Fprintln(cmd.OutOrStdout(), "configuration valid")
+
+				return nil
+			})
+		},
+	}
+
+	cmd.AddCommand(printCmd, validateCmd)
+
+	return cmd
+}
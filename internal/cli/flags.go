@@ -0,0 +1,15 @@
+package cli
+
+import (
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// bindFlags binds flags to configuration keys, so that flags (when set) override all other config sources
+func bindFlags(flags *pflag.FlagSet, keys map[string]string) {
+	for key, name := range keys {
+		if err := viper.BindPFlag(key, flags.Lookup(name)); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jasonsites/gosk/internal/database"
+	"github.com/jasonsites/gosk/internal/resolver"
+	"github.com/spf13/cobra"
+)
+
+// newMigrateCommand returns the `migrate` command and its subcommands
+func newMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Manage database migrations",
+	}
+
+	// migrator runs fn with the resolver migrator, closing the db connection pool on return
+	migrator := func(cmd *cobra.Command, fn func(m *database.Migrator) error) error {
+		return resolve(func() error {
+			r := resolver.NewResolver(cmd.Context(), nil)
+			defer r.PostgreSQLClient().Close()
+			return fn(r.Migrator())
+		})
+	}
+
+	up := &cobra.Command{
+		Use:   "up [n]",
+		Short: "Apply all (or n) pending up migrations",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return migrator(cmd, func(m *database.Migrator) error {
+				if len(args) == 0 {
+					return m.Up(cmd.Context())
+				}
+				n, err := parseSteps(args[0])
+				if err != nil {
+					return err
+				}
+				return m.Steps(cmd.Context(), n)
+			})
+		},
+	}
+
+	down := &cobra.Command{
+		Use:   "down <n|all>",
+		Short: "Apply n (or all) down migrations",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return migrator(cmd, func(m *database.Migrator) error {
+				if args[0] == "all" {
+					return m.Down(cmd.Context())
+				}
+				n, err := parseSteps(args[0])
+				if err != nil {
+					return err
+				}
+				return m.Steps(cmd.Context(), -n)
+			})
+		},
+	}
+
+	steps := &cobra.Command{
+		Use:   "steps <n>",
+		Short: "Apply n up (n > 0) or down (n < 0) migrations",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			n, err := strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q", args[0])
+			}
+			return migrator(cmd, func(m *database.Migrator) error {
+				return m.Steps(cmd.Context(), n)
+			})
+		},
+	}
+
+	version := &cobra.Command{
+		Use:   "version",
+		Short: "Print the current schema version",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return migrator(cmd, func(m *database.Migrator) error {
+				v, dirty, err := m.Version(cmd.Context())
+				if err != nil {
+					return err
+				}
+				switch {
+				case v == database.NilVersion:
+					fmt.Fprintln(cmd.OutOrStdout(), "no migration")
+				case dirty:
+					fmt.Fprintf(cmd.OutOrStdout(), "%d (dirty)\n", v)
+				default:
+					fmt.Fprintln(cmd.OutOrStdout(), v)
+				}
+				return nil
+			})
+		},
+	}
+
+	force := &cobra.Command{
+		Use:   "force <version>",
+		Short: "Set the schema version (-1 for none) and clear the dirty state",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid version %q", args[0])
+			}
+			return migrator(cmd, func(m *database.Migrator) error {
+				return m.Force(cmd.Context(), v)
+			})
+		},
+	}
+
+	var dir string
+	create := &cobra.Command{
+		Use:   "create <name>",
+		Short: "Create empty up and down migration files (without connecting to the database)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			files, err := database.CreateMigration(dir, args[0], time.Now())
+			for _, file := range files {
+				fmt.Fprintln(cmd.OutOrStdout(), file)
+			}
+			return err
+		},
+	}
+	create.Flags().StringVar(&dir, "dir", "database/migrations", "migration directory")
+
+	cmd.AddCommand(up, down, steps, version, force, create)
+
+	return cmd
+}
+
+// parseSteps parses a positive number of migration steps
+func parseSteps(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid steps %q, must be a positive integer", arg)
+	}
+	return n, nil
+}
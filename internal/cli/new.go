@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jasonsites/gosk/internal/generator"
+	"github.com/spf13/cobra"
+)
+
+// newNewCommand returns the `new` command and its subcommands
+func newNewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "new",
+		Short: "Scaffold new application components",
+	}
+
+	moduleCmd := &cobra.Command{
+		Use:   "module <name>",
+		Short: "Scaffold a new CRUD module (e.g. gosk new module blog-post)",
+		Long: "Scaffold a new CRUD module from templates: entity and migration, request DTO, service hooks, router,\n" +
+			"resolver provider and integration tests, with its routes and controller registered. Must be run from the\n" +
+			"repository root.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			path, _ := cmd.Flags().GetString("path")
+
+			gen, err := generator.NewGenerator(&generator.GeneratorConfig{Root: root})
+			if err != nil {
+				return err
+			}
+
+			files, err := gen.Module(args[0], &generator.ModuleOptions{Path: path})
+			if err != nil {
+				return err
+			}
+
+			for _, f := range files {
+				fmt.Fprintln(cmd.OutOrStdout(), f)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "module generated (apply the new migration with `gosk migrate up`)")
+
+			return nil
+		},
+	}
+	moduleCmd.Flags().String("path", "", "resource collection path within the router namespace (default pluralized name)")
+	moduleCmd.Flags().String("root", ".", "repository root directory")
+
+	cmd.AddCommand(moduleCmd)
+
+	return cmd
+}
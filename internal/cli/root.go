@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/jasonsites/gosk/config"
+	"github.com/spf13/cobra"
+)
+
+// Execute runs the root command, returning the process exit code
+func Execute() int {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	return execute(ctx, NewRootCommand(), os.Stderr)
+}
+
+// execute runs the given command, writing a returned error to stderr, and returns the process exit code
+func execute(ctx context.Context, cmd *cobra.Command, stderr io.Writer) int {
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		fmt.Fprintf(stderr, "error: %s\n", err.Error())
+		return 1
+	}
+	return 0
+}
+
+// NewRootCommand returns the root command with all subcommands registered
+func NewRootCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "gosk",
+		Short:         "Go starter kit for modular backend applications",
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if path, _ := cmd.Flags().GetString("config"); path != "" {
+				config.SetConfigFile(path)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.String("config", "", "config file path (default /app/config/config.toml)")
+	flags.String("log-level", "", "log level (debug|info|warn|error)")
+	flags.String("log-format", "", "log format (json|styled)")
+	flags.String("database", "", "postgres database name")
+	bindFlags(flags, map[string]string{
+		"logger.level":      "log-level",
+		"logger.format":     "log-format",
+		"postgres.database": "database",
+	})
+
+	cmd.AddCommand(
+		newConfigCommand(),
+		newMigrateCommand(),
+		newNewCommand(),
+		newSeedCommand(),
+		newServeCommand(),
+	)
+
+	return cmd
+}
+
+// resolve calls fn, converting resolver load panics into errors
+func resolve(fn func() error) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			if e, ok := rec.(error); ok {
+				err = e
+				return
+			}
+			err = fmt.Errorf("%v", rec)
+		}
+	}()
+
+	return fn()
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"github.com/jasonsites/gosk/internal/database"
+	"github.com/jasonsites/gosk/internal/resolver"
+	"github.com/spf13/cobra"
+)
+
+// newSeedCommand returns the `seed` command
+func newSeedCommand() *cobra.Command {
+	var (
+		env    string
+		seed   int64
+		upsert bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Seed the database with the seed sets for an environment",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return resolve(func() error {
+				r := resolver.NewResolver(cmd.Context(), nil)
+				defer r.PostgreSQLClient().Close()
+
+				if env == "" {
+					env = r.Config().App.Metadata.Environment
+				}
+
+				opts := &database.SeedOptions{
+					Environment: env,
+					Seed:        seed,
+					Upsert:      upsert,
+				}
+				return r.Seeder().Run(cmd.Context(), opts)
+			})
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&env, "env", "", "seed set environment (default app environment)")
+	flags.Int64Var(&seed, "seed", 1, "random seed for generated records (0 for non-deterministic)")
+	flags.BoolVar(&upsert, "upsert", false, "update existing records in place (idempotent)")
+
+	return cmd
+}
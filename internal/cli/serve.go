@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jasonsites/gosk/internal/resolver"
+	"github.com/jasonsites/gosk/internal/runtime"
+	"github.com/spf13/cobra"
+)
+
+// newServeCommand returns the `serve [http|worker]` command
+func newServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "serve [http|worker]",
+		Short:     "Run the application with the given entry (default http)",
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: []string{string(resolver.HTTP), string(resolver.Worker)},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry := resolver.HTTP
+			if len(args) > 0 {
+				entry = resolver.ResolverEntry(args[0])
+			}
+
+			runconf := &runtime.RunConfig{Entry: entry}
+			if _, err := runtime.NewRuntime(nil).Run(runconf); err != nil {
+				return fmt.Errorf("serve %s: %w", entry, err)
+			}
+			return nil
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.String("host", "", "http server host")
+	flags.Uint("port", 0, "http server port")
+	flags.String("namespace", "", "http router namespace")
+	flags.Bool("migrate", false, "apply pending migrations on startup")
+	bindFlags(flags, map[string]string{
+		"http.server.host":          "host",
+		"http.server.port":          "port",
+		"http.router.namespace":     "namespace",
+		"postgres.migrateOnStartup": "migrate",
+	})
+
+	return cmd
+}
@@ -0,0 +1,10 @@
+package database
+
+import "hash/fnv"
+
+// AdvisoryLockID returns a stable postgres advisory lock id for the given key
+func AdvisoryLockID(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
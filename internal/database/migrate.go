@@ -0,0 +1,318 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/logger"
+)
+
+// NilVersion is the schema version before any migration has been applied
+const NilVersion int64 = -1
+
+// ErrDirty is returned when a previous migration failed, and the schema version must be forced
+var ErrDirty = errors.New("database is dirty, fix and force version")
+
+// migrationFile matches migration file names (e.g. 1667109482_initial-schema.up.sql)
+var migrationFile = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// migrationName matches the names of created migrations (e.g. add-widget-index)
+var migrationName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Migration defines a single versioned migration
+type Migration struct {
+	Down    string
+	Name    string
+	Up      string
+	Version int64
+}
+
+// MigratorConfig defines the input to NewMigrator
+type MigratorConfig struct {
+	DBClient *pgxpool.Pool        `validate:"required"`
+	FS       fs.FS                `validate:"required"`
+	Logger   *logger.CustomLogger `validate:"required"`
+	// Path defines the directory of migration files within FS
+	Path string `validate:"required"`
+}
+
+// Migrator applies versioned sql migrations, tracking the schema version in the `schema_migrations`
+// table (compatible with the golang-migrate cli)
+type Migrator struct {
+	db         *pgxpool.Pool
+	logger     *logger.CustomLogger
+	migrations []Migration
+}
+
+// NewMigrator returns a new Migrator instance
+func NewMigrator(c *MigratorConfig) (*Migrator, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(c.FS, c.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Migrator{
+		db:         c.DBClient,
+		logger:     c.Logger,
+		migrations: migrations,
+	}
+
+	return m, nil
+}
+
+// Up applies all pending up migrations
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.Steps(ctx, len(m.migrations))
+}
+
+// Down applies all down migrations
+func (m *Migrator) Down(ctx context.Context) error {
+	return m.Steps(ctx, -len(m.migrations))
+}
+
+// Steps applies n up migrations (n > 0) or -n down migrations (n < 0)
+func (m *Migrator) Steps(ctx context.Context, n int) error {
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w (version %d)", ErrDirty, version)
+		}
+
+		i := m.index(version)
+		if i == -1 && version != NilVersion {
+			return fmt.Errorf("unknown schema version %d", version)
+		}
+
+		for ; n > 0 && i+1 < len(m.migrations); n-- {
+			i++
+			next := m.migrations[i]
+			if err := m.apply(ctx, conn, next.Version, next.Version, next.Up, "up", next.Name); err != nil {
+				return err
+			}
+		}
+		for ; n < 0 && i >= 0; n++ {
+			prev := NilVersion
+			if i > 0 {
+				prev = m.migrations[i-1].Version
+			}
+			curr := m.migrations[i]
+			if err := m.apply(ctx, conn, curr.Version, prev, curr.Down, "down", curr.Name); err != nil {
+				return err
+			}
+			i--
+		}
+
+		return nil
+	})
+}
+
+// Version returns the current schema version and dirty state
+func (m *Migrator) Version(ctx context.Context) (int64, bool, error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return NilVersion, false, err
+	}
+	defer conn.Release()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return NilVersion, false, err
+	}
+
+	return m.version(ctx, conn)
+}
+
+// Force sets the schema version without running any migrations, clearing the dirty state
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if version != NilVersion && m.index(version) == -1 {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			return setVersion(ctx, tx, version, false)
+		})
+	})
+}
+
+// apply runs a single migration, marking the schema dirty until the migration completes
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, from, to int64, sql, direction, name string) error {
+	m.logger.Log.Info(fmt.Sprintf("migrating %s %d_%s", direction, from, name))
+
+	if err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		return setVersion(ctx, tx, from, true)
+	}); err != nil {
+		return err
+	}
+
+	return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, sql); err != nil {
+			return fmt.Errorf("migration %d_%s (%s) error: %w", from, name, direction, err)
+		}
+		return setVersion(ctx, tx, to, false)
+	})
+}
+
+// index returns the position of the given version in the migration list (-1 for NilVersion)
+func (m *Migrator) index(version int64) int {
+	return slices.IndexFunc(m.migrations, func(mig Migration) bool {
+		return mig.Version == version
+	})
+}
+
+// version returns the current schema version and dirty state
+func (m *Migrator) version(ctx context.Context, conn *pgxpool.Conn) (int64, bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+
+	err := conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NilVersion, false, nil
+	}
+	if err != nil {
+		return NilVersion, false, err
+	}
+
+	return version, dirty, nil
+}
+
+// withLock runs fn on a dedicated connection while holding a session-level advisory lock,
+// so that only one migrator (across all replicas) runs at a time
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	id := AdvisoryLockID("schema_migrations:" + conn.Conn().Config().Database)
+	if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", id); err != nil {
+		return fmt.Errorf("migration lock error: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", id); err != nil {
+			m.logger.Log.Error(fmt.Sprintf("migration unlock error: %s", err.Error()))
+		}
+	}()
+
+	if err := ensureVersionTable(ctx, conn); err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+// ensureVersionTable creates the schema version table if it does not exist
+func ensureVersionTable(ctx context.Context, conn *pgxpool.Conn) error {
+	_, err := conn.Exec(ctx,
+		"CREATE TABLE IF NOT EXISTS schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)",
+	)
+	return err
+}
+
+// setVersion replaces the schema version row
+func setVersion(ctx context.Context, tx pgx.Tx, version int64, dirty bool) error {
+	if _, err := tx.Exec(ctx, "TRUNCATE schema_migrations"); err != nil {
+		return err
+	}
+	if version == NilVersion && !dirty {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ($1, $2)", version, dirty)
+	return err
+}
+
+// loadMigrations reads and sorts all migrations in the given directory
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("migration directory read error: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFile.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %q", match[1])
+		}
+
+		b, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("migration file read error: %w", err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Name: match[2], Version: version}
+			byVersion[version] = mig
+		}
+		if match[3] == "up" {
+			mig.Up = string(b)
+		} else {
+			mig.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		migrations = append(migrations, *mig)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+
+	return migrations, nil
+}
+
+// CreateMigration creates empty up and down migration files for the given name in dir, versioned by the given time
+// (unix seconds, as by the golang-migrate cli), returning the created file paths
+func CreateMigration(dir, name string, now time.Time) ([]string, error) {
+	if !migrationName.MatchString(name) {
+		return nil, fmt.Errorf("invalid migration name %q (letters, digits, '-' and '_')", name)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("migration directory create error: %w", err)
+	}
+
+	var files []string
+	for _, direction := range []string{"up", "down"} {
+		file := filepath.Join(dir, fmt.Sprintf("%d_%s.%s.sql", now.Unix(), name, direction))
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err != nil {
+			return files, fmt.Errorf("migration file create error: %w", err)
+		}
+		if err := f.Close(); err != nil {
+			return files, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
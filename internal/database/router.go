@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/logger"
+)
+
+// DB defines the query interface shared by pools and transactions
+type DB interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// primaryContextKey defines the context key used for flagging read-your-writes operations
+type primaryContextKey struct{}
+
+// WithPrimary returns a context which routes all reads to the primary (read-your-writes)
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// UsePrimary reports whether the given context has been flagged for read-your-writes
+func UsePrimary(ctx context.Context) bool {
+	v, ok := ctx.Value(primaryContextKey{}).(bool)
+	return ok && v
+}
+
+// replicaPingTimeout defines the maximum duration of a single replica health check ping
+const replicaPingTimeout = 2 * time.Second
+
+// RouterConfig defines the input to NewRouter
+type RouterConfig struct {
+	HealthCheckPeriod time.Duration        `validate:"required"`
+	Logger            *logger.CustomLogger `validate:"required"`
+	Primary           *pgxpool.Pool        `validate:"required"`
+	Replicas          []*pgxpool.Pool
+}
+
+// Router routes writes to the primary and reads to healthy replicas, falling back to the primary. Replicas are
+// unhealthy until their first successful health check
+type Router struct {
+	healthCheckPeriod time.Duration
+	logger            *logger.CustomLogger
+	next              atomic.Uint64
+	primary           *pgxpool.Pool
+	replicas          []*replica
+}
+
+// replica tracks the health of a single read replica
+type replica struct {
+	checkedOn atomic.Pointer[time.Time]
+	healthy   atomic.Bool
+	pool      *pgxpool.Pool
+}
+
+// ReplicaStatus defines the health status of a single read replica
+type ReplicaStatus struct {
+	CheckedOn *time.Time `json:"checked_on"`
+	Healthy   bool       `json:"healthy"`
+	Host      string     `json:"host"`
+}
+
+// NewRouter returns a new Router instance
+func NewRouter(c *RouterConfig) (*Router, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	replicas := make([]*replica, 0, len(c.Replicas))
+	for _, pool := range c.Replicas {
+		replicas = append(replicas, &replica{pool: pool})
+	}
+
+	router := &Router{
+		healthCheckPeriod: c.HealthCheckPeriod,
+		logger:            c.Logger,
+		primary:           c.Primary,
+		replicas:          replicas,
+	}
+
+	return router, nil
+}
+
+// Primary returns the primary pool
+func (r *Router) Primary() *pgxpool.Pool {
+	return r.primary
+}
+
+// Read returns the next healthy replica (round robin), or the primary if the context is flagged
+// for read-your-writes or no replica is healthy
+func (r *Router) Read(ctx context.Context) DB {
+	n := len(r.replicas)
+	if n == 0 || UsePrimary(ctx) {
+		return r.primary
+	}
+
+	start := r.next.Add(1)
+	for i := range n {
+		rep := r.replicas[(start+uint64(i))%uint64(n)]
+		if rep.healthy.Load() {
+			return rep.pool
+		}
+	}
+
+	return r.primary
+}
+
+// Write returns the primary
+func (r *Router) Write(ctx context.Context) DB {
+	return r.primary
+}
+
+// Status returns the health status of all replicas
+func (r *Router) Status() []ReplicaStatus {
+	status := make([]ReplicaStatus, 0, len(r.replicas))
+	for _, rep := range r.replicas {
+		status = append(status, ReplicaStatus{
+			CheckedOn: rep.checkedOn.Load(),
+			Healthy:   rep.healthy.Load(),
+			Host:      rep.pool.Config().ConnConfig.Host,
+		})
+	}
+	return status
+}
+
+// Monitor checks replica health on each health check period until the given context is done
+func (r *Router) Monitor(ctx context.Context) {
+	if len(r.replicas) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(r.healthCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		r.checkReplicas(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkReplicas pings all replicas concurrently, logging health transitions
+func (r *Router) checkReplicas(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, rep := range r.replicas {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.checkReplica(ctx, rep)
+		}()
+	}
+	wg.Wait()
+}
+
+// checkReplica pings a single replica (within the ping timeout or health check period, whichever is shorter),
+// logging its initial health and health transitions
+func (r *Router) checkReplica(ctx context.Context, rep *replica) {
+	pingCtx, cancel := context.WithTimeout(ctx, min(replicaPingTimeout, r.healthCheckPeriod))
+	err := rep.pool.Ping(pingCtx)
+	cancel()
+
+	now := time.Now()
+	first := rep.checkedOn.Swap(&now) == nil
+
+	host := rep.pool.Config().ConnConfig.Host
+	healthy := err == nil
+	if prev := rep.healthy.Swap(healthy); prev != healthy || first {
+		if healthy {
+			r.logger.Log.Info(fmt.Sprintf("replica %s healthy", host))
+		} else {
+			r.logger.Log.Warn(fmt.Sprintf("replica %s unhealthy, falling back: %s", host, err.Error()))
+		}
+	}
+}
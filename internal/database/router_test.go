@@ -0,0 +1,138 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/logger"
+)
+
+// testPool returns a lazily connected pool for the given host, refusing connections on port 1
+func testPool(t *testing.T, host string) *pgxpool.Pool {
+	t.Helper()
+
+	pool, err := pgxpool.New(context.Background(), "host="+host+" port=1 user=postgres dbname=gosk sslmode=disable connect_timeout=1")
+	if err != nil {
+		t.Fatalf("pool config error: %+v\n", err)
+	}
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// testRouter returns a router with the given number of (unreachable) replicas
+func testRouter(t *testing.T, replicas int) *Router {
+	t.Helper()
+
+	pools := make([]*pgxpool.Pool, 0, replicas)
+	for range replicas {
+		pools = append(pools, testPool(t, "127.0.0.1"))
+	}
+
+	router, err := NewRouter(&RouterConfig{
+		HealthCheckPeriod: time.Minute,
+		Logger:            &logger.CustomLogger{Level: logger.LevelError, Log: slog.Default()},
+		Primary:           testPool(t, "127.0.0.1"),
+		Replicas:          pools,
+	})
+	if err != nil {
+		t.Fatalf("router load error: %+v\n", err)
+	}
+
+	return router
+}
+
+// Test_Router_Read verifies read routing to healthy replicas (round robin), and to the primary for read-your-writes
+// or when no replica is healthy
+func Test_Router_Read(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("no_replicas", func(t *testing.T) {
+		r := testRouter(t, 0)
+		if r.Read(ctx) != r.primary {
+			t.Errorf("expected reads to route to the primary")
+		}
+	})
+
+	t.Run("initially_unhealthy", func(t *testing.T) {
+		r := testRouter(t, 2)
+		if r.Read(ctx) != r.primary {
+			t.Errorf("expected reads to route to the primary before replica health checks")
+		}
+		for _, status := range r.Status() {
+			if status.Healthy || status.CheckedOn != nil {
+				t.Errorf("expected unchecked unhealthy replica, actual %+v", status)
+			}
+		}
+	})
+
+	t.Run("round_robin", func(t *testing.T) {
+		r := testRouter(t, 3)
+		for _, rep := range r.replicas {
+			rep.healthy.Store(true)
+		}
+
+		seen := map[DB]int{}
+		for range 6 {
+			seen[r.Read(ctx)]++
+		}
+		for _, rep := range r.replicas {
+			if seen[rep.pool] != 2 {
+				t.Errorf("expected 2 reads per replica, actual %v", seen)
+			}
+		}
+	})
+
+	t.Run("skip_unhealthy", func(t *testing.T) {
+		r := testRouter(t, 3)
+		r.replicas[1].healthy.Store(true)
+
+		for range 3 {
+			if r.Read(ctx) != r.replicas[1].pool {
+				t.Errorf("expected reads to route to the healthy replica")
+			}
+		}
+	})
+
+	t.Run("read_your_writes", func(t *testing.T) {
+		r := testRouter(t, 2)
+		for _, rep := range r.replicas {
+			rep.healthy.Store(true)
+		}
+
+		if r.Read(WithPrimary(ctx)) != r.primary {
+			t.Errorf("expected flagged reads to route to the primary")
+		}
+		if r.Write(ctx) != r.primary {
+			t.Errorf("expected writes to route to the primary")
+		}
+	})
+}
+
+// Test_Router_Failover verifies that replicas failing health checks are marked unhealthy (concurrently, within the
+// ping timeout) and that reads fall back to the primary
+func Test_Router_Failover(t *testing.T) {
+	ctx := context.Background()
+	r := testRouter(t, 3)
+	for _, rep := range r.replicas {
+		rep.healthy.Store(true)
+	}
+
+	start := time.Now()
+	r.checkReplicas(ctx)
+	if elapsed := time.Since(start); elapsed > replicaPingTimeout+time.Second {
+		t.Errorf("expected health checks within the ping timeout, actual %s", elapsed)
+	}
+
+	for _, status := range r.Status() {
+		if status.Healthy || status.CheckedOn == nil || status.Host != "127.0.0.1" {
+			t.Errorf("expected checked unhealthy replica, actual %+v", status)
+		}
+	}
+	if r.Read(ctx) != r.primary {
+		t.Errorf("expected reads to fall back to the primary")
+	}
+}
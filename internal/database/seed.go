@@ -0,0 +1,221 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	fake "github.com/brianvoe/gofakeit/v6"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/logger"
+	"gopkg.in/yaml.v3"
+)
+
+// SeedRecord defines a single table row as column values
+type SeedRecord map[string]any
+
+// SeedTable defines a table registered for seeding
+type SeedTable struct {
+	// Generate returns a generated record using the given (seeded) faker
+	Generate func(f *fake.Faker) SeedRecord
+	// Key defines the conflict target columns used in upsert mode (e.g. the primary key)
+	Key  []string `validate:"required,min=1"`
+	Name string   `validate:"required"`
+}
+
+// SeedSet defines records to load into a single registered table, either explicitly defined,
+// generated, or both
+type SeedSet struct {
+	Generate int          `json:"generate" yaml:"generate"`
+	Records  []SeedRecord `json:"records" yaml:"records"`
+	Table    string       `json:"table" yaml:"table"`
+}
+
+// SeedOptions defines the options for a single seeding run
+type SeedOptions struct {
+	// Environment selects the seed set directory (e.g. development)
+	Environment string
+	// Seed defines the random seed for generated records (0 for non-deterministic)
+	Seed int64
+	// Upsert updates conflicting records in place, so that repeated runs are idempotent
+	Upsert bool
+}
+
+// SeederConfig defines the input to NewSeeder
+type SeederConfig struct {
+	DBClient *pgxpool.Pool        `validate:"required"`
+	FS       fs.FS                `validate:"required"`
+	Logger   *logger.CustomLogger `validate:"required"`
+	// Path defines the directory of per-environment seed set directories within FS
+	Path   string      `validate:"required"`
+	Tables []SeedTable `validate:"dive"`
+}
+
+// Seeder loads generated or fixture-defined records (yaml/json) into registered tables
+type Seeder struct {
+	db     *pgxpool.Pool
+	fs     fs.FS
+	logger *logger.CustomLogger
+	path   string
+	tables map[string]SeedTable
+}
+
+// NewSeeder returns a new Seeder instance
+func NewSeeder(c *SeederConfig) (*Seeder, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]SeedTable, len(c.Tables))
+	for _, t := range c.Tables {
+		tables[t.Name] = t
+	}
+
+	seeder := &Seeder{
+		db:     c.DBClient,
+		fs:     c.FS,
+		logger: c.Logger,
+		path:   c.Path,
+		tables: tables,
+	}
+
+	return seeder, nil
+}
+
+// Run loads all seed sets for the configured environment
+func (s *Seeder) Run(ctx context.Context, opts *SeedOptions) error {
+	sets, err := s.Sets(opts.Environment)
+	if err != nil {
+		return err
+	}
+
+	return s.Load(ctx, opts, sets...)
+}
+
+// Sets reads all seed set files (*.yaml, *.yml, *.json) for the given environment, in file name order
+func (s *Seeder) Sets(environment string) ([]SeedSet, error) {
+	dir := path.Join(s.path, environment)
+
+	entries, err := fs.ReadDir(s.fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("seed directory read error (%s): %w", environment, err)
+	}
+
+	var sets []SeedSet
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		var unmarshal func([]byte, any) error
+		switch path.Ext(entry.Name()) {
+		case ".json":
+			unmarshal = json.Unmarshal
+		case ".yaml", ".yml":
+			unmarshal = yaml.Unmarshal
+		default:
+			continue
+		}
+
+		b, err := fs.ReadFile(s.fs, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("seed file read error: %w", err)
+		}
+
+		var fileSets []SeedSet
+		if err := unmarshal(b, &fileSets); err != nil {
+			return nil, fmt.Errorf("seed file %s parse error: %w", entry.Name(), err)
+		}
+		sets = append(sets, fileSets...)
+	}
+
+	return sets, nil
+}
+
+// Load inserts (or upserts) the given seed sets in a single transaction
+func (s *Seeder) Load(ctx context.Context, opts *SeedOptions, sets ...SeedSet) error {
+	faker := fake.New(opts.Seed)
+
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		for _, set := range sets {
+			table, ok := s.tables[set.Table]
+			if !ok {
+				return fmt.Errorf("seed table '%s' not registered", set.Table)
+			}
+
+			records := slices.Clone(set.Records)
+			if set.Generate > 0 {
+				if table.Generate == nil {
+					return fmt.Errorf("seed table '%s' does not support generated records", set.Table)
+				}
+				for range set.Generate {
+					records = append(records, table.Generate(faker))
+				}
+			}
+
+			for _, record := range records {
+				if err := s.insert(ctx, tx, table, record, opts.Upsert); err != nil {
+					return err
+				}
+			}
+
+			s.logger.Log.Info(fmt.Sprintf("seeded %d %s records", len(records), table.Name))
+		}
+
+		return nil
+	})
+}
+
+// insert inserts a single record, updating all non-key columns on conflict in upsert mode
+func (s *Seeder) insert(ctx context.Context, tx pgx.Tx, table SeedTable, record SeedRecord, upsert bool) error {
+	columns := slices.Sorted(maps.Keys(record))
+	if len(columns) == 0 {
+		return fmt.Errorf("empty %s seed record", table.Name)
+	}
+
+	var (
+		names   = make([]string, len(columns))
+		params  = make([]string, len(columns))
+		updates = make([]string, 0, len(columns))
+		values  = make([]any, len(columns))
+	)
+	for i, col := range columns {
+		name := pgx.Identifier{col}.Sanitize()
+		names[i] = name
+		params[i] = fmt.Sprintf("$%d", i+1)
+		values[i] = record[col]
+		if !slices.Contains(table.Key, col) {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", name, name))
+		}
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		pgx.Identifier{table.Name}.Sanitize(),
+		strings.Join(names, ","),
+		strings.Join(params, ","),
+	)
+	if upsert {
+		keys := make([]string, len(table.Key))
+		for i, k := range table.Key {
+			keys[i] = pgx.Identifier{k}.Sanitize()
+		}
+		action := "NOTHING"
+		if len(updates) > 0 {
+			action = "UPDATE SET " + strings.Join(updates, ",")
+		}
+		query = fmt.Sprintf("%s ON CONFLICT (%s) DO %s", query, strings.Join(keys, ","), action)
+	}
+
+	if _, err := tx.Exec(ctx, query, values...); err != nil {
+		return fmt.Errorf("%s seed error: %w", table.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,381 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/jasonsites/gosk/internal/app"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// moduleNamePattern defines valid module names (e.g. widget, blog_post, blog-post)
+var moduleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*([_-][a-z0-9]+)*$`)
+
+// GeneratorConfig defines the input to NewGenerator
+type GeneratorConfig struct {
+	// Now returns the time used for migration versions (default time.Now)
+	Now func() time.Time
+	// Root defines the repository root directory (containing go.mod)
+	Root string `validate:"required"`
+}
+
+// Generator scaffolds new application components from templates
+type Generator struct {
+	importPath string
+	now        func() time.Time
+	root       string
+}
+
+// NewGenerator returns a new Generator instance
+func NewGenerator(c *GeneratorConfig) (*Generator, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	importPath, err := readModulePath(filepath.Join(c.Root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	now := c.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	generator := &Generator{
+		importPath: importPath,
+		now:        now,
+		root:       c.Root,
+	}
+
+	return generator, nil
+}
+
+// ModuleOptions defines optional parameters for a generated module
+type ModuleOptions struct {
+	// Path defines the resource collection path within the router namespace (default the pluralized,
+	// hyphenated module name, e.g. blog-posts)
+	Path string
+}
+
+// ModuleNames defines the names derived from a module name (e.g. blog-post)
+type ModuleNames struct {
+	Import    string // go module import path (e.g. github.com/jasonsites/gosk)
+	Migration string // migration file name prefix (e.g. 1761202800_blog-post)
+	Package   string // go package name (e.g. blogpost)
+	Path      string // resource collection path (e.g. blog-posts)
+	Resource  string // JSON:API resource type (e.g. blog_post)
+	Table     string // database table (e.g. blog_post)
+	Title     string // human-readable name used in doc comments (e.g. blog post)
+	Type      string // exported go type name (e.g. BlogPost)
+}
+
+// moduleFiles defines the generated module files, as template name and destination path template
+var moduleFiles = []struct {
+	Template string
+	Path     string
+}{
+	{"entity.go.tmpl", "internal/modules/{{.Package}}/repo.interfaces.go"},
+	{"dto.go.tmpl", "internal/modules/{{.Package}}/model.dto.request.go"},
+	{"service.go.tmpl", "internal/modules/{{.Package}}/service.go"},
+	{"router.go.tmpl", "internal/modules/{{.Package}}/http.router.go"},
+	{"resolver.go.tmpl", "internal/resolver/{{.Package}}.go"},
+	{"migration.up.sql.tmpl", "database/migrations/{{.Migration}}.up.sql"},
+	{"migration.down.sql.tmpl", "database/migrations/{{.Migration}}.down.sql"},
+	{"test.common.go.tmpl", "test/integration/{{.Package}}/common.go"},
+	{"test.crud_test.go.tmpl", "test/integration/{{.Package}}/crud_test.go"},
+}
+
+// Module scaffolds a new CRUD module (entity, migration, DTO, hooks, router, resolver provider and integration
+// tests), registering its routes, controller and redacted fields, and returns the paths of all created and
+// modified files
+func (g *Generator) Module(name string, opts *ModuleOptions) ([]string, error) {
+	if opts == nil {
+		opts = &ModuleOptions{}
+	}
+
+	names, err := g.moduleNames(name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(filepath.Join(g.root, "internal/modules", names.Package)); !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("module '%s' already exists", names.Package)
+	}
+
+	// render all files before writing, so that template errors leave the tree untouched
+	files := make(map[string][]byte, len(moduleFiles))
+	paths := make([]string, 0, len(moduleFiles)+2)
+	for _, f := range moduleFiles {
+		path, err := render(f.Path, names)
+		if err != nil {
+			return nil, err
+		}
+		content, err := renderTemplate(f.Template, names)
+		if err != nil {
+			return nil, err
+		}
+		files[path] = content
+		paths = append(paths, path)
+	}
+
+	// fail before writing anything when any destination exists (e.g. module 'utils' and internal/resolver/utils.go)
+	var existing []string
+	for _, path := range paths {
+		if _, err := os.Stat(filepath.Join(g.root, path)); !errors.Is(err, fs.ErrNotExist) {
+			existing = append(existing, path)
+		}
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("module '%s' conflicts with existing files: %s", names.Package, strings.Join(existing, ", "))
+	}
+
+	edits, err := g.registrations(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range paths {
+		dest := filepath.Join(g.root, path)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(dest, files[path], 0o644); err != nil {
+			return nil, err
+		}
+	}
+	for path, content := range edits {
+		if err := os.WriteFile(filepath.Join(g.root, path), content, 0o644); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// moduleNames derives all module names from the given module name
+func (g *Generator) moduleNames(name string, opts *ModuleOptions) (*ModuleNames, error) {
+	if !moduleNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid module name '%s' (expected lowercase words separated by '-' or '_', e.g. blog-post)", name)
+	}
+
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' })
+
+	var typeName strings.Builder
+	for _, w := range words {
+		runes := []rune(w)
+		runes[0] = unicode.ToUpper(runes[0])
+		typeName.WriteString(string(runes))
+	}
+
+	plural := slices.Clone(words)
+	plural[len(plural)-1] = pluralize(plural[len(plural)-1])
+
+	path := opts.Path
+	if path == "" {
+		path = strings.Join(plural, "-")
+	}
+
+	names := &ModuleNames{
+		Import:    g.importPath,
+		Migration: fmt.Sprintf("%d_%s", g.now().Unix(), strings.Join(words, "-")),
+		Package:   strings.Join(words, ""),
+		Path:      strings.Trim(path, "/"),
+		Resource:  strings.Join(words, "_"),
+		Table:     strings.Join(words, "_"),
+		Title:     strings.Join(words, " "),
+		Type:      typeName.String(),
+	}
+
+	return names, nil
+}
+
+// registrations returns the router and resolver source files with the module controller, routes, operations and
+// redacted fields registered at their `gosk:` marker comments
+func (g *Generator) registrations(names *ModuleNames) (map[string][]byte, error) {
+	var (
+		routerPath   = "internal/http/httpserver/router.go"
+		resolverPath = "internal/resolver/common.go"
+		utilsPath    = "internal/resolver/utils.go"
+		controller   = names.Type + "Controller"
+	)
+
+	router, err := os.ReadFile(filepath.Join(g.root, routerPath))
+	if err != nil {
+		return nil, err
+	}
+	router, err = insertBefore(router, "// gosk:controllers", fmt.Sprintf("%s crud.Controller", controller))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", routerPath, err)
+	}
+	router, err = insertBefore(router, "// gosk:routes", fmt.Sprintf("%s.%sRouter(r, ns, c.%s)", names.Package, names.Type, controller))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", routerPath, err)
+	}
+	router, err = insertBefore(router, "// gosk:operations", fmt.Sprintf("ops = append(ops, c.%s.Operations()...)", controller))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", routerPath, err)
+	}
+	router = addImport(router, names.Import+"/internal/modules/"+names.Package, "")
+	router = addImport(router, names.Import+"/internal/modules/common/crud", "crud")
+
+	resolver, err := os.ReadFile(filepath.Join(g.root, resolverPath))
+	if err != nil {
+		return nil, err
+	}
+	resolver, err = insertBefore(resolver, "// gosk:controllers", fmt.Sprintf("%s: r.%s(),", controller, controller))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", resolverPath, err)
+	}
+
+	utils, err := os.ReadFile(filepath.Join(g.root, utilsPath))
+	if err != nil {
+		return nil, err
+	}
+	utils, err = insertBefore(utils, "// gosk:redaction", fmt.Sprintf("fields = append(fields, %s.%sRedactedFields()...)", names.Package, names.Type))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", utilsPath, err)
+	}
+	utils = addImport(utils, names.Import+"/internal/modules/"+names.Package, "")
+
+	edits := map[string][]byte{routerPath: router, resolverPath: resolver, utilsPath: utils}
+	for path, src := range edits {
+		formatted, err := format.Source(src)
+		if err != nil {
+			return nil, fmt.Errorf("%s: format error: %w", path, err)
+		}
+		edits[path] = formatted
+	}
+
+	return edits, nil
+}
+
+// render executes an inline template with the given module names
+func render(text string, names *ModuleNames) (string, error) {
+	t, err := template.New("").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, names); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// renderTemplate executes an embedded module template with the given module names, formatting go source
+func renderTemplate(name string, names *ModuleNames) ([]byte, error) {
+	t, err := template.ParseFS(templates, "templates/module/"+name)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, names); err != nil {
+		return nil, fmt.Errorf("template %s error: %w", name, err)
+	}
+
+	if !strings.HasSuffix(name, ".go.tmpl") {
+		return b.Bytes(), nil
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("template %s format error: %w", name, err)
+	}
+
+	return src, nil
+}
+
+// insertBefore inserts a line before the line containing the given marker, with matching indentation
+func insertBefore(src []byte, marker, line string) ([]byte, error) {
+	var (
+		out   bytes.Buffer
+		found bool
+	)
+
+	scanner := bufio.NewScanner(bytes.NewReader(src))
+	for scanner.Scan() {
+		text := scanner.Text()
+		if !found && strings.Contains(text, marker) {
+			indent := text[:len(text)-len(strings.TrimLeft(text, " \t"))]
+			out.WriteString(indent + line + "\n")
+			found = true
+		}
+		out.WriteString(text + "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("marker '%s' not found", marker)
+	}
+
+	return out.Bytes(), nil
+}
+
+// addImport adds an import (with optional name) to the first import block, unless already imported
+func addImport(src []byte, path, name string) []byte {
+	spec := fmt.Sprintf("%q", path)
+	if bytes.Contains(src, []byte(spec)) {
+		return src
+	}
+	if name != "" {
+		spec = name + " " + spec
+	}
+
+	start := bytes.Index(src, []byte("import ("))
+	if start < 0 {
+		return src
+	}
+	end := start + bytes.Index(src[start:], []byte("\n)"))
+
+	// gofmt sorts the appended import within the last import group
+	return append(src[:end:end], append([]byte("\n\t"+spec), src[end:]...)...)
+}
+
+// readModulePath reads the module path from the given go.mod file
+func readModulePath(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("go.mod read error (generator must be run from the repository root): %w", err)
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		if mod, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(mod), `"`), nil
+		}
+	}
+
+	return "", fmt.Errorf("module path not found in %s", path)
+}
+
+// pluralize returns the (naive) english plural of the given word
+func pluralize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "s"), strings.HasSuffix(word, "x"), strings.HasSuffix(word, "ch"), strings.HasSuffix(word, "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !strings.ContainsRune("aeiou", rune(word[len(word)-2])):
+		return word[:len(word)-1] + "ies"
+	default:
+		return word + "s"
+	}
+}
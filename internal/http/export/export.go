@@ -0,0 +1,193 @@
+package export
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+const (
+	// MediaTypeCSV defines the CSV export media type, with a header row of column names
+	MediaTypeCSV = "text/csv"
+	// MediaTypeNDJSON defines the newline-delimited JSON export media type, with one object per row
+	MediaTypeNDJSON = "application/x-ndjson"
+)
+
+// MediaTypes defines all export media types
+var MediaTypes = []string{MediaTypeCSV, MediaTypeNDJSON}
+
+const (
+	// flushInterval defines the number of rows written between response flushes
+	flushInterval = 100
+	// formulaPrefixes defines the leading characters of CSV cells evaluated as formulas by spreadsheet applications
+	formulaPrefixes = "=+-@\t\r"
+)
+
+// IsExport returns true for export media types
+func IsExport(mediaType string) bool {
+	return slices.Contains(MediaTypes, mediaType)
+}
+
+// Writer streams rows of named columns as a CSV or NDJSON response body, flushing the response every
+// flushInterval rows so that rows are not buffered. The response header is written with the first row (or on
+// Close), so that errors returned before any row is written can still be sent as error responses (see Started)
+type Writer struct {
+	columns   []string
+	csv       *csv.Writer
+	mediaType string
+	rows      int
+	started   bool
+	w         http.ResponseWriter
+}
+
+// NewWriter returns a new Writer of the given export media type and columns
+func NewWriter(w http.ResponseWriter, mediaType string, columns []string) (*Writer, error) {
+	if !IsExport(mediaType) {
+		return nil, fmt.Errorf("unsupported export media type '%s'", mediaType)
+	}
+
+	return &Writer{columns: columns, mediaType: mediaType, w: w}, nil
+}
+
+// Started returns true once the response header has been written
+func (ew *Writer) Started() bool {
+	return ew.started
+}
+
+// Write writes a single row of column values (in column order)
+func (ew *Writer) Write(values []any) error {
+	if err := ew.start(); err != nil {
+		return err
+	}
+
+	var err error
+	switch ew.mediaType {
+	case MediaTypeCSV:
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = Cell(value)
+		}
+		err = ew.csv.Write(record)
+	case MediaTypeNDJSON:
+		err = ew.writeJSON(values)
+	}
+	if err != nil {
+		return err
+	}
+
+	if ew.rows++; ew.rows%flushInterval == 0 {
+		return ew.flush()
+	}
+	return nil
+}
+
+// Close writes the response header (if no rows were written) and flushes buffered rows
+func (ew *Writer) Close() error {
+	if err := ew.start(); err != nil {
+		return err
+	}
+	return ew.flush()
+}
+
+// start writes the response header, and the CSV header row
+func (ew *Writer) start() error {
+	if ew.started {
+		return nil
+	}
+	ew.started = true
+
+	ew.w.Header().Set("Content-Type", ew.mediaType+"; charset=utf-8")
+	ew.w.WriteHeader(http.StatusOK)
+
+	if ew.mediaType == MediaTypeCSV {
+		ew.csv = csv.NewWriter(ew.w)
+		return ew.csv.Write(ew.columns)
+	}
+	return nil
+}
+
+// flush flushes buffered CSV rows and the response
+func (ew *Writer) flush() error {
+	if ew.csv != nil {
+		ew.csv.Flush()
+		if err := ew.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if f, ok := ew.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// writeJSON writes a single row as a JSON object line, with members in column order
+func (ew *Writer) writeJSON(values []any) error {
+	var line bytes.Buffer
+	line.WriteByte('{')
+	for i, column := range ew.columns {
+		if i > 0 {
+			line.WriteByte(',')
+		}
+		name, _ := json.Marshal(column)
+		value, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		line.Write(name)
+		line.WriteByte(':')
+		line.Write(value)
+	}
+	line.WriteString("}\n")
+
+	_, err := ew.w.Write(line.Bytes())
+	return err
+}
+
+// Cell formats a column value as CSV cell text, prefixing text starting with a formula character (=, +, -, @, tab or
+// carriage return) with a single quote, so that cells are not evaluated as formulas by spreadsheet applications
+// (numeric values are not prefixed)
+func Cell(value any) string {
+	text := Text(value)
+	if text == "" || !strings.ContainsRune(formulaPrefixes, rune(text[0])) {
+		return text
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return text
+	}
+	return "'" + text
+}
+
+// Text formats a column value as text, with nil values as empty text and text marshalers (e.g. time.Time,
+// uuid.UUID) as their text
+func Text(value any) string {
+	v := reflect.ValueOf(value)
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return ""
+	}
+	if v.Kind() == reflect.Pointer {
+		value = v.Elem().Interface()
+	}
+
+	switch value := value.(type) {
+	case encoding.TextMarshaler:
+		text, err := value.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	default:
+		return fmt.Sprint(value)
+	}
+}
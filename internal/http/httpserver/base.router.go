@@ -7,6 +7,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/jasonsites/gosk/internal/http/jsonapi"
 	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/openapi"
 )
 
 // BaseRouter only exists to easily verify a working app and should normally be removed
@@ -29,10 +30,25 @@ func BaseRouter(r *chi.Mux, ns string) {
 			},
 		}
 
-		jsonio.EncodeResponse(w, r, http.StatusOK, data)
+		jsonio.EncodeJSON(w, http.StatusOK, data)
 	}
 
 	r.Route(prefix, func(r chi.Router) {
 		r.Get("/", get)
 	})
 }
+
+// BaseOperations describes the routes registered by BaseRouter
+func BaseOperations(ns string) []openapi.Operation {
+	return []openapi.Operation{
+		{
+			Method:    http.MethodGet,
+			Pattern:   fmt.Sprintf("/%s", ns),
+			ID:        "base.get",
+			MediaType: "application/json",
+			Summary:   "Verify a working app (echoes request metadata)",
+			Tags:      []string{"base"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Schema: &openapi.Schema{Type: "object"}}},
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/openapi"
+	"github.com/jasonsites/gosk/internal/logger"
+)
+
+// DocsConfig defines the OpenAPI document configuration
+type DocsConfig struct {
+	Title   string `validate:"required"`
+	Version string `validate:"required"`
+	// Viewer enables the document viewer page at /{ns}/docs
+	Viewer bool
+	// ViewerIntegrity and ViewerScript define the viewer script URL and its subresource integrity hash
+	ViewerIntegrity string
+	ViewerScript    string
+}
+
+// DocsRouter implements a router for the OpenAPI document of all registered routes (/{ns}/openapi.json) and an
+// optional document viewer (/{ns}/docs). The document is built from the router on first request, so this
+// router is registered after all other routes
+func DocsRouter(r *chi.Mux, ns string, conf *DocsConfig, ops []openapi.Operation, logger *logger.CustomLogger) {
+	specPath := fmt.Sprintf("/%s/openapi.json", ns)
+	viewerPath := fmt.Sprintf("/%s/docs", ns)
+
+	ops = append(ops, DocsOperations(ns, conf)...)
+
+	var (
+		doc  *openapi.Document
+		err  error
+		once sync.Once
+	)
+	build := func() {
+		undocumented, unregistered, derr := openapi.Drift(r, ops)
+		if derr == nil && (len(undocumented) > 0 || len(unregistered) > 0) {
+			logger.Log.Warn(fmt.Sprintf("openapi drift: undocumented routes [%s], unregistered operations [%s]",
+				strings.Join(undocumented, ", "), strings.Join(unregistered, ", ")))
+		}
+
+		doc, err = openapi.NewDocument(&openapi.DocumentConfig{
+			Operations: ops,
+			Routes:     r,
+			Title:      conf.Title,
+			Version:    conf.Version,
+		})
+	}
+
+	spec := func(w http.ResponseWriter, r *http.Request) {
+		once.Do(build)
+		if err != nil {
+			jsonio.EncodeError(w, r, cerror.NewInternalServerError(err, "openapi document build error"))
+			return
+		}
+		jsonio.EncodeJSON(w, http.StatusOK, doc)
+	}
+
+	r.Get(specPath, spec)
+	if conf.Viewer {
+		r.Get(viewerPath, openapi.ViewerHandler(&openapi.ViewerConfig{
+			Integrity: conf.ViewerIntegrity,
+			Script:    conf.ViewerScript,
+			SpecURL:   specPath,
+			Title:     conf.Title,
+		}))
+	}
+}
+
+// DocsOperations describes the routes registered by DocsRouter
+func DocsOperations(ns string, conf *DocsConfig) []openapi.Operation {
+	ops := []openapi.Operation{
+		{
+			Method:    http.MethodGet,
+			Pattern:   fmt.Sprintf("/%s/openapi.json", ns),
+			ID:        "docs.openapi",
+			MediaType: "application/json",
+			Summary:   "Get the OpenAPI document",
+			Tags:      []string{"docs"},
+			Responses: []openapi.Response{{Status: http.StatusOK, Schema: &openapi.Schema{Type: "object"}}},
+		},
+	}
+	if conf.Viewer {
+		ops = append(ops, openapi.Operation{
+			Method:    http.MethodGet,
+			Pattern:   fmt.Sprintf("/%s/docs", ns),
+			ID:        "docs.viewer",
+			Summary:   "View the OpenAPI document (html)",
+			Tags:      []string{"docs"},
+			Responses: []openapi.Response{{Status: http.StatusOK}},
+		})
+	}
+
+	return ops
+}
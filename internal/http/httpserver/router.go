@@ -3,13 +3,17 @@ package httpserver
 import (
 	"compress/gzip"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"github.com/goddtriffin/helmet"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
 	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/http/openapi"
 	"github.com/jasonsites/gosk/internal/logger"
 	"github.com/jasonsites/gosk/internal/modules/example"
 	"github.com/jasonsites/gosk/internal/modules/health"
@@ -17,39 +21,130 @@ import (
 
 type ControllerRegistry struct {
 	ExampleController example.ExampleController
+	// gosk:controllers (generated module controllers are added above)
 }
 
 type RouterConfig struct {
-	Namespace string `validate:"required"`
+	// Admin defines the status providers of the admin status route, for details not exposed by Health (e.g. errors)
+	Admin map[string]health.StatusProvider
+	// Authenticator defines optional authentication middleware, which sets the verified mw.ClientIdentity of
+	// authenticated requests before rate limiting
+	Authenticator func(http.Handler) http.Handler
+	// BodyLimit defines the default maximum size (bytes) of request bodies, and BodyLimitRoutes route-specific
+	// maximum sizes (optional)
+	BodyLimit       int64 `validate:"required,min=1"`
+	BodyLimitRoutes []mw.BodyLimitRoute
+	CORS            *mw.CORS `validate:"required"`
+	// Docs enables the OpenAPI document routes (optional)
+	Docs *DocsConfig
+	// ErrorAbout defines the URL template of error object links.about members (optional)
+	ErrorAbout string
+	// ErrorFormat defines the default error format (optional, defaults to jsonio.ErrorFormatJSONAPI)
+	ErrorFormat jsonio.ErrorFormat
+	Health      map[string]health.StatusProvider
+	// JSONAPIExtensions and JSONAPIProfiles define the supported JSON:API extension and profile URIs (optional)
+	JSONAPIExtensions []string
+	JSONAPIProfiles   []string
+	Namespace         string `validate:"required"`
+	RateLimiter       *mw.RateLimiter
+	Recoverer         *mw.Recoverer `validate:"required"`
+	// TrustedProxies defines the IP addresses and CIDR ranges of trusted reverse proxies (optional, see mw.RealIP)
+	TrustedProxies []string
 }
 
 // configureMiddleware
-func configureMiddleware(conf *RouterConfig, r *chi.Mux, logger *logger.CustomLogger) {
+func configureMiddleware(conf *RouterConfig, r *chi.Mux, ops []openapi.Operation, logger *logger.CustomLogger) {
 	skipHealth := func(r *http.Request) bool {
 		return r.URL.Path == fmt.Sprintf("/%s/health", conf.Namespace)
 	}
+	// base, docs, health and admin status routes do not serve JSON:API documents
+	skipNegotiation := func(r *http.Request) bool {
+		switch strings.TrimPrefix(r.URL.Path, "/"+conf.Namespace) {
+		case "", "/", "/admin/status", "/docs", "/health", "/openapi.json":
+			return true
+		}
+		return false
+	}
 
 	r.Use(middleware.Compress(gzip.DefaultCompression))
+	r.Use(mw.RealIP(&mw.RealIPConfig{TrustedProxies: conf.TrustedProxies}))
 	r.Use(mw.Correlation(&mw.CorrelationConfig{Next: skipHealth}))
+	if conf.ErrorAbout != "" {
+		r.Use(mw.ErrorLinks(conf.ErrorAbout))
+	}
+	if conf.ErrorFormat != "" {
+		r.Use(mw.ErrorFormat(conf.ErrorFormat))
+	}
 	r.Use(mw.ResponseLogger(&mw.ResponseLoggerConfig{Logger: logger, Next: skipHealth}))
+	// CORS headers are set before all other middleware, so that browser clients can read error responses (e.g. 404,
+	// 413 and 429)
+	r.Use(conf.CORS.Handler)
+	r.Use(conf.Recoverer.Handler)
 	r.Use(helmet.Default().Secure)
 	r.Use(mw.RequestLogger(&mw.RequestLoggerConfig{Logger: logger, Next: skipHealth}))
+	if conf.Authenticator != nil {
+		r.Use(conf.Authenticator)
+	}
+	if conf.RateLimiter != nil {
+		r.Use(conf.RateLimiter.Handler)
+	}
+	r.Use(mw.BodyLimit(&mw.BodyLimitConfig{
+		Default: conf.BodyLimit,
+		Logger:  logger,
+		Routes:  conf.BodyLimitRoutes,
+	}))
 	r.Use(mw.NotFound)
-	r.Use(middleware.Recoverer)
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"http://*", "https://*"},
-		AllowedMethods:   []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
-		AllowCredentials: true,
-		MaxAge:           300,
+	r.Use(mw.ReadYourWrites(&mw.ReadYourWritesConfig{Next: skipHealth}))
+	r.Use(mw.ContentNegotiation(&mw.ContentNegotiationConfig{
+		Extensions: conf.JSONAPIExtensions,
+		Next:       skipNegotiation,
+		Profiles:   conf.JSONAPIProfiles,
+		Routes:     mediaTypeRoutes(ops),
 	}))
 }
 
 // registerRoutes
-func registerRoutes(conf *RouterConfig, r *chi.Mux, c *ControllerRegistry) {
+func registerRoutes(conf *RouterConfig, r *chi.Mux, c *ControllerRegistry, ops []openapi.Operation, logger *logger.CustomLogger) {
 	ns := conf.Namespace
 	BaseRouter(r, ns)
-	health.HealthRouter(r, ns)
+	health.HealthRouter(r, ns, conf.Health)
+	health.AdminRouter(r, ns, conf.Admin)
 	example.ExampleRouter(r, ns, c.ExampleController)
+	// gosk:routes (generated module routes are added above)
+
+	if conf.Docs != nil {
+		DocsRouter(r, ns, conf.Docs, ops, logger)
+	}
+}
+
+// routeOperations describes all routes registered by registerRoutes (other than docs routes) for the OpenAPI
+// document, with routes and operations kept in sync by the openapi integration test
+func routeOperations(conf *RouterConfig, c *ControllerRegistry) []openapi.Operation {
+	ns := conf.Namespace
+
+	ops := BaseOperations(ns)
+	ops = append(ops, health.HealthOperations(ns)...)
+	ops = append(ops, example.ExampleOperations(ns)...)
+	// gosk:operations (generated module operations are added above)
+
+	return ops
+}
+
+// mediaTypeRoutes returns the alternate request and (success) response media types of all described operations
+func mediaTypeRoutes(ops []openapi.Operation) []mw.MediaTypeRoute {
+	var routes []mw.MediaTypeRoute
+	for _, op := range ops {
+		route := mw.MediaTypeRoute{Method: op.Method, Path: op.Pattern}
+		route.Consumes = slices.Sorted(maps.Keys(op.RequestAlternates))
+		for _, res := range op.Responses {
+			if res.Status >= 200 && res.Status < 300 {
+				route.Produces = append(route.Produces, slices.Sorted(maps.Keys(res.Alternates))...)
+			}
+		}
+		if len(route.Consumes) > 0 || len(route.Produces) > 0 {
+			routes = append(routes, route)
+		}
+	}
+
+	return routes
 }
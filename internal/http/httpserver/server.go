@@ -33,8 +33,9 @@ func NewServer(c *ServerConfig) (*Server, error) {
 	}
 
 	mux := chi.NewRouter()
-	configureMiddleware(c.RouterConfig, mux, c.Logger)
-	registerRoutes(c.RouterConfig, mux, c.Controllers)
+	ops := routeOperations(c.RouterConfig, c.Controllers)
+	configureMiddleware(c.RouterConfig, mux, ops, c.Logger)
+	registerRoutes(c.RouterConfig, mux, c.Controllers, ops, c.Logger)
 
 	addr := fmt.Sprintf(":%s", strconv.FormatUint(uint64(c.Port), 10))
 	s := &Server{
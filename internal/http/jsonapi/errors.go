@@ -5,24 +5,31 @@ type ErrorResponse struct {
 	Errors []ErrorData `json:"errors"`
 }
 
-// ErrorData
+// ErrorData defines a JSON:API error object
 type ErrorData struct {
-	Source *ErrorSourcePointer `json:"source,omitempty"`
-	Title  string              `json:"title"`
-	Detail string              `json:"detail"`
+	// ID defines the unique identifier of the error occurrence (the request trace id)
+	ID    string      `json:"id,omitempty"`
+	Links *ErrorLinks `json:"links,omitempty"`
+	// Status defines the HTTP status code, as a string value (e.g. "400")
+	Status string `json:"status"`
+	// Code defines the stable machine-readable error code (e.g. validation_required)
+	Code   string         `json:"code"`
+	Title  string         `json:"title"`
+	Detail string         `json:"detail"`
+	Source *ErrorSource   `json:"source,omitempty"`
+	Meta   map[string]any `json:"meta,omitempty"`
 }
 
-// // ErrorSourcePointer
-// type ErrorSourceHeader struct {
-// 	Header string `json:"header,omitempty"`
-// }
-
-// // ErrorSourceParameter
-// type ErrorSourceParameter struct {
-// 	Parameter string `json:"parameter,omitempty"`
-// }
+// ErrorLinks defines error object links
+type ErrorLinks struct {
+	// About links to further details about the error code
+	About string `json:"about,omitempty"`
+}
 
-// ErrorSourcePointer
-type ErrorSourcePointer struct {
-	Pointer string `json:"pointer,omitempty"`
+// ErrorSource locates the cause of an error within the request, by JSON pointer to a request body field
+// (e.g. /data/attributes/title), by query parameter name (e.g. page[limit]) or by request header name
+type ErrorSource struct {
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Header    string `json:"header,omitempty"`
 }
@@ -0,0 +1,26 @@
+package jsonapi
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ParseFieldset parses a comma-separated sparse fieldset query parameter value (e.g. fields[examples]=title,status),
+// rejecting fields not present in allowed
+func ParseFieldset(raw string, allowed []string) ([]string, error) {
+	var fields []string
+
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if !slices.Contains(allowed, field) {
+			return nil, fmt.Errorf("unsupported field '%s'", field)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,119 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Links defines top-level, resource and relationship links
+type Links struct {
+	Self    string `json:"self,omitempty"`
+	Related string `json:"related,omitempty"`
+}
+
+// ResourceIdentifier identifies a single resource (resource linkage)
+type ResourceIdentifier struct {
+	Type string    `json:"type" validate:"required"`
+	ID   uuid.UUID `json:"id" validate:"required"`
+}
+
+// Relationship defines a resource relationship, with resource linkage (Data) only when loaded
+// (e.g. when included), so that unloaded relationships are represented by links alone
+type Relationship struct {
+	Links *Links          `json:"links,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// ToOne returns a to-one relationship with resource linkage (null for a nil identifier)
+func ToOne(links *Links, id *ResourceIdentifier) Relationship {
+	data, _ := json.Marshal(id)
+	return Relationship{Links: links, Data: data}
+}
+
+// ToMany returns a to-many relationship with resource linkage (an empty array for no identifiers)
+func ToMany(links *Links, ids []ResourceIdentifier) Relationship {
+	if ids == nil {
+		ids = []ResourceIdentifier{}
+	}
+	data, _ := json.Marshal(ids)
+	return Relationship{Links: links, Data: data}
+}
+
+// RelationshipResponse defines a relationship endpoint response (e.g. /examples/{id}/relationships/tags)
+type RelationshipResponse struct {
+	Links *Links `json:"links,omitempty"`
+	Data  any    `json:"data"`
+}
+
+// RelationshipRequestBody defines a to-many relationship request body
+type RelationshipRequestBody struct {
+	Data []ResourceIdentifier `json:"data" validate:"required,dive"`
+}
+
+// Included collects unique included resources (by type and id), in insertion order
+type Included struct {
+	keys      map[string]struct{}
+	resources []ResponseResource
+}
+
+// Add adds the given resources, skipping resources which have already been included
+func (in *Included) Add(resources ...ResponseResource) {
+	if in.keys == nil {
+		in.keys = make(map[string]struct{})
+	}
+	for _, res := range resources {
+		key := res.Type + ":" + res.ID.String()
+		if _, ok := in.keys[key]; ok {
+			continue
+		}
+		in.keys[key] = struct{}{}
+		in.resources = append(in.resources, res)
+	}
+}
+
+// Resources returns all included resources
+func (in *Included) Resources() []ResponseResource {
+	return in.resources
+}
+
+// IncludePaths defines the parsed relationship paths of an include query parameter
+// (e.g. include=tags,author.profile), keyed by the full dotted path
+type IncludePaths map[string]struct{}
+
+// Has returns true if the given relationship path is included
+func (p IncludePaths) Has(path string) bool {
+	_, ok := p[path]
+	return ok
+}
+
+// ParseInclude parses a comma-separated include query parameter value, rejecting relationship paths
+// deeper than maxDepth or not present in allowed. Intermediate paths are implicitly included
+// (include=a.b includes a), as required by the spec
+func ParseInclude(raw string, maxDepth int, allowed []string) (IncludePaths, error) {
+	paths := make(IncludePaths)
+
+	for _, path := range strings.Split(raw, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+
+		segments := strings.Split(path, ".")
+		if len(segments) > maxDepth {
+			return nil, fmt.Errorf("include path '%s' exceeds maximum depth %d", path, maxDepth)
+		}
+		if !slices.Contains(allowed, path) {
+			return nil, fmt.Errorf("unsupported include path '%s'", path)
+		}
+
+		for i := range segments {
+			paths[strings.Join(segments[:i+1], ".")] = struct{}{}
+		}
+	}
+
+	return paths, nil
+}
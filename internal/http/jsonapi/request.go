@@ -1,5 +1,8 @@
 package jsonapi
 
+// MediaType defines the JSON:API media type
+const MediaType = "application/vnd.api+json"
+
 // Envelope
 type Envelope map[string]any
 
@@ -7,24 +7,38 @@ import (
 
 // Response
 type Response struct {
-	Meta *ResponseMetadata `json:"meta"`
-	Data any               `json:"data"`
+	Meta     *ResponseMetadata  `json:"meta"`
+	Links    *Links             `json:"links,omitempty"`
+	Data     any                `json:"data"`
+	Included []ResponseResource `json:"included,omitempty"`
 }
 
 // ResponseMetadata
 type ResponseMetadata struct {
 	Filter *query.FilterMetadata `json:"filter,omitempty"`
 	Page   query.PageMetadata    `json:"page,omitempty"`
+	Search *string               `json:"q,omitempty"`
 	Sort   any                   `json:"sort,omitempty"`
 }
 
 // Resource
 type ResponseResource struct {
-	Type       string            `json:"type"`
-	ID         uuid.UUID         `json:"id"`
-	Meta       *ResourceMetadata `json:"meta,omitempty"`
-	Attributes any               `json:"attributes"`
+	Type          string                  `json:"type"`
+	ID            uuid.UUID               `json:"id"`
+	Meta          *ResourceMetadata       `json:"meta,omitempty"`
+	Attributes    any                     `json:"attributes"`
+	Relationships map[string]Relationship `json:"relationships,omitempty"`
+	Links         *Links                  `json:"links,omitempty"`
 }
 
 // ResourceMetadata
-type ResourceMetadata struct{}
+type ResourceMetadata struct {
+	Search *SearchMetadata `json:"search,omitempty"`
+}
+
+// SearchMetadata defines the full-text search match of a single resource
+type SearchMetadata struct {
+	// Highlights defines matched attribute snippets, with matches wrapped in <mark></mark>
+	Highlights map[string]string `json:"highlights,omitempty"`
+	Rank       float32           `json:"rank"`
+}
@@ -1,110 +1,210 @@
 package jsonio
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/invopop/validation"
 	cerror "github.com/jasonsites/gosk/internal/cerror"
 	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/http/problem"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/validate"
 )
 
-// HTTPStatusMap maps custom error types to relevant HTTP status codes
-var HTTPStatusMap = map[string]int{
-	cerror.ErrorType.Conflict:       http.StatusConflict,
-	cerror.ErrorType.Forbidden:      http.StatusForbidden,
-	cerror.ErrorType.InternalServer: http.StatusInternalServerError,
-	cerror.ErrorType.NotFound:       http.StatusNotFound,
-	cerror.ErrorType.Unauthorized:   http.StatusUnauthorized,
-	cerror.ErrorType.Validation:     http.StatusBadRequest,
+// ErrorFormat defines the representation of error responses
+type ErrorFormat string
+
+const (
+	// ErrorFormatJSONAPI represents errors as JSON:API error objects (default)
+	ErrorFormatJSONAPI ErrorFormat = "jsonapi"
+	// ErrorFormatProblem represents errors as RFC 9457 Problem Details
+	ErrorFormatProblem ErrorFormat = "problem"
+)
+
+type (
+	// errorAboutContextKey defines the context key of the error links.about URL template
+	errorAboutContextKey struct{}
+	// errorFormatContextKey defines the context key of the (route) default error format
+	errorFormatContextKey struct{}
+)
+
+// WithErrorAbout returns a context with the URL template of error object links.about members (and problem types),
+// in which {code} is replaced by the error code (e.g. https://docs.example.com/errors/{code})
+func WithErrorAbout(ctx context.Context, template string) context.Context {
+	return context.WithValue(ctx, errorAboutContextKey{}, template)
 }
 
-// EncodeError writes error messages to the response writer
+// WithErrorFormat returns a context with the default error format, used unless the request Accept header prefers
+// another error format
+func WithErrorFormat(ctx context.Context, format ErrorFormat) context.Context {
+	return context.WithValue(ctx, errorFormatContextKey{}, format)
+}
+
+// EncodeError writes error messages to the response writer, as JSON:API errors or as Problem Details (negotiated by
+// the request Accept header and the default error format)
 func EncodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var e cerror.CustomError
+	if !errors.As(err, &e) {
+		e = cerror.NewInternalServerError(nil, "internal server error").(cerror.CustomError)
+	}
+	m := MapError(e.Type())
+
+	if negotiateErrorFormat(r) == ErrorFormatProblem {
+		encode(w, problem.MediaType, m.Status, problemDetails(r, e, m))
+		return
+	}
+	EncodeResponse(w, r, m.Status, errorResponse(r, e, m))
+}
+
+// errorResponse returns a custom error as a JSON:API error response, with one error object per invalid field of
+// validation errors
+func errorResponse(r *http.Request, e cerror.CustomError, m ErrorMapping) jsonapi.ErrorResponse {
 	var (
-		code     = http.StatusInternalServerError
 		response jsonapi.ErrorResponse
+		ferrors  = fieldErrors(e)
 	)
 
-	switch e := err.(type) {
-	case cerror.CustomError:
-		code, response = handleCustomError(e)
-	default:
-		data := defaultErrorData()
-		response = errorResponse(data)
+	if len(ferrors) == 0 {
+		response.Errors = []jsonapi.ErrorData{{
+			Code:   e.Code(),
+			Title:  e.Type(),
+			Detail: errorDetail(e),
+			Meta:   e.Meta(),
+		}}
+	}
+	for _, fe := range ferrors {
+		data := jsonapi.ErrorData{
+			Code:   fieldErrorCode(e, fe),
+			Title:  e.Type(),
+			Detail: fe.Detail,
+			Meta:   e.Meta(),
+		}
+		if fe.Pointer != "" || fe.Parameter != "" || fe.Header != "" {
+			data.Source = &jsonapi.ErrorSource{Pointer: fe.Pointer, Parameter: fe.Parameter, Header: fe.Header}
+		}
+		response.Errors = append(response.Errors, data)
+	}
+
+	// request-scoped members
+	traceID := requestTraceID(r)
+	about := errorAbout(r)
+	for i := range response.Errors {
+		data := &response.Errors[i]
+		data.ID = traceID
+		data.Status = strconv.Itoa(m.Status)
+		if about != "" {
+			data.Links = &jsonapi.ErrorLinks{About: strings.ReplaceAll(about, "{code}", data.Code)}
+		}
 	}
 
-	EncodeResponse(w, r, code, response)
+	return response
 }
 
-func defaultErrorData() jsonapi.ErrorData {
-	return jsonapi.ErrorData{
-		Title:  cerror.ErrorType.InternalServer,
-		Detail: "internal server error",
+// problemDetails returns a custom error as Problem Details, with invalid fields of validation errors as the errors
+// extension member
+func problemDetails(r *http.Request, e cerror.CustomError, m ErrorMapping) problem.Details {
+	details := problem.Details{
+		Type:     problem.DefaultType,
+		Title:    m.Title,
+		Status:   m.Status,
+		Detail:   errorDetail(e),
+		Instance: r.URL.RequestURI(),
+		Code:     e.Code(),
+		TraceID:  requestTraceID(r),
+		Meta:     e.Meta(),
+	}
+	if about := errorAbout(r); about != "" {
+		details.Type = strings.ReplaceAll(about, "{code}", details.Code)
 	}
-}
 
-func defaultValidationErrorData(e cerror.CustomError) jsonapi.ErrorData {
-	detail := "validation error"
-	if e.ErrorMessage() != "" {
-		detail = e.ErrorMessage()
+	for _, fe := range fieldErrors(e) {
+		details.Errors = append(details.Errors, problem.FieldError{
+			Code:      fieldErrorCode(e, fe),
+			Detail:    fe.Detail,
+			Pointer:   fe.Pointer,
+			Parameter: fe.Parameter,
+			Header:    fe.Header,
+		})
 	}
 
-	return jsonapi.ErrorData{
-		Title:  cerror.ErrorType.Validation,
-		Detail: detail,
+	return details
+}
+
+// errorDetail returns the detail message of a custom error, defaulting to its error type message
+func errorDetail(e cerror.CustomError) string {
+	if e.ErrorMessage() != "" {
+		return e.ErrorMessage()
 	}
+	return strings.ToLower(MapError(e.Type()).Title)
 }
 
-func errorData(code int, detail, title string) jsonapi.ErrorData {
-	return jsonapi.ErrorData{
-		Title:  title,
-		Detail: detail,
+// fieldErrors returns the invalid fields (or request headers) of an error, with the field errors of invopop
+// validation errors located by JSON pointer
+func fieldErrors(e cerror.CustomError) cerror.FieldErrors {
+	var (
+		ferrors cerror.FieldErrors
+		verrors validation.Errors
+	)
+	if errors.As(e, &ferrors) {
+		return ferrors
+	}
+	if e.Type() == cerror.ErrorType.Validation && errors.As(e, &verrors) {
+		return validate.Errors(verrors, validate.Pointer(""))
 	}
+
+	return nil
 }
 
-func errorResponse(data jsonapi.ErrorData) jsonapi.ErrorResponse {
-	return jsonapi.ErrorResponse{
-		Errors: []jsonapi.ErrorData{data},
+// fieldErrorCode returns the code of a field error, defaulting to the code of its validation error
+func fieldErrorCode(e cerror.CustomError, fe cerror.FieldError) string {
+	if fe.Code != "" {
+		return fe.Code
 	}
+	return e.Code()
 }
 
-func handleCustomError(e cerror.CustomError) (int, jsonapi.ErrorResponse) {
-	code := HTTPStatusMap[e.Type()]
+// errorAbout returns the error links.about URL template of the request (if configured)
+func errorAbout(r *http.Request) string {
+	about, _ := r.Context().Value(errorAboutContextKey{}).(string)
+	return about
+}
 
-	if e.Type() != cerror.ErrorType.Validation {
-		data := errorData(code, e.ErrorMessage(), e.Type())
-		return code, errorResponse(data)
+// requestTraceID returns the trace id of the request (if set)
+func requestTraceID(r *http.Request) string {
+	if traceID := trace.GetTraceIDFromContext(r.Context()); traceID != trace.UnknownTraceID {
+		return traceID
 	}
+	return ""
+}
 
-	var (
-		response = errorResponse(defaultValidationErrorData(e))
-		verrors  validation.Errors
-	)
-	if errors.As(e, &verrors) {
-		response = validationErrorResponse("/", verrors, jsonapi.ErrorResponse{})
+// negotiateErrorFormat returns the error format preferred by the request Accept header (by quality value), or the
+// default error format of the request when neither format is preferred
+func negotiateErrorFormat(r *http.Request) ErrorFormat {
+	format, ok := r.Context().Value(errorFormatContextKey{}).(ErrorFormat)
+	if !ok {
+		format = ErrorFormatJSONAPI
 	}
 
-	return code, response
-}
-
-func validationErrorResponse(path string, ve validation.Errors, er jsonapi.ErrorResponse) jsonapi.ErrorResponse {
-	for key, val := range ve {
-		path := fmt.Sprintf("%s%s", path, key)
-
-		switch v := val.(type) {
-		case validation.Error:
-			er.Errors = append(er.Errors, jsonapi.ErrorData{
-				Source: &jsonapi.ErrorSourcePointer{Pointer: path},
-				Title:  cerror.ErrorType.Validation,
-				Detail: v.Error(),
-			})
-		case validation.Errors:
-			path = fmt.Sprintf("%s/", path)
-			return validationErrorResponse(path, v, er)
+	problemQ, jsonapiQ := -1.0, -1.0
+	for _, mr := range ParseAccept(r.Header.Get("Accept")) {
+		switch mr.MediaType {
+		case problem.MediaType:
+			problemQ = max(problemQ, mr.Q)
+		case jsonapi.MediaType, "application/json":
+			jsonapiQ = max(jsonapiQ, mr.Q)
 		}
 	}
 
-	return er
+	switch {
+	case problemQ > 0 && problemQ > jsonapiQ:
+		return ErrorFormatProblem
+	case jsonapiQ > 0 && jsonapiQ > problemQ:
+		return ErrorFormatJSONAPI
+	default:
+		return format
+	}
 }
@@ -0,0 +1,53 @@
+package jsonio
+
+import (
+	"net/http"
+	"sync"
+
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+)
+
+// ErrorMapping defines the HTTP representation of a custom error type, shared by all error formats
+type ErrorMapping struct {
+	// Status defines the HTTP status code
+	Status int `validate:"required,min=400,max=599"`
+	// Title defines the short, human-readable summary of the error type (problem details title)
+	Title string `validate:"required"`
+}
+
+var (
+	errorMappingsMutex sync.RWMutex
+	// errorMappings maps custom error types to their HTTP representation
+	errorMappings = map[string]ErrorMapping{
+		cerror.ErrorType.Conflict:             {Status: http.StatusConflict, Title: "Conflict"},
+		cerror.ErrorType.Forbidden:            {Status: http.StatusForbidden, Title: "Forbidden"},
+		cerror.ErrorType.InternalServer:       {Status: http.StatusInternalServerError, Title: "Internal Server Error"},
+		cerror.ErrorType.NotAcceptable:        {Status: http.StatusNotAcceptable, Title: "Not Acceptable"},
+		cerror.ErrorType.NotFound:             {Status: http.StatusNotFound, Title: "Not Found"},
+		cerror.ErrorType.PayloadTooLarge:      {Status: http.StatusRequestEntityTooLarge, Title: "Payload Too Large"},
+		cerror.ErrorType.Timeout:              {Status: http.StatusGatewayTimeout, Title: "Timeout"},
+		cerror.ErrorType.TooManyRequests:      {Status: http.StatusTooManyRequests, Title: "Too Many Requests"},
+		cerror.ErrorType.Unauthorized:         {Status: http.StatusUnauthorized, Title: "Unauthorized"},
+		cerror.ErrorType.UnsupportedMediaType: {Status: http.StatusUnsupportedMediaType, Title: "Unsupported Media Type"},
+		cerror.ErrorType.Validation:           {Status: http.StatusBadRequest, Title: "Validation Failed"},
+	}
+)
+
+// MapError returns the HTTP representation of a custom error type, defaulting to that of internal server errors
+func MapError(errorType string) ErrorMapping {
+	errorMappingsMutex.RLock()
+	defer errorMappingsMutex.RUnlock()
+
+	if m, ok := errorMappings[errorType]; ok {
+		return m
+	}
+	return errorMappings[cerror.ErrorType.InternalServer]
+}
+
+// RegisterErrorMapping adds (or replaces) the HTTP representation of a custom error type
+func RegisterErrorMapping(errorType string, m ErrorMapping) {
+	errorMappingsMutex.Lock()
+	defer errorMappingsMutex.Unlock()
+
+	errorMappings[errorType] = m
+}
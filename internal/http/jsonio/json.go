@@ -3,16 +3,21 @@ package jsonio
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
+
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/validate"
 	// "go.opentelemetry.io/otel"
 	// "go.opentelemetry.io/otel/trace"
 )
 
-// DecodeRequest
+// DecodeRequest decodes a single JSON value of the request body into dest. The request body size is limited by the
+// body limit middleware
 func DecodeRequest(w http.ResponseWriter, r *http.Request, dest any) error {
-	r.Body = http.MaxBytesReader(w, r.Body, int64(1048576))
-
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 
@@ -28,9 +33,47 @@ func DecodeRequest(w http.ResponseWriter, r *http.Request, dest any) error {
 	return nil
 }
 
-// EncodeResponse
+// DecodeValidRequest decodes a request body into dest and validates it in a single pipeline: the `validate` struct
+// tags of dest (including JSON:API resource attributes), then the Validate method of dest or, for a JSON:API
+// request body, of its attributes (if implemented). All decode and validation failures are returned as a single
+// validation error, with field errors located by JSON pointer
+func DecodeValidRequest(w http.ResponseWriter, r *http.Request, dest any) error {
+	if err := DecodeRequest(w, r, dest); err != nil {
+		return decodeError(err)
+	}
+
+	errs := validate.Struct(dest, validate.Pointer(""))
+	if body, ok := dest.(*jsonapi.RequestBody); ok && body.Data != nil {
+		if v, ok := body.Data.Attributes.(validate.Validator); ok {
+			errs = validate.Merge(errs, validate.Errors(v.Validate(), validate.Pointer("/data/attributes")))
+		}
+	}
+	if len(errs) > 0 {
+		return cerror.NewValidationError(errs, "request body validation error")
+	}
+
+	return nil
+}
+
+// EncodeResponse writes data as a JSON:API response body, with the negotiated JSON representation of the request
+// (application/vnd.api+json with applied extensions and profiles, or application/json)
 func EncodeResponse(w http.ResponseWriter, r *http.Request, code int, data any) {
-	w.Header().Set("Content-Type", "application/json")
+	rep := RequestRepresentation(r)
+	if !rep.IsJSON() {
+		rep = Representation{MediaType: jsonapi.MediaType}
+	}
+	encode(w, rep.ContentType(), code, data)
+}
+
+// EncodeJSON writes data as a plain JSON response body, for responses which are not JSON:API documents (e.g. the
+// OpenAPI document)
+func EncodeJSON(w http.ResponseWriter, code int, data any) {
+	encode(w, "application/json", code, data)
+}
+
+// encode writes data as a JSON response body of the given media type
+func encode(w http.ResponseWriter, mediaType string, code int, data any) {
+	w.Header().Set("Content-Type", mediaType)
 	w.WriteHeader(code)
 
 	encoder := json.NewEncoder(w)
@@ -39,3 +82,35 @@ func EncodeResponse(w http.ResponseWriter, r *http.Request, code int, data any)
 		w.Write([]byte("internal server error"))
 	}
 }
+
+// BodyError returns request body read errors of bodies exceeding the maximum size (of the body limit middleware) as
+// payload too large errors, and other errors unchanged
+func BodyError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return cerror.NewPayloadTooLargeError(err, "request body exceeds the maximum size of %d bytes", maxErr.Limit)
+	}
+	return err
+}
+
+// decodeError returns a request body decode error as a validation error, located by JSON pointer for field type
+// mismatches (or as a payload too large error for bodies exceeding the maximum size)
+func decodeError(err error) error {
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		return BodyError(err)
+	}
+
+	fe := cerror.FieldError{Code: "validation_invalid_json", Detail: err.Error()}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		fe = cerror.FieldError{
+			Code:    "validation_invalid_type",
+			Detail:  fmt.Sprintf("must be a valid %s", typeErr.Type),
+			Pointer: "/" + strings.ReplaceAll(typeErr.Field, ".", "/"),
+		}
+	}
+
+	return cerror.NewValidationError(cerror.FieldErrors{fe}, "request body decode error")
+}
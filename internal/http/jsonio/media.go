@@ -0,0 +1,111 @@
+package jsonio
+
+import (
+	"context"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+)
+
+// Representation defines the negotiated response media type of a request, with the JSON:API extensions and
+// profiles (by URI) applied to JSON:API responses
+type Representation struct {
+	MediaType string
+	Ext       []string
+	Profile   []string
+}
+
+// ContentType returns the Content-Type header value of the representation, with ext and profile media type
+// parameters (as space-separated URI lists)
+func (rep Representation) ContentType() string {
+	params := map[string]string{}
+	if len(rep.Ext) > 0 {
+		params["ext"] = strings.Join(rep.Ext, " ")
+	}
+	if len(rep.Profile) > 0 {
+		params["profile"] = strings.Join(rep.Profile, " ")
+	}
+	return mime.FormatMediaType(rep.MediaType, params)
+}
+
+// IsJSON returns true for JSON:API and plain JSON representations
+func (rep Representation) IsJSON() bool {
+	return rep.MediaType == jsonapi.MediaType || rep.MediaType == "application/json"
+}
+
+// MediaRange defines a single media range of an Accept header
+type MediaRange struct {
+	// MediaType defines the (lowercase) media type, which may include wildcards (e.g. text/*)
+	MediaType string
+	// Params defines the media type parameters, other than the quality value
+	Params map[string]string
+	Q      float64
+}
+
+// Matches returns the specificity (1-3) with which the media range matches the given media type, or 0 for no match
+func (mr MediaRange) Matches(mediaType string) int {
+	switch {
+	case mr.MediaType == mediaType:
+		return 3
+	case mr.MediaType == "*/*":
+		return 1
+	case strings.HasSuffix(mr.MediaType, "/*"):
+		if strings.HasPrefix(mediaType, strings.TrimSuffix(mr.MediaType, "*")) {
+			return 2
+		}
+	}
+	return 0
+}
+
+// representationContextKey defines the context key of the negotiated representation
+type representationContextKey struct{}
+
+// WithRepresentation returns a context with the negotiated response representation
+func WithRepresentation(ctx context.Context, rep Representation) context.Context {
+	return context.WithValue(ctx, representationContextKey{}, rep)
+}
+
+// RequestRepresentation returns the negotiated response representation of the request, defaulting to the JSON:API
+// media type (without extensions or profiles)
+func RequestRepresentation(r *http.Request) Representation {
+	if rep, ok := r.Context().Value(representationContextKey{}).(Representation); ok {
+		return rep
+	}
+	return Representation{MediaType: jsonapi.MediaType}
+}
+
+// ParseAccept returns the media ranges of an Accept header, skipping malformed ranges. An empty header accepts all
+// media types
+func ParseAccept(header string) []MediaRange {
+	if strings.TrimSpace(header) == "" {
+		return []MediaRange{{MediaType: "*/*", Q: 1}}
+	}
+
+	var ranges []MediaRange
+	for _, accepted := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+			delete(params, "q")
+		}
+		ranges = append(ranges, MediaRange{MediaType: mediaType, Params: params, Q: q})
+	}
+
+	return ranges
+}
+
+// ParseURIList returns the URIs of a space-separated ext or profile media type parameter value
+func ParseURIList(value string) []string {
+	return slices.DeleteFunc(strings.Split(value, " "), func(uri string) bool { return uri == "" })
+}
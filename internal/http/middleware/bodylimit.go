@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	cl "github.com/jasonsites/gosk/internal/logger"
+)
+
+// BodyLimitRoute defines a route-specific maximum request body size (bytes), matched against the chi route pattern
+type BodyLimitRoute struct {
+	Method string `validate:"required"`
+	Path   string `validate:"required"`
+	Limit  int64  `validate:"required,min=1"`
+}
+
+// BodyLimitConfig defines necessary components for the body limit middleware
+type BodyLimitConfig struct {
+	// Default maximum request body size (bytes) applied to all routes without a route-specific limit
+	Default int64 `validate:"required,min=1"`
+
+	Logger *cl.CustomLogger `validate:"required"`
+
+	// Next defines a function to skip this middleware on return true
+	Next func(r *http.Request) bool
+
+	// Routes defines route-specific limits
+	Routes []BodyLimitRoute `validate:"dive"`
+}
+
+// BodyLimit returns the body limit middleware, which rejects requests with a Content-Length above the route limit
+// (413), and limits the request body read by handlers to the route limit (read errors of larger bodies are
+// returned as payload too large errors by jsonio.BodyError)
+func BodyLimit(c *BodyLimitConfig) func(http.Handler) http.Handler {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		panic(err)
+	}
+
+	routes := make(map[string]int64, len(c.Routes))
+	for _, route := range c.Routes {
+		routes[routeKey(route.Method, route.Path)] = route.Limit
+	}
+
+	limit := func(r *http.Request) int64 {
+		if len(routes) > 0 {
+			if rctx := chi.RouteContext(r.Context()); rctx != nil {
+				pattern := rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+				if limit, ok := routes[routeKey(r.Method, pattern)]; ok && pattern != "" {
+					return limit
+				}
+			}
+		}
+		return c.Default
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c.Next != nil && c.Next(r) || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			max := limit(r)
+			if r.ContentLength > max {
+				traceID := trace.GetTraceIDFromContext(r.Context())
+				log := c.Logger.CreateContextLogger(traceID)
+
+				err := cerror.NewPayloadTooLargeError(nil, "request body exceeds the maximum size of %d bytes", max)
+				log.Warn(err.Error())
+				jsonio.EncodeError(w, r, err)
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, max)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-chi/cors"
+)
+
+// CORS wraps the cors middleware, allowing the allowed origins to be replaced at runtime
+type CORS struct {
+	cors atomic.Pointer[cors.Cors]
+}
+
+// NewCORS returns a new CORS instance allowing the given origins
+func NewCORS(origins []string) *CORS {
+	c := &CORS{}
+	c.SetAllowedOrigins(origins)
+	return c
+}
+
+// SetAllowedOrigins replaces the allowed origins for subsequent requests
+func (c *CORS) SetAllowedOrigins(origins []string) {
+	c.cors.Store(cors.New(cors.Options{
+		AllowedOrigins:   origins,
+		AllowedMethods:   []string{"GET", "POST", "PATCH", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Read-Your-Writes"},
+		ExposedHeaders:   []string{"Link"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	}))
+}
+
+// Handler returns the cors middleware
+func (c *CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.cors.Load().Handler(next).ServeHTTP(w, r)
+	})
+}
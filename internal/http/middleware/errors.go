@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+)
+
+// ErrorLinks sets the URL template of JSON:API error object links.about members and problem types (e.g.
+// https://docs.example.com/errors/{code}), in which {code} is replaced by the error code
+func ErrorLinks(about string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := jsonio.WithErrorAbout(r.Context(), about)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ErrorFormat sets the default error format of all routes, or of a single route (e.g.
+// r.With(mw.ErrorFormat(jsonio.ErrorFormatProblem)).Get(...)), used unless the request Accept header prefers another
+// error format
+func ErrorFormat(format jsonio.ErrorFormat) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := jsonio.WithErrorFormat(r.Context(), format)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/problem"
+)
+
+// MediaTypeRoute defines the alternate request (Consumes) and response (Produces) media types of a route, matched
+// against the chi route pattern
+type MediaTypeRoute struct {
+	Method   string `validate:"required"`
+	Path     string `validate:"required"`
+	Consumes []string
+	Produces []string
+}
+
+// ContentNegotiationConfig defines necessary components for the content negotiation middleware
+type ContentNegotiationConfig struct {
+	// Extensions defines the supported JSON:API extension URIs
+	Extensions []string
+
+	// Next defines a function to skip this middleware on return true
+	Next func(r *http.Request) bool
+
+	// Profiles defines the supported JSON:API profile URIs
+	Profiles []string
+
+	// Routes defines route-specific alternate media types
+	Routes []MediaTypeRoute
+}
+
+// ContentNegotiation enforces JSON:API media type negotiation. Request bodies must be JSON:API documents (or plain
+// JSON, or an alternate request media type of the route), and are otherwise rejected with 415 Unsupported Media Type.
+// The Accept header must accept JSON:API (or plain JSON, or an alternate response media type of the route), and is
+// otherwise rejected with 406 Not Acceptable. The negotiated representation is set on the request context (see
+// jsonio.RequestRepresentation)
+func ContentNegotiation(c *ContentNegotiationConfig) func(http.Handler) http.Handler {
+	routes := make(map[string]MediaTypeRoute, len(c.Routes))
+	for _, route := range c.Routes {
+		routes[routeKey(route.Method, route.Path)] = route
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c.Next != nil && c.Next(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Accept")
+			route := mediaTypeRoute(r, routes)
+
+			if err := checkContentType(r, route.Consumes, c.Extensions); err != nil {
+				jsonio.EncodeError(w, r, err)
+				return
+			}
+
+			rep, err := negotiate(r, route.Produces, c.Extensions, c.Profiles)
+			if err != nil {
+				jsonio.EncodeError(w, r, err)
+				return
+			}
+
+			ctx := jsonio.WithRepresentation(r.Context(), rep)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// mediaTypeRoute returns the alternate media types of the route matching the given request
+func mediaTypeRoute(r *http.Request, routes map[string]MediaTypeRoute) MediaTypeRoute {
+	if len(routes) > 0 {
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path); pattern != "" {
+				return routes[routeKey(r.Method, pattern)]
+			}
+		}
+	}
+
+	return MediaTypeRoute{}
+}
+
+// checkContentType returns an unsupported media type error for request bodies other than JSON:API documents (with
+// supported extensions only), plain JSON or the given alternate request media types
+func checkContentType(r *http.Request, consumes, extensions []string) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+
+	header := r.Header.Get("Content-Type")
+	mediaType, params, err := mime.ParseMediaType(header)
+	if err != nil {
+		return unsupportedMediaType("missing or invalid request media type '%s'", header)
+	}
+
+	switch {
+	case mediaType == jsonapi.MediaType:
+		if err := checkJSONAPIParams(params, extensions); err != nil {
+			return unsupportedMediaType("unsupported request media type '%s' (%s)", header, err.Error())
+		}
+	case mediaType == "application/json", slices.Contains(consumes, mediaType):
+	default:
+		return unsupportedMediaType("unsupported request media type '%s'", header)
+	}
+
+	return nil
+}
+
+// negotiate returns the representation among JSON:API, plain JSON and the given alternate response media types
+// most preferred by the request Accept header (by quality value, and then in that order), or a not acceptable error.
+// Problem Details (application/problem+json) are acceptable, for error responses, with JSON:API documents returned
+// otherwise
+func negotiate(r *http.Request, produces, extensions, profiles []string) (jsonio.Representation, error) {
+	accept := r.Header.Get("Accept")
+	ranges := jsonio.ParseAccept(accept)
+	available := append([]string{jsonapi.MediaType, "application/json"}, produces...)
+
+	var (
+		best  jsonio.Representation
+		bestQ float64
+	)
+	for _, mediaType := range available {
+		q, params, err := acceptQuality(ranges, mediaType, extensions)
+		if err != nil {
+			return best, notAcceptable("unacceptable media type '%s' (%s)", accept, err.Error())
+		}
+		if q <= bestQ {
+			continue
+		}
+
+		bestQ = q
+		best = jsonio.Representation{MediaType: mediaType}
+		if mediaType == jsonapi.MediaType {
+			best.Ext = jsonio.ParseURIList(params["ext"])
+			for _, profile := range jsonio.ParseURIList(params["profile"]) {
+				if slices.Contains(profiles, profile) {
+					best.Profile = append(best.Profile, profile)
+				}
+			}
+		}
+	}
+
+	// clients accepting only Problem Details (error responses) are not rejected, and are sent JSON:API documents
+	if bestQ == 0 {
+		if q, _, _ := acceptQuality(ranges, problem.MediaType, extensions); q > 0 {
+			return jsonio.Representation{MediaType: jsonapi.MediaType}, nil
+		}
+		return best, notAcceptable("none of the accepted media types '%s' are available (available: %s)",
+			accept, strings.Join(available, ", "))
+	}
+
+	return best, nil
+}
+
+// acceptQuality returns the quality value of the most specific media range matching the given media type, with its
+// media type parameters for exact matches. JSON:API media ranges with parameters other than ext and profile, or with
+// unsupported extensions, are skipped, and an error is returned when every JSON:API media range is skipped
+func acceptQuality(ranges []jsonio.MediaRange, mediaType string, extensions []string) (float64, map[string]string, error) {
+	var (
+		q           float64
+		params      map[string]string
+		specificity int
+		instances   int
+		skipped     int
+		skipErr     error
+	)
+
+	for _, mr := range ranges {
+		s := mr.Matches(mediaType)
+		if s == 0 {
+			continue
+		}
+		if s == 3 && mediaType == jsonapi.MediaType {
+			instances++
+			if err := checkJSONAPIParams(mr.Params, extensions); err != nil {
+				skipped, skipErr = skipped+1, err
+				continue
+			}
+		}
+		if s > specificity || (s == specificity && mr.Q > q) {
+			q, specificity = mr.Q, s
+			params = nil
+			if s == 3 {
+				params = mr.Params
+			}
+		}
+	}
+
+	if instances > 0 && skipped == instances {
+		return 0, nil, skipErr
+	}
+
+	return q, params, nil
+}
+
+// checkJSONAPIParams returns an error for JSON:API media type parameters other than ext and profile, and for
+// unsupported extensions (unrecognized profiles are ignored, as required by the spec)
+func checkJSONAPIParams(params map[string]string, extensions []string) error {
+	for name, value := range params {
+		switch name {
+		case "ext":
+			for _, uri := range jsonio.ParseURIList(value) {
+				if !slices.Contains(extensions, uri) {
+					return fmt.Errorf("unsupported extension '%s'", uri)
+				}
+			}
+		case "profile":
+		default:
+			return fmt.Errorf("unsupported media type parameter '%s'", name)
+		}
+	}
+
+	return nil
+}
+
+// notAcceptable returns a not acceptable error located by the Accept request header
+func notAcceptable(message string, a ...any) error {
+	detail := fmt.Sprintf(message, a...)
+	errs := cerror.FieldErrors{{Detail: detail, Header: "Accept"}}
+	return cerror.NewNotAcceptableError(errs, "%s", detail)
+}
+
+// unsupportedMediaType returns an unsupported media type error located by the Content-Type request header
+func unsupportedMediaType(message string, a ...any) error {
+	detail := fmt.Sprintf(message, a...)
+	errs := cerror.FieldErrors{{Detail: detail, Header: "Content-Type"}}
+	return cerror.NewUnsupportedMediaTypeError(errs, "%s", detail)
+}
@@ -0,0 +1,280 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	cl "github.com/jasonsites/gosk/internal/logger"
+)
+
+// RateLimitQuota defines a token bucket quota of Limit requests per Period
+type RateLimitQuota struct {
+	Limit  uint          `validate:"required"`
+	Period time.Duration `validate:"required"`
+}
+
+// RateLimitRoute defines a route-specific quota, matched against the chi route pattern
+type RateLimitRoute struct {
+	Method string `validate:"required"`
+	Path   string `validate:"required"`
+	Quota  RateLimitQuota
+}
+
+// RateLimitResult defines the outcome of taking a token from a bucket
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      uint
+	Remaining  uint
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// RateLimitStore defines the interface for token bucket persistence
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, quota RateLimitQuota) (*RateLimitResult, error)
+}
+
+// RateLimiterConfig defines necessary components for the rate limiter middleware
+type RateLimiterConfig struct {
+	// Default quota applied to all routes without a route-specific quota
+	Default RateLimitQuota `validate:"required"`
+
+	// KeyFunc defines a function to identify the client making the request (optional, defaults to
+	// RateLimitClientKey). It must only use verified credentials (see ClientIdentity)
+	KeyFunc func(r *http.Request) string
+
+	Logger *cl.CustomLogger `validate:"required"`
+
+	// Next defines a function to skip this middleware on return true
+	Next func(r *http.Request) bool
+
+	// Routes defines route-specific quotas
+	Routes []RateLimitRoute `validate:"dive"`
+
+	Store RateLimitStore `validate:"required"`
+}
+
+// RateLimiter implements token bucket rate limiting keyed by client and route
+type RateLimiter struct {
+	keyFunc func(r *http.Request) string
+	logger  *cl.CustomLogger
+	mutex   sync.RWMutex
+	next    func(r *http.Request) bool
+	quotas  rateLimitQuotas
+	store   RateLimitStore
+}
+
+type rateLimitQuotas struct {
+	def    RateLimitQuota
+	routes map[string]RateLimitQuota
+}
+
+// NewRateLimiter returns a new RateLimiter instance
+func NewRateLimiter(c *RateLimiterConfig) (*RateLimiter, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	rl := &RateLimiter{
+		keyFunc: c.KeyFunc,
+		logger:  c.Logger,
+		next:    c.Next,
+		store:   c.Store,
+	}
+	if rl.keyFunc == nil {
+		rl.keyFunc = RateLimitClientKey
+	}
+	rl.SetQuotas(c.Default, c.Routes)
+
+	return rl, nil
+}
+
+// SetQuotas replaces the default and route-specific quotas
+func (rl *RateLimiter) SetQuotas(def RateLimitQuota, routes []RateLimitRoute) {
+	quotas := rateLimitQuotas{
+		def:    def,
+		routes: make(map[string]RateLimitQuota, len(routes)),
+	}
+	for _, route := range routes {
+		quotas.routes[routeKey(route.Method, route.Path)] = route.Quota
+	}
+
+	rl.mutex.Lock()
+	rl.quotas = quotas
+	rl.mutex.Unlock()
+}
+
+// Handler returns the rate limiter middleware
+func (rl *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rl.next != nil && rl.next(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := rl.logger.CreateContextLogger(traceID)
+
+		scope, quota := rl.quota(r)
+		key := fmt.Sprintf("%s|%s", rl.keyFunc(r), scope)
+
+		result, err := rl.store.Take(ctx, key, quota)
+		if err != nil {
+			// fail open, as an unavailable store should not take down the api
+			log.Error(fmt.Sprintf("rate limit store error: %s", err.Error()))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.FormatUint(uint64(result.Limit), 10))
+		header.Set("RateLimit-Remaining", strconv.FormatUint(uint64(result.Remaining), 10))
+		header.Set("RateLimit-Reset", formatSeconds(result.Reset))
+
+		if !result.Allowed {
+			header.Set("Retry-After", formatSeconds(result.RetryAfter))
+			err := cerror.NewTooManyRequestsError(nil, "rate limit exceeded, retry in %s seconds", formatSeconds(result.RetryAfter))
+			log.Warn(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// quota returns the bucket scope and quota for the route matching the given request
+func (rl *RateLimiter) quota(r *http.Request) (string, RateLimitQuota) {
+	rl.mutex.RLock()
+	quotas := rl.quotas
+	rl.mutex.RUnlock()
+
+	if len(quotas.routes) > 0 {
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			pattern := rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)
+			if pattern != "" {
+				scope := routeKey(r.Method, pattern)
+				if quota, ok := quotas.routes[scope]; ok {
+					return scope, quota
+				}
+			}
+		}
+	}
+
+	return "*", quotas.def
+}
+
+// ClientIdentity defines the verified credentials of an authenticated client, set on the request context by
+// authentication middleware (which must run before the rate limiter)
+type ClientIdentity struct {
+	// Admin defines whether the client is authorized for admin routes (e.g. /{namespace}/admin/status)
+	Admin bool
+	// APIKey defines the verified API key (or its identifier)
+	APIKey string
+	// Subject defines the verified JWT subject
+	Subject string
+}
+
+// clientIdentityContextKey defines the context key of the verified client identity
+type clientIdentityContextKey struct{}
+
+// WithClientIdentity returns a context with the given verified client identity, and must only be called with
+// credentials verified by authentication
+func WithClientIdentity(ctx context.Context, id ClientIdentity) context.Context {
+	return context.WithValue(ctx, clientIdentityContextKey{}, id)
+}
+
+// GetClientIdentity returns the verified client identity of the given context, if authenticated
+func GetClientIdentity(ctx context.Context) (ClientIdentity, bool) {
+	id, ok := ctx.Value(clientIdentityContextKey{}).(ClientIdentity)
+	return id, ok
+}
+
+// RateLimitClientKey identifies the client by verified API key, then verified JWT subject (see WithClientIdentity),
+// then IP address (see RealIP for clients behind trusted proxies). Unverified credential headers are never used, as
+// they would allow a client to pick a new bucket per request
+func RateLimitClientKey(r *http.Request) string {
+	if id, ok := GetClientIdentity(r.Context()); ok {
+		switch {
+		case id.APIKey != "":
+			// api keys are hashed, so that they are not persisted by rate limit stores
+			sum := sha256.Sum256([]byte(id.APIKey))
+			return "key:" + hex.EncodeToString(sum[:16])
+		case id.Subject != "":
+			return "sub:" + id.Subject
+		}
+	}
+
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	return "ip:" + ip
+}
+
+func routeKey(method, path string) string {
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
+
+// =====================================================================================================================
+// Token Bucket
+// =====================================================================================================================
+
+// rateLimitBucket defines the state of a single token bucket
+type rateLimitBucket struct {
+	Tokens    float64
+	UpdatedOn time.Time
+}
+
+// take refills the bucket for the time elapsed since its last update and attempts to consume a single token
+func (b *rateLimitBucket) take(now time.Time, quota RateLimitQuota) *RateLimitResult {
+	capacity := float64(quota.Limit)
+	rate := capacity / quota.Period.Seconds()
+
+	if b.UpdatedOn.IsZero() {
+		b.Tokens = capacity
+	} else if elapsed := now.Sub(b.UpdatedOn).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(capacity, b.Tokens+elapsed*rate)
+	}
+	b.UpdatedOn = now
+
+	result := &RateLimitResult{Limit: quota.Limit}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.Tokens) / rate)
+	}
+
+	result.Remaining = uint(math.Floor(b.Tokens))
+	result.Reset = seconds((capacity - b.Tokens) / rate)
+
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/app"
+)
+
+// =====================================================================================================================
+// Memory Store
+// =====================================================================================================================
+
+// MemoryRateLimitStore provides a process-local RateLimitStore
+type MemoryRateLimitStore struct {
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+	mutex     sync.Mutex
+	now       func() time.Time
+}
+
+type memoryBucket struct {
+	bucket rateLimitBucket
+	period time.Duration
+}
+
+// NewMemoryRateLimitStore returns a new MemoryRateLimitStore instance
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+	}
+}
+
+// Take consumes a token from the bucket identified by key
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, quota RateLimitQuota) (*RateLimitResult, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{}
+		s.buckets[key] = b
+	}
+	b.period = quota.Period
+
+	return b.bucket.take(now, quota), nil
+}
+
+// sweep evicts buckets which have been idle long enough to be completely refilled
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if now.Sub(b.bucket.UpdatedOn) > b.period {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// =====================================================================================================================
+// PostgreSQL Store
+// =====================================================================================================================
+
+// PostgresRateLimitStoreConfig defines the input to NewPostgresRateLimitStore
+type PostgresRateLimitStoreConfig struct {
+	DBClient *pgxpool.Pool `validate:"required"`
+}
+
+// PostgresRateLimitStore provides a RateLimitStore shared across replicas
+type PostgresRateLimitStore struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresRateLimitStore returns a new PostgresRateLimitStore instance
+func NewPostgresRateLimitStore(c *PostgresRateLimitStoreConfig) (*PostgresRateLimitStore, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	return &PostgresRateLimitStore{db: c.DBClient}, nil
+}
+
+// Take consumes a token from the bucket identified by key, locking the bucket row for the duration of the update
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, quota RateLimitQuota) (*RateLimitResult, error) {
+	var result *RateLimitResult
+
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		var (
+			b   rateLimitBucket
+			now time.Time
+		)
+
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO rate_limit_bucket (key, tokens) VALUES ($1, $2) ON CONFLICT (key) DO NOTHING",
+			key, float64(quota.Limit),
+		); err != nil {
+			return err
+		}
+
+		if err := tx.QueryRow(ctx,
+			"SELECT tokens, updated_on, clock_timestamp() FROM rate_limit_bucket WHERE key = $1 FOR UPDATE",
+			key,
+		).Scan(&b.Tokens, &b.UpdatedOn, &now); err != nil {
+			return err
+		}
+
+		result = b.take(now, quota)
+
+		_, err := tx.Exec(ctx,
+			"UPDATE rate_limit_bucket SET tokens = $2, updated_on = $3 WHERE key = $1",
+			key, b.Tokens, b.UpdatedOn,
+		)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Prune deletes buckets which have been idle for the given duration, returning the number of deleted buckets
+// NOTE: idle should be at least the longest quota period, so that only completely refilled buckets are deleted
+func (s *PostgresRateLimitStore) Prune(ctx context.Context, idle time.Duration) (int64, error) {
+	tag, err := s.db.Exec(ctx, "DELETE FROM rate_limit_bucket WHERE updated_on < clock_timestamp() - $1::interval", idle)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
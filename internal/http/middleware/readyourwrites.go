@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/jasonsites/gosk/internal/database"
+)
+
+// ReadYourWritesConfig defines necessary components for the read-your-writes middleware
+type ReadYourWritesConfig struct {
+	// Header flags a request for read-your-writes when present with any value (e.g. on reads following a write)
+	Header string
+
+	// Next defines a function to skip this middleware on return true
+	Next func(r *http.Request) bool
+}
+
+// ReadYourWrites returns the read-your-writes middleware, which routes all database reads of unsafe requests
+// (e.g. reading back a created resource) and of requests with the configured header to the primary
+// (see database.WithPrimary)
+func ReadYourWrites(c *ReadYourWritesConfig) func(http.Handler) http.Handler {
+	conf := setReadYourWritesConfig(c)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if conf.Next != nil && conf.Next(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				if r.Header.Get(conf.Header) == "" {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(database.WithPrimary(r.Context())))
+		})
+	}
+}
+
+func setReadYourWritesConfig(c *ReadYourWritesConfig) *ReadYourWritesConfig {
+	// default config
+	var conf = &ReadYourWritesConfig{
+		Header: "X-Read-Your-Writes",
+		Next:   nil,
+	}
+
+	// default overrides
+	if c.Header != "" {
+		conf.Header = c.Header
+	}
+	if c.Next != nil {
+		conf.Next = c.Next
+	}
+
+	return conf
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/jasonsites/gosk/internal/app"
+)
+
+// RealIPConfig defines necessary components for the real ip middleware
+type RealIPConfig struct {
+	// TrustedProxies defines the IP addresses and CIDR ranges (e.g. 10.0.0.0/8) of trusted reverse proxies, whose
+	// X-Forwarded-For and X-Real-IP headers identify the client. Headers of all other peers are ignored
+	TrustedProxies []string `validate:"dive,cidr|ip"`
+}
+
+// RealIP returns the real ip middleware, which replaces the request RemoteAddr of requests from trusted proxies with
+// the client IP address: the last untrusted X-Forwarded-For address, or the X-Real-IP address
+func RealIP(c *RealIPConfig) func(http.Handler) http.Handler {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		panic(err)
+	}
+
+	trusted := make([]netip.Prefix, 0, len(c.TrustedProxies))
+	for _, proxy := range c.TrustedProxies {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			addr := netip.MustParseAddr(proxy)
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+		trusted = append(trusted, prefix.Masked())
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ip, ok := clientIP(r, trusted); ok {
+				r.RemoteAddr = ip.String()
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP returns the client IP address of a request from a trusted proxy, walking the X-Forwarded-For chain from
+// the nearest hop and skipping trusted proxies
+func clientIP(r *http.Request, trusted []netip.Prefix) (netip.Addr, bool) {
+	isTrusted := func(addr netip.Addr) bool {
+		addr = addr.Unmap()
+		for _, prefix := range trusted {
+			if prefix.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	remote, err := netip.ParseAddr(host)
+	if err != nil || !isTrusted(remote) {
+		return netip.Addr{}, false
+	}
+
+	var hops []string
+	for _, value := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(value, ",")...)
+	}
+
+	client, found := netip.Addr{}, false
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// addresses beyond an invalid hop can not be attributed
+			break
+		}
+		client, found = addr.Unmap(), true
+		if !isTrusted(addr) {
+			return client, true
+		}
+	}
+	if found {
+		return client, true
+	}
+
+	if addr, err := netip.ParseAddr(strings.TrimSpace(r.Header.Get("X-Real-IP"))); err == nil {
+		return addr.Unmap(), true
+	}
+
+	return netip.Addr{}, false
+}
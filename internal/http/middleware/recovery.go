@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	cl "github.com/jasonsites/gosk/internal/logger"
+)
+
+// PanicReport defines a recovered panic reported to an ErrorReporter
+type PanicReport struct {
+	Err     error
+	Method  string
+	Path    string
+	Stack   []byte
+	TraceID string
+}
+
+// ErrorReporter defines a pluggable error reporting sink (e.g. an error tracking service)
+type ErrorReporter interface {
+	Report(ctx context.Context, report PanicReport)
+}
+
+// RecovererConfig defines necessary components for the recovery middleware
+type RecovererConfig struct {
+	Logger *cl.CustomLogger `validate:"required"`
+
+	// Reporter defines an optional error reporting sink for recovered panics
+	Reporter ErrorReporter
+}
+
+// RecovererStatus defines the recovery metrics
+type RecovererStatus struct {
+	Panics uint64 `json:"panics"`
+}
+
+// Recoverer recovers from handler panics, logging and reporting each panic before responding with an internal
+// server error
+type Recoverer struct {
+	logger   *cl.CustomLogger
+	panics   atomic.Uint64
+	reporter ErrorReporter
+}
+
+// NewRecoverer returns a new Recoverer instance
+func NewRecoverer(c *RecovererConfig) (*Recoverer, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	rec := &Recoverer{
+		logger:   c.Logger,
+		reporter: c.Reporter,
+	}
+
+	return rec, nil
+}
+
+// Handler returns the recovery middleware
+func (rec *Recoverer) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				// aborted responses are not recoverable, and are not logged by the http server
+				panic(v)
+			}
+			rec.recover(ww, r, v, debug.Stack())
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
+
+// Status returns the recovery metrics
+func (rec *Recoverer) Status() RecovererStatus {
+	return RecovererStatus{Panics: rec.panics.Load()}
+}
+
+// recover logs and reports a recovered panic value, responding with an internal server error unless the response
+// has already been written
+func (rec *Recoverer) recover(w middleware.WrapResponseWriter, r *http.Request, v any, stack []byte) {
+	ctx := r.Context()
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := rec.logger.CreateContextLogger(traceID)
+
+	rec.panics.Add(1)
+
+	err, ok := v.(error)
+	if !ok {
+		err = fmt.Errorf("%v", v)
+	}
+	err = fmt.Errorf("panic: %w", err)
+
+	log.Error(err.Error(),
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("stack", string(stack)),
+	)
+
+	if rec.reporter != nil {
+		rec.reporter.Report(ctx, PanicReport{
+			Err:     err,
+			Method:  r.Method,
+			Path:    r.URL.Path,
+			Stack:   stack,
+			TraceID: traceID,
+		})
+	}
+
+	if w.Status() != 0 {
+		// the response status (and possibly part of the body) has already been sent
+		log.Warn("panic recovered after response was written")
+		return
+	}
+	jsonio.EncodeError(w, r, cerror.NewInternalServerError(err, "internal server error"))
+}
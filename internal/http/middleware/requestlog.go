@@ -5,22 +5,34 @@ import (
 	"encoding/json"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"strings"
 
 	"github.com/jasonsites/gosk/internal/app"
-	"github.com/jasonsites/gosk/internal/http/jsonio"
 	"github.com/jasonsites/gosk/internal/http/trace"
 	cl "github.com/jasonsites/gosk/internal/logger"
 )
 
+const (
+	// maxRequestLogBodySize defines the maximum size of request bodies read for logging (the remaining request body
+	// is read by handlers, and never held in memory)
+	maxRequestLogBodySize = 64 << 10
+	// maxRequestLogBodyText defines the maximum size of request bodies logged as (truncated) text
+	maxRequestLogBodyText = 4 << 10
+)
+
 // RequestLogData defines the data captured for request logging
 type RequestLogData struct {
-	Body     map[string]any
-	ClientIP string
-	Header   http.Header
-	Method   string
-	Path     string
-	Query    *string
+	// Body defines the decoded (JSON) or text request body (redacted by the logger redaction policy)
+	Body          any
+	BodySize      *int64
+	BodyTruncated bool
+	ClientIP      string
+	Header        http.Header
+	Method        string
+	Path          string
+	Query         *string
 }
 
 // RequestLoggerConfig defines necessary components for the request logger middleware
@@ -42,37 +54,18 @@ func RequestLogger(c *RequestLoggerConfig) func(http.Handler) http.Handler {
 				return
 			}
 
-			if err := logRequest(w, r, c.Logger); err != nil {
-				c.Logger.Log.Error(err.Error())
-				jsonio.EncodeError(w, r, err)
-			}
+			logRequest(r, c.Logger)
 
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
-func logRequest(w http.ResponseWriter, r *http.Request, logger *cl.CustomLogger) error {
+func logRequest(r *http.Request, logger *cl.CustomLogger) {
 	traceID := trace.GetTraceIDFromContext(r.Context())
 	log := logger.CreateContextLogger(traceID)
 
-	var body map[string]any
-	if logger.Level == cl.LevelDebug {
-		r.Body = http.MaxBytesReader(w, r.Body, int64(1048576))
-
-		copy, err := io.ReadAll(r.Body)
-		if err != nil {
-			return err
-		}
-
-		if len(copy) > 0 {
-			if err := json.Unmarshal(copy, &body); err != nil {
-				return err
-			}
-		}
-
-		r.Body = io.NopCloser(bytes.NewBuffer(copy))
-	}
+	debug := logger.DebugEnabled(r.Context())
 
 	var queryString string
 	if r.URL.RawQuery != "" {
@@ -80,20 +73,83 @@ func logRequest(w http.ResponseWriter, r *http.Request, logger *cl.CustomLogger)
 	}
 
 	data := &RequestLogData{
-		Body:     body,
 		ClientIP: r.RemoteAddr,
 		Header:   r.Header,
 		Method:   r.Method,
 		Path:     r.URL.Path,
 		Query:    &queryString,
 	}
-	attrs := requestLogAttrs(data, logger.Level)
+	if r.ContentLength > 0 {
+		data.BodySize = &r.ContentLength
+	}
+	if debug {
+		data.Body, data.BodyTruncated = peekRequestBody(r)
+	}
+
+	attrs := requestLogAttrs(data, debug)
 	log.With(attrs...).Info("request")
+}
 
-	return nil
+// peekedBody defines a request body of which a prefix has been read for logging
+type peekedBody struct {
+	io.Reader
+	io.Closer
 }
 
-func requestLogAttrs(data *RequestLogData, level string) []any {
+// peekRequestBody reads the request body for logging, up to a maximum size, and restores the request body for
+// handlers (read errors, e.g. of bodies exceeding the body limit, are left to handlers)
+func peekRequestBody(r *http.Request) (any, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, false
+	}
+
+	prefix, err := io.ReadAll(io.LimitReader(r.Body, maxRequestLogBodySize+1))
+	r.Body = &peekedBody{Reader: io.MultiReader(bytes.NewReader(prefix), r.Body), Closer: r.Body}
+	if err != nil || len(prefix) == 0 {
+		return nil, false
+	}
+
+	return requestLogBody(r.Header.Get("Content-Type"), prefix, len(prefix) > maxRequestLogBodySize)
+}
+
+// requestLogBody returns a request body for logging: complete JSON bodies are decoded, other JSON and text bodies
+// are logged as (truncated) text, and binary bodies are not logged
+func requestLogBody(contentType string, body []byte, truncated bool) (any, bool) {
+	if isJSONMediaType(contentType) && !truncated {
+		var value any
+		if err := json.Unmarshal(body, &value); err == nil {
+			return value, false
+		}
+	}
+
+	if !isTextMediaType(contentType) {
+		return nil, false
+	}
+
+	if len(body) > maxRequestLogBodyText {
+		body, truncated = body[:maxRequestLogBodyText], true
+	}
+
+	return strings.ToValidUTF8(string(body), ""), truncated
+}
+
+// isTextMediaType returns true for JSON, text (e.g. text/csv), url-encoded form, NDJSON and XML media types
+func isTextMediaType(contentType string) bool {
+	if isJSONMediaType(contentType) {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch mediaType {
+	case "application/x-www-form-urlencoded", "application/x-ndjson", "application/xml":
+		return true
+	}
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+xml")
+}
+
+func requestLogAttrs(data *RequestLogData, debug bool) []any {
 	k := cl.AttrKey
 
 	attrs := []any{
@@ -106,13 +162,20 @@ func requestLogAttrs(data *RequestLogData, level string) []any {
 		attrs = append(attrs, k.HTTP.Query, data.Query)
 	}
 
-	if level == cl.LevelDebug {
+	if data.BodySize != nil {
+		attrs = append(attrs, slog.Int64(k.HTTP.BodySize, *data.BodySize))
+	}
+
+	if debug {
 		if data.Header != nil {
 			attrs = append(attrs, k.HTTP.Header, data.Header)
 		}
 		if data.Body != nil {
 			attrs = append(attrs, k.HTTP.Body, data.Body)
 		}
+		if data.BodyTruncated {
+			attrs = append(attrs, slog.Bool(k.HTTP.BodyTruncated, true))
+		}
 	}
 
 	return attrs
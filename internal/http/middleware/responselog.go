@@ -5,8 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -15,6 +17,9 @@ import (
 	cl "github.com/jasonsites/gosk/internal/logger"
 )
 
+// maxResponseLogBodySize defines the maximum size of response bodies captured for logging
+const maxResponseLogBodySize = 64 << 10
+
 // ResponseLogData defines the data captured for response logging
 type ResponseLogData struct {
 	Body         map[string]any
@@ -48,8 +53,8 @@ func ResponseLogger(c *ResponseLoggerConfig) func(http.Handler) http.Handler {
 
 			// extended response writer
 			extendedRW := middleware.NewWrapResponseWriter(w, 1)
-			bodyBuffer := new(bytes.Buffer)
-			extendedRW.Tee(bodyBuffer)
+			body := &bodyCapture{header: extendedRW.Header(), max: maxResponseLogBodySize}
+			extendedRW.Tee(body)
 
 			// call next middleware
 			next.ServeHTTP(extendedRW, r)
@@ -60,7 +65,7 @@ func ResponseLogger(c *ResponseLoggerConfig) func(http.Handler) http.Handler {
 			extendedRW.Header().Set("X-Response-Time", responseTime)
 
 			lrc := logResponseConfig{
-				bodyBuffer:   bodyBuffer,
+				body:         body,
 				logger:       c.Logger,
 				request:      r,
 				response:     extendedRW,
@@ -75,7 +80,7 @@ func ResponseLogger(c *ResponseLoggerConfig) func(http.Handler) http.Handler {
 }
 
 type logResponseConfig struct {
-	bodyBuffer   *bytes.Buffer
+	body         *bodyCapture
 	logger       *cl.CustomLogger
 	request      *http.Request
 	response     middleware.WrapResponseWriter
@@ -86,7 +91,6 @@ func logResponse(c logResponseConfig) error {
 	traceID := trace.GetTraceIDFromContext(c.request.Context())
 	log := c.logger.CreateContextLogger(traceID)
 
-	bodyBytes := c.bodyBuffer.Bytes()
 	bodySize := c.response.BytesWritten()
 
 	data := &ResponseLogData{
@@ -95,23 +99,64 @@ func logResponse(c logResponseConfig) error {
 		Status:       c.response.Status(),
 	}
 
-	var body map[string]any
 	if bodySize > 0 {
-		if err := json.Unmarshal(bodyBytes, &body); err != nil {
+		data.BodySize = &bodySize
+	}
+
+	var body map[string]any
+	if c.body.captured() {
+		if err := json.Unmarshal(c.body.buf.Bytes(), &body); err != nil {
 			return err
 		}
-
 		data.Body = body
-		data.BodySize = &bodySize
 	}
 
-	attrs := responseLogAttrs(data, c.logger.Level)
+	attrs := responseLogAttrs(data, c.logger.DebugEnabled(c.request.Context()))
 	log.With(attrs...).Info("response")
 
 	return nil
 }
 
-func responseLogAttrs(data *ResponseLogData, level string) []any {
+// bodyCapture captures JSON response bodies for logging, up to a maximum size. Other (e.g. streamed CSV or NDJSON)
+// and larger response bodies are not captured, so that they are never held in memory
+type bodyCapture struct {
+	buf     bytes.Buffer
+	header  http.Header
+	max     int
+	skip    bool
+	started bool
+}
+
+// Write captures written response body bytes
+func (b *bodyCapture) Write(p []byte) (int, error) {
+	if !b.started {
+		b.started = true
+		b.skip = !isJSONMediaType(b.header.Get("Content-Type"))
+	}
+	if b.skip {
+		return len(p), nil
+	}
+	if b.buf.Len()+len(p) > b.max {
+		b.skip = true
+		b.buf = bytes.Buffer{}
+		return len(p), nil
+	}
+
+	return b.buf.Write(p)
+}
+
+// captured returns true if a complete response body was captured
+func (b *bodyCapture) captured() bool {
+	return b.started && !b.skip && b.buf.Len() > 0
+}
+
+// isJSONMediaType returns true for JSON media types (e.g. application/json, application/vnd.api+json)
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"))
+}
+
+func responseLogAttrs(data *ResponseLogData, debug bool) []any {
 	k := cl.AttrKey
 
 	attrs := []any{
@@ -123,7 +168,7 @@ func responseLogAttrs(data *ResponseLogData, level string) []any {
 		attrs = append(attrs, slog.Int(k.HTTP.BodySize, *data.BodySize))
 	}
 
-	if level == cl.LevelDebug {
+	if debug {
 		if data.Body != nil {
 			attrs = append(attrs, k.HTTP.Body, data.Body)
 		}
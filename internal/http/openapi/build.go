@@ -0,0 +1,178 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+)
+
+// patternParamRegexp matches chi route parameter regular expressions (e.g. {id:[0-9]+})
+var patternParamRegexp = regexp.MustCompile(`\{([^}:]+):[^}]+\}`)
+
+// DocumentConfig defines the input to NewDocument
+type DocumentConfig struct {
+	Description string
+	// Operations describes the registered routes
+	Operations []Operation
+	// Routes defines the router walked for registered routes
+	Routes  chi.Routes `validate:"required"`
+	Title   string     `validate:"required"`
+	Version string     `validate:"required"`
+}
+
+// NewDocument returns an OpenAPI document of all routes registered on the router, as described by the given
+// operations. Registered routes without a described operation are included with `x-undocumented: true`, and
+// described operations without a registered route are included as-is, so that any drift between routes and
+// operations is visible in the document (see Drift)
+func NewDocument(c *DocumentConfig) (*Document, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	reg := &registry{names: map[reflect.Type]string{}, schemas: map[string]*Schema{}}
+	doc := &Document{
+		OpenAPI:    Version,
+		Info:       Info{Title: c.Title, Description: c.Description, Version: c.Version},
+		Paths:      map[string]PathItem{},
+		Components: Components{Schemas: reg.schemas},
+	}
+
+	for _, op := range c.Operations {
+		path := NormalizePattern(op.Pattern)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(op.Method)] = reg.operation(op)
+	}
+
+	err := walk(c.Routes, func(method, path string) {
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		if _, ok := doc.Paths[path][strings.ToLower(method)]; !ok {
+			doc.Paths[path][strings.ToLower(method)] = &OperationObject{
+				Responses:    map[string]*ResponseObject{"default": {Description: "undocumented"}},
+				Undocumented: true,
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// Drift returns the registered routes without a described operation, and the described operations without a
+// registered route (as "METHOD /path")
+func Drift(routes chi.Routes, ops []Operation) (undocumented, unregistered []string, err error) {
+	described := make(map[string]bool, len(ops))
+	for _, op := range ops {
+		described[routeKey(op.Method, NormalizePattern(op.Pattern))] = true
+	}
+
+	registered := map[string]bool{}
+	err = walk(routes, func(method, path string) {
+		key := routeKey(method, path)
+		registered[key] = true
+		if !described[key] {
+			undocumented = append(undocumented, key)
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, op := range ops {
+		if key := routeKey(op.Method, NormalizePattern(op.Pattern)); !registered[key] {
+			unregistered = append(unregistered, key)
+		}
+	}
+
+	return undocumented, unregistered, nil
+}
+
+// NormalizePattern returns the OpenAPI path of a chi route pattern, without trailing slashes or parameter
+// regular expressions (e.g. /domain/examples/ => /domain/examples)
+func NormalizePattern(pattern string) string {
+	pattern = patternParamRegexp.ReplaceAllString(pattern, "{$1}")
+	if len(pattern) > 1 {
+		pattern = strings.TrimSuffix(pattern, "/")
+	}
+	return pattern
+}
+
+// walk calls fn with the method and normalized path of every route registered on the router, skipping
+// wildcard (mounted or catch-all) routes
+func walk(routes chi.Routes, fn func(method, path string)) error {
+	return chi.Walk(routes, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if strings.Contains(route, "*") {
+			return nil
+		}
+		fn(method, NormalizePattern(route))
+		return nil
+	})
+}
+
+// routeKey returns the drift key of a route
+func routeKey(method, path string) string {
+	return fmt.Sprintf("%s %s", strings.ToUpper(method), path)
+}
+
+// operation returns the document operation object of a described operation
+func (reg *registry) operation(op Operation) *OperationObject {
+	o := &OperationObject{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Tags:        op.Tags,
+		Responses:   make(map[string]*ResponseObject, len(op.Responses)),
+	}
+
+	mediaType := op.MediaType
+	if mediaType == "" {
+		mediaType = jsonapi.MediaType
+	}
+
+	for _, p := range op.Parameters {
+		param := *p
+		param.Schema = reg.resolve(p.Schema)
+		o.Parameters = append(o.Parameters, &param)
+	}
+
+	if op.Request != nil || len(op.RequestAlternates) > 0 {
+		o.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{}}
+		if op.Request != nil {
+			o.RequestBody.Content[mediaType] = MediaType{Schema: reg.resolve(op.Request)}
+		}
+		for alternate, schema := range op.RequestAlternates {
+			o.RequestBody.Content[alternate] = MediaType{Schema: reg.resolve(schema)}
+		}
+	}
+
+	for _, res := range op.Responses {
+		description := res.Description
+		if description == "" {
+			description = http.StatusText(res.Status)
+		}
+		response := &ResponseObject{Description: description}
+		if res.Schema != nil {
+			response.Content = map[string]MediaType{mediaType: {Schema: reg.resolve(res.Schema)}}
+		}
+		for alternate, schema := range res.Alternates {
+			if response.Content == nil {
+				response.Content = make(map[string]MediaType, len(res.Alternates))
+			}
+			response.Content[alternate] = MediaType{Schema: reg.resolve(schema)}
+		}
+		o.Responses[strconv.Itoa(res.Status)] = response
+	}
+
+	return o
+}
@@ -0,0 +1,133 @@
+package openapi_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jasonsites/gosk/internal/http/openapi"
+)
+
+func Test_NormalizePattern(t *testing.T) {
+	tests := map[string]string{
+		"/":                             "/",
+		"/domain/examples/":             "/domain/examples",
+		"/domain/examples/{id}":         "/domain/examples/{id}",
+		"/domain/examples/{id:[0-9]+}":  "/domain/examples/{id}",
+		"/domain/{ns:[a-z]+}/{id:.+}/x": "/domain/{ns}/{id}/x",
+	}
+	for pattern, expected := range tests {
+		if actual := openapi.NormalizePattern(pattern); actual != expected {
+			t.Errorf("expected '%s' => '%s', actual '%s'", pattern, expected, actual)
+		}
+	}
+}
+
+func Test_NewDocument_Paths(t *testing.T) {
+	noop := func(w http.ResponseWriter, r *http.Request) {}
+
+	r := chi.NewRouter()
+	r.Route("/domain/examples", func(r chi.Router) {
+		r.Get("/", noop)
+		r.Get("/{id:[0-9a-f-]+}", noop)
+		r.Delete("/{id}", noop)
+	})
+	r.Mount("/static", http.NotFoundHandler())
+
+	ops := []openapi.Operation{
+		{
+			Method:     http.MethodGet,
+			Pattern:    "/domain/examples/{id:[0-9a-f-]+}",
+			ID:         "examples.detail",
+			Parameters: []*openapi.Parameter{openapi.IDParameter(), openapi.FieldsParameter("example", "title")},
+			Responses:  []openapi.Response{{Status: http.StatusOK, Schema: &openapi.Schema{Type: "object"}}},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: "/domain/examples/",
+			ID:      "examples.list",
+			Parameters: append(openapi.PageParameters(),
+				openapi.SortParameter("title"), openapi.FilterParameter("status"), openapi.IncludeParameter("owner")),
+			Responses: []openapi.Response{{Status: http.StatusOK}},
+		},
+		{Method: http.MethodPost, Pattern: "/domain/unregistered", ID: "unregistered"},
+	}
+
+	doc, err := openapi.NewDocument(&openapi.DocumentConfig{Operations: ops, Routes: r, Title: "test", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("document build error: %+v\n", err)
+	}
+
+	t.Run("paths", func(t *testing.T) {
+		var paths []string
+		for path := range doc.Paths {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+		expected := []string{"/domain/examples", "/domain/examples/{id}", "/domain/unregistered"}
+		if !slices.Equal(paths, expected) {
+			t.Errorf("expected paths %v (without mounted wildcard routes), actual %v", expected, paths)
+		}
+	})
+
+	t.Run("parameters", func(t *testing.T) {
+		detail := doc.Paths["/domain/examples/{id}"]["get"]
+		if detail == nil || detail.OperationID != "examples.detail" {
+			t.Fatalf("expected detail operation, actual %+v", detail)
+		}
+		id := detail.Parameters[0]
+		if id.Name != "id" || id.In != "path" || !id.Required || id.Schema.Format != "uuid" {
+			t.Errorf("expected required uuid id path parameter, actual %+v", id)
+		}
+		if fields := detail.Parameters[1]; fields.Name != "fields[example]" || fields.In != "query" {
+			t.Errorf("expected fields[example] query parameter, actual %+v", fields)
+		}
+
+		var names []string
+		for _, p := range doc.Paths["/domain/examples"]["get"].Parameters {
+			names = append(names, p.Name)
+		}
+		expected := []string{"page[limit]", "page[offset]", "sort", "filter[status]", "include"}
+		if !slices.Equal(names, expected) {
+			t.Errorf("expected list parameters %v, actual %v", expected, names)
+		}
+	})
+
+	t.Run("responses", func(t *testing.T) {
+		detail := doc.Paths["/domain/examples/{id}"]["get"]
+		ok := detail.Responses["200"]
+		if ok == nil || ok.Description != "OK" || ok.Content["application/vnd.api+json"].Schema == nil {
+			t.Errorf("expected 200 JSON:API response, actual %+v", ok)
+		}
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		if del := doc.Paths["/domain/examples/{id}"]["delete"]; del == nil || !del.Undocumented {
+			t.Errorf("expected undocumented delete operation, actual %+v", del)
+		}
+
+		undocumented, unregistered, err := openapi.Drift(r, ops)
+		if err != nil {
+			t.Fatalf("drift error: %+v\n", err)
+		}
+		if !slices.Equal(undocumented, []string{"DELETE /domain/examples/{id}"}) {
+			t.Errorf("expected undocumented [DELETE /domain/examples/{id}], actual %v", undocumented)
+		}
+		if !slices.Equal(unregistered, []string{"POST /domain/unregistered"}) {
+			t.Errorf("expected unregistered [POST /domain/unregistered], actual %v", unregistered)
+		}
+	})
+}
+
+func Test_QueryFields(t *testing.T) {
+	type sort struct {
+		Title   string `schema:"title"`
+		Created string `schema:"created_on,omitempty"`
+		Skipped string `schema:"-"`
+		Untaged string
+	}
+	if fields := openapi.QueryFields[sort](); !slices.Equal(fields, []string{"title", "created_on"}) {
+		t.Errorf("expected query fields [title created_on], actual %v", fields)
+	}
+}
@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/http/problem"
+)
+
+// ResourceRequest returns a JSON:API request body schema (jsonapi.RequestBody) for the given resource type and
+// attributes schema
+func ResourceRequest(resourceType string, attributes *Schema) *Schema {
+	resource := expand(reflect.TypeFor[jsonapi.RequestResource]())
+	resource.Properties["type"].Enum = []any{resourceType}
+	resource.Properties["attributes"] = attributes
+
+	body := expand(reflect.TypeFor[jsonapi.RequestBody]())
+	body.Properties["data"] = resource
+
+	return body
+}
+
+// ResourceResponse returns a JSON:API single resource response schema (jsonapi.Response)
+func ResourceResponse(resourceType string, attributes *Schema) *Schema {
+	response := expand(reflect.TypeFor[jsonapi.Response]())
+	response.Properties["data"] = responseResource(resourceType, attributes)
+
+	return response
+}
+
+// CollectionResponse returns a JSON:API resource collection response schema (jsonapi.Response)
+func CollectionResponse(resourceType string, attributes *Schema) *Schema {
+	response := expand(reflect.TypeFor[jsonapi.Response]())
+	response.Properties["data"] = &Schema{Type: "array", Items: responseResource(resourceType, attributes)}
+
+	return response
+}
+
+// RelationshipRequest returns a JSON:API to-many relationship request body schema
+// (jsonapi.RelationshipRequestBody) for the given related resource type
+func RelationshipRequest(resourceType string) *Schema {
+	body := expand(reflect.TypeFor[jsonapi.RelationshipRequestBody]())
+	body.Properties["data"] = &Schema{Type: "array", Items: resourceIdentifier(resourceType)}
+
+	return body
+}
+
+// RelationshipResponse returns a JSON:API to-many relationship response schema (jsonapi.RelationshipResponse)
+// for the given related resource type
+func RelationshipResponse(resourceType string) *Schema {
+	response := expand(reflect.TypeFor[jsonapi.RelationshipResponse]())
+	response.Properties["data"] = &Schema{Type: "array", Items: resourceIdentifier(resourceType)}
+
+	return response
+}
+
+// ErrorResponse returns a JSON:API error response (jsonapi.ErrorResponse) for the given status code, with the
+// Problem Details (problem.Details) alternate
+func ErrorResponse(status int) Response {
+	return Response{
+		Status:      status,
+		Description: http.StatusText(status),
+		Schema:      Type[jsonapi.ErrorResponse](),
+		Alternates:  map[string]*Schema{problem.MediaType: Type[problem.Details]()},
+	}
+}
+
+// responseResource returns a JSON:API response resource schema (jsonapi.ResponseResource)
+func responseResource(resourceType string, attributes *Schema) *Schema {
+	resource := expand(reflect.TypeFor[jsonapi.ResponseResource]())
+	resource.Properties["type"].Enum = []any{resourceType}
+	resource.Properties["attributes"] = attributes
+
+	return resource
+}
+
+// resourceIdentifier returns a JSON:API resource identifier schema (jsonapi.ResourceIdentifier)
+func resourceIdentifier(resourceType string) *Schema {
+	identifier := expand(reflect.TypeFor[jsonapi.ResourceIdentifier]())
+	identifier.Properties["type"].Enum = []any{resourceType}
+
+	return identifier
+}
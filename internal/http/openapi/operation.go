@@ -0,0 +1,251 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// Operation describes a single registered route for the generated document
+type Operation struct {
+	// Method defines the http method (e.g. http.MethodGet)
+	Method string
+	// Pattern defines the full chi route pattern (e.g. /domain/examples/{id})
+	Pattern    string
+	ID         string
+	Summary    string
+	Tags       []string
+	Parameters []*Parameter
+	// MediaType defines the media type of request and response bodies (optional, defaults to the JSON:API media
+	// type)
+	MediaType string
+	// Request defines the JSON request body schema (optional)
+	Request *Schema
+	// RequestAlternates defines alternate request body schemas by media type (e.g. text/csv), accepted by content
+	// negotiation (the only request body media types of operations without a Request schema)
+	RequestAlternates map[string]*Schema
+	Responses         []Response
+}
+
+// Response describes a single operation response
+type Response struct {
+	Status      int
+	Description string
+	// Schema defines the JSON response body schema (nil for responses without content)
+	Schema *Schema
+	// Alternates defines alternate response body schemas by media type (e.g. application/problem+json), of which
+	// those of success responses are produced by content negotiation
+	Alternates map[string]*Schema
+}
+
+// Resource describes a JSON:API resource collection for CRUDOperations
+type Resource struct {
+	// Name defines the operation id prefix and tag (e.g. "examples")
+	Name string
+	// Type defines the JSON:API resource type
+	Type string
+	// Attributes defines the response resource attributes schema
+	Attributes *Schema
+	// Input defines the request resource attributes (DTO) schema
+	Input *Schema
+	// Filters defines the filterable fields (filter[field])
+	Filters []string
+	// Sort defines the sortable fields
+	Sort []string
+	// DetailParameters and ListParameters define additional detail and list query parameters
+	DetailParameters []*Parameter
+	ListParameters   []*Parameter
+	// ListAlternates defines alternate list response body schemas by media type (e.g. text/csv)
+	ListAlternates map[string]*Schema
+}
+
+// CRUDOperations describes the create, delete, detail, list and update routes of a resource collection at prefix
+func CRUDOperations(prefix string, r Resource) []Operation {
+	item := prefix + "/{id}"
+	tags := []string{r.Name}
+
+	list := []*Parameter{SortParameter(r.Sort...)}
+	list = append(list, PageParameters()...)
+	for _, field := range r.Filters {
+		list = append(list, FilterParameter(field))
+	}
+	list = append(list, r.ListParameters...)
+
+	return []Operation{
+		{
+			Method:     http.MethodGet,
+			Pattern:    prefix,
+			ID:         r.Name + ".list",
+			Summary:    fmt.Sprintf("List %s resources", r.Type),
+			Tags:       tags,
+			Parameters: list,
+			Responses: []Response{
+				{Status: http.StatusOK, Schema: CollectionResponse(r.Type, r.Attributes), Alternates: r.ListAlternates},
+				ErrorResponse(http.StatusBadRequest),
+				ErrorResponse(http.StatusInternalServerError),
+			},
+		},
+		{
+			Method:     http.MethodGet,
+			Pattern:    item,
+			ID:         r.Name + ".detail",
+			Summary:    fmt.Sprintf("Get %s resource", r.Type),
+			Tags:       tags,
+			Parameters: append([]*Parameter{IDParameter()}, r.DetailParameters...),
+			Responses: []Response{
+				{Status: http.StatusOK, Schema: ResourceResponse(r.Type, r.Attributes)},
+				ErrorResponse(http.StatusBadRequest),
+				ErrorResponse(http.StatusNotFound),
+				ErrorResponse(http.StatusInternalServerError),
+			},
+		},
+		{
+			Method:  http.MethodPost,
+			Pattern: prefix,
+			ID:      r.Name + ".create",
+			Summary: fmt.Sprintf("Create %s resource", r.Type),
+			Tags:    tags,
+			Request: ResourceRequest(r.Type, r.Input),
+			Responses: []Response{
+				{Status: http.StatusCreated, Schema: ResourceResponse(r.Type, r.Attributes)},
+				ErrorResponse(http.StatusBadRequest),
+				ErrorResponse(http.StatusConflict),
+				ErrorResponse(http.StatusRequestEntityTooLarge),
+				ErrorResponse(http.StatusInternalServerError),
+			},
+		},
+		{
+			Method:     http.MethodPut,
+			Pattern:    item,
+			ID:         r.Name + ".update",
+			Summary:    fmt.Sprintf("Update %s resource", r.Type),
+			Tags:       tags,
+			Parameters: []*Parameter{IDParameter()},
+			Request:    ResourceRequest(r.Type, r.Input),
+			Responses: []Response{
+				{Status: http.StatusOK, Schema: ResourceResponse(r.Type, r.Attributes)},
+				ErrorResponse(http.StatusBadRequest),
+				ErrorResponse(http.StatusNotFound),
+				ErrorResponse(http.StatusConflict),
+				ErrorResponse(http.StatusRequestEntityTooLarge),
+				ErrorResponse(http.StatusInternalServerError),
+			},
+		},
+		{
+			Method:     http.MethodDelete,
+			Pattern:    item,
+			ID:         r.Name + ".delete",
+			Summary:    fmt.Sprintf("Delete %s resource", r.Type),
+			Tags:       tags,
+			Parameters: []*Parameter{IDParameter()},
+			Responses: []Response{
+				{Status: http.StatusNoContent},
+				ErrorResponse(http.StatusBadRequest),
+				ErrorResponse(http.StatusNotFound),
+				ErrorResponse(http.StatusInternalServerError),
+			},
+		},
+	}
+}
+
+// IDParameter returns the uuid resource id path parameter
+func IDParameter() *Parameter {
+	return &Parameter{
+		Name:        "id",
+		In:          "path",
+		Description: "resource id",
+		Required:    true,
+		Schema:      &Schema{Type: "string", Format: "uuid"},
+	}
+}
+
+// PageParameters returns the page[limit] and page[offset] query parameters
+func PageParameters() []*Parameter {
+	limit, offset := float64(1), float64(0)
+
+	return []*Parameter{
+		{
+			Name:        "page[limit]",
+			In:          "query",
+			Description: "maximum number of resources returned (defaults to the configured page limit)",
+			Schema:      &Schema{Type: "integer", Minimum: &limit},
+		},
+		{
+			Name:        "page[offset]",
+			In:          "query",
+			Description: "number of resources skipped",
+			Schema:      &Schema{Type: "integer", Minimum: &offset},
+		},
+	}
+}
+
+// SortParameter returns the sort query parameter for the given sortable fields
+func SortParameter(fields ...string) *Parameter {
+	return &Parameter{
+		Name: "sort",
+		In:   "query",
+		Description: fmt.Sprintf("comma-separated sort fields, each prefixed with '-' for descending order "+
+			"(e.g. sort=-%[1]s), or sort[n][field]=asc|desc (fields: %[2]s)", first(fields), strings.Join(fields, ", ")),
+		Schema: &Schema{Type: "string"},
+	}
+}
+
+// FilterParameter returns the filter[field] query parameter for the given filterable field
+func FilterParameter(field string) *Parameter {
+	return &Parameter{
+		Name:        fmt.Sprintf("filter[%s]", field),
+		In:          "query",
+		Description: fmt.Sprintf("filter resources by %s", field),
+		Schema:      &Schema{Type: "string"},
+	}
+}
+
+// IncludeParameter returns the include query parameter for the given relationship paths
+func IncludeParameter(paths ...string) *Parameter {
+	return &Parameter{
+		Name:        "include",
+		In:          "query",
+		Description: fmt.Sprintf("comma-separated related resources to include (paths: %s)", strings.Join(paths, ", ")),
+		Schema:      &Schema{Type: "string"},
+	}
+}
+
+// FieldsParameter returns the fields[type] sparse fieldset query parameter for the given resource type and fields
+func FieldsParameter(resourceType string, fields ...string) *Parameter {
+	return &Parameter{
+		Name:        fmt.Sprintf("fields[%s]", resourceType),
+		In:          "query",
+		Description: fmt.Sprintf("comma-separated fields to return (fields: %s)", strings.Join(fields, ", ")),
+		Schema:      &Schema{Type: "string"},
+	}
+}
+
+// QueryParameter returns an optional query parameter with the given schema
+func QueryParameter(name, description string, schema *Schema) *Parameter {
+	return &Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// QueryFields returns the query parameter field names of T, defined by gorilla/schema `schema` struct tags
+// (e.g. the sortable fields of a sort entry type)
+func QueryFields[T any]() []string {
+	t := reflect.TypeFor[T]()
+
+	var fields []string
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("schema"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// first returns the first of the given values, or an empty string
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
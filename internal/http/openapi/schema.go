@@ -0,0 +1,319 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+	timeType          = reflect.TypeFor[time.Time]()
+	uuidType          = reflect.TypeFor[uuid.UUID]()
+)
+
+// componentNamePattern defines the characters not allowed in component schema names
+var componentNamePattern = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Type returns a schema for the go type T, added to the document components (by type name) when the document
+// is built. Struct field constraints are derived from `json` and go-playground `validate` struct tags
+func Type[T any]() *Schema {
+	return &Schema{goType: reflect.TypeFor[T]()}
+}
+
+// schemaOf returns the schema of a struct field or collection element type, deferring named structs to
+// component references
+func schemaOf(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	if t.Kind() == reflect.Pointer {
+		return nullable(schemaOf(t.Elem()))
+	}
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		return &Schema{goType: t}
+	}
+	if implements(t, jsonMarshalerType) {
+		return &Schema{}
+	}
+	if implements(t, textMarshalerType) {
+		return &Schema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer", Format: intFormat(t)}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		minimum := float64(0)
+		return &Schema{Type: "integer", Format: intFormat(t), Minimum: &minimum}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(t.Elem())}
+	case reflect.Struct:
+		return expand(t)
+	default:
+		// interfaces (e.g. `any` attributes) accept any value
+		return &Schema{}
+	}
+}
+
+// expand returns the object schema of a struct type, with properties named by `json` struct tags
+func expand(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		// exported fields of unexported embedded structs are promoted, as by encoding/json
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// embedded structs without a json name are flattened, as by encoding/json
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded := expand(ft)
+				for k, v := range embedded.Properties {
+					s.Properties[k] = v
+				}
+				s.Required = append(s.Required, embedded.Required...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := schemaOf(field.Type)
+		if applyValidation(prop, field.Type, field.Tag.Get("validate")) {
+			s.Required = append(s.Required, name)
+			// required pointer fields are not nullable
+			if field.Type.Kind() == reflect.Pointer {
+				prop = nonNullable(prop)
+			}
+		}
+		s.Properties[name] = prop
+	}
+	slices.Sort(s.Required)
+
+	return s
+}
+
+// applyValidation applies go-playground `validate` tag constraints to a field schema, and reports whether
+// the field is required
+func applyValidation(s *Schema, t reflect.Type, tag string) bool {
+	if tag == "" {
+		return false
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var required bool
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "dive":
+			// remaining rules apply to collection elements
+			return required
+		case "required":
+			required = true
+		case "min", "gte":
+			setBound(s, t, param, true)
+		case "max", "lte":
+			setBound(s, t, param, false)
+		case "len":
+			setBound(s, t, param, true)
+			setBound(s, t, param, false)
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				s.Enum = append(s.Enum, enumValue(t, v))
+			}
+		case "uuid", "uuid4":
+			s.Format = "uuid"
+		case "email":
+			s.Format = "email"
+		case "url", "uri":
+			s.Format = "uri"
+		case "alphanum":
+			s.Pattern = "^[a-zA-Z0-9]*$"
+		}
+	}
+
+	return required
+}
+
+// setBound sets a minimum (or maximum) length, item count or value constraint, depending on the field kind
+func setBound(s *Schema, t reflect.Type, param string, lower bool) {
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return
+		}
+		switch {
+		case t.Kind() == reflect.String && lower:
+			s.MinLength = &n
+		case t.Kind() == reflect.String:
+			s.MaxLength = &n
+		case lower:
+			s.MinItems = &n
+		default:
+			s.MaxItems = &n
+		}
+	default:
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		if lower {
+			s.Minimum = &n
+		} else {
+			s.Maximum = &n
+		}
+	}
+}
+
+// enumValue returns a `oneof` tag value typed for the field kind
+func enumValue(t reflect.Type, v string) any {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+// nullable returns the given schema allowing null values
+func nullable(s *Schema) *Schema {
+	switch typ := s.Type.(type) {
+	case string:
+		s.Type = []string{typ, "null"}
+		return s
+	case nil:
+		if s.goType == nil && s.Ref == "" && s.AnyOf == nil {
+			// schemas without a type already accept null
+			return s
+		}
+	}
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+// nonNullable reverses nullable
+func nonNullable(s *Schema) *Schema {
+	if typ, ok := s.Type.([]string); ok && len(typ) == 2 {
+		s.Type = typ[0]
+		return s
+	}
+	if len(s.AnyOf) == 2 && s.AnyOf[1].Type == "null" {
+		return s.AnyOf[0]
+	}
+	return s
+}
+
+// implements reports whether the type (or a pointer to it) implements the given interface
+func implements(t, iface reflect.Type) bool {
+	return t.Implements(iface) || reflect.PointerTo(t).Implements(iface)
+}
+
+// intFormat returns the OpenAPI format of an integer type
+func intFormat(t reflect.Type) string {
+	if t.Bits() == 64 {
+		return "int64"
+	}
+	return "int32"
+}
+
+// registry resolves go type schemas to component references
+type registry struct {
+	names   map[reflect.Type]string
+	schemas map[string]*Schema
+}
+
+// resolve returns the given schema with deferred go type schemas replaced by component references
+func (reg *registry) resolve(s *Schema) *Schema {
+	if s == nil {
+		return nil
+	}
+	if t := s.goType; t != nil {
+		if t.Kind() != reflect.Struct || t.Name() == "" {
+			return reg.resolve(schemaOf(t))
+		}
+		return &Schema{Ref: "#/components/schemas/" + reg.component(t)}
+	}
+
+	// described schemas are copied rather than modified, as operations may be resolved by multiple documents
+	resolved := *s
+	if s.Properties != nil {
+		resolved.Properties = make(map[string]*Schema, len(s.Properties))
+		for k, v := range s.Properties {
+			resolved.Properties[k] = reg.resolve(v)
+		}
+	}
+	if s.AnyOf != nil {
+		resolved.AnyOf = make([]*Schema, 0, len(s.AnyOf))
+		for _, v := range s.AnyOf {
+			resolved.AnyOf = append(resolved.AnyOf, reg.resolve(v))
+		}
+	}
+	resolved.Items = reg.resolve(s.Items)
+	resolved.AdditionalProperties = reg.resolve(s.AdditionalProperties)
+
+	return &resolved
+}
+
+// component adds the schema of the given named struct type to the registry (once), returning its component name
+func (reg *registry) component(t reflect.Type) string {
+	if name, ok := reg.names[t]; ok {
+		return name
+	}
+
+	name := componentNamePattern.ReplaceAllString(t.Name(), "_")
+	if _, taken := reg.schemas[name]; taken {
+		name = fmt.Sprintf("%s.%s", path.Base(t.PkgPath()), name)
+	}
+	reg.names[t] = name
+	reg.schemas[name] = &Schema{} // placeholder for recursive types
+	reg.schemas[name] = reg.resolve(expand(t))
+
+	return name
+}
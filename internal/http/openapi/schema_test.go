@@ -0,0 +1,150 @@
+package openapi_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/http/openapi"
+)
+
+type schemaAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type schemaBase struct {
+	ID uuid.UUID `json:"id"`
+}
+
+type schemaDTO struct {
+	schemaBase
+	Title    string            `json:"title" validate:"required,min=2,max=255"`
+	Status   string            `json:"status" validate:"oneof=draft published"`
+	Count    *int32            `json:"count" validate:"omitempty,gte=1,lte=10"`
+	Priority int64             `json:"priority" validate:"oneof=1 2 3"`
+	Tags     []string          `json:"tags" validate:"max=5,dive,alphanum"`
+	Email    *string           `json:"email" validate:"required,email"`
+	Address  *schemaAddress    `json:"address"`
+	Labels   map[string]string `json:"labels"`
+	Created  time.Time         `json:"created_on"`
+	Secret   string            `json:"-"`
+	internal string
+}
+
+// document returns a document with a single operation requesting and responding with the given schema
+func document(t *testing.T, schema *openapi.Schema) *openapi.Document {
+	t.Helper()
+
+	doc, err := openapi.NewDocument(&openapi.DocumentConfig{
+		Operations: []openapi.Operation{{
+			Method:    http.MethodPost,
+			Pattern:   "/things",
+			Request:   schema,
+			Responses: []openapi.Response{{Status: http.StatusCreated, Schema: schema}},
+		}},
+		Routes:  chi.NewRouter(),
+		Title:   "test",
+		Version: "1.0.0",
+	})
+	if err != nil {
+		t.Fatalf("document build error: %+v\n", err)
+	}
+	return doc
+}
+
+func Test_Schema_Type(t *testing.T) {
+	doc := document(t, openapi.Type[schemaDTO]())
+
+	op := doc.Paths["/things"]["post"]
+	if ref := op.RequestBody.Content["application/vnd.api+json"].Schema.Ref; ref != "#/components/schemas/schemaDTO" {
+		t.Fatalf("expected request schema reference, actual '%s'", ref)
+	}
+	s := doc.Components.Schemas["schemaDTO"]
+	if s == nil {
+		t.Fatalf("expected schemaDTO component schema")
+	}
+
+	t.Run("properties", func(t *testing.T) {
+		for _, name := range []string{"id", "title", "status", "count", "priority", "tags", "email", "address", "labels", "created_on"} {
+			if s.Properties[name] == nil {
+				t.Errorf("expected property '%s'", name)
+			}
+		}
+		for _, name := range []string{"Secret", "-", "internal", "schemaBase"} {
+			if s.Properties[name] != nil {
+				t.Errorf("expected no property '%s'", name)
+			}
+		}
+	})
+
+	t.Run("required", func(t *testing.T) {
+		if !slices.Equal(s.Required, []string{"email", "title"}) {
+			t.Errorf("expected required [email title], actual %v", s.Required)
+		}
+	})
+
+	t.Run("formats", func(t *testing.T) {
+		tests := map[string][2]any{
+			"id":         {"string", "uuid"},
+			"created_on": {"string", "date-time"},
+			"priority":   {"integer", "int64"},
+			"email":      {"string", "email"},
+		}
+		for name, expected := range tests {
+			p := s.Properties[name]
+			if p.Type != expected[0] || p.Format != expected[1] {
+				t.Errorf("expected '%s' type %v format %v, actual %v %v", name, expected[0], expected[1], p.Type, p.Format)
+			}
+		}
+	})
+
+	t.Run("bounds", func(t *testing.T) {
+		title := s.Properties["title"]
+		if title.MinLength == nil || *title.MinLength != 2 || title.MaxLength == nil || *title.MaxLength != 255 {
+			t.Errorf("expected title length 2-255, actual %+v", title)
+		}
+		count := s.Properties["count"]
+		if count.Minimum == nil || *count.Minimum != 1 || count.Maximum == nil || *count.Maximum != 10 {
+			t.Errorf("expected count range 1-10, actual %+v", count)
+		}
+		tags := s.Properties["tags"]
+		if tags.Type != "array" || tags.MaxItems == nil || *tags.MaxItems != 5 || tags.Items.Pattern != "" {
+			t.Errorf("expected tags with max 5 items (element rules after dive not applied), actual %+v", tags)
+		}
+	})
+
+	t.Run("enums", func(t *testing.T) {
+		if enum := s.Properties["status"].Enum; !slices.Equal(enum, []any{"draft", "published"}) {
+			t.Errorf("expected status enum [draft published], actual %v", enum)
+		}
+		if enum := s.Properties["priority"].Enum; !slices.Equal(enum, []any{int64(1), int64(2), int64(3)}) {
+			t.Errorf("expected priority enum [1 2 3], actual %v", enum)
+		}
+	})
+
+	t.Run("nullable", func(t *testing.T) {
+		if typ, ok := s.Properties["count"].Type.([]string); !ok || !slices.Equal(typ, []string{"integer", "null"}) {
+			t.Errorf("expected nullable count, actual %v", s.Properties["count"].Type)
+		}
+		if s.Properties["email"].Type != "string" {
+			t.Errorf("expected required pointer email to be non-nullable, actual %v", s.Properties["email"].Type)
+		}
+		address := s.Properties["address"]
+		if len(address.AnyOf) != 2 || address.AnyOf[0].Ref != "#/components/schemas/schemaAddress" {
+			t.Errorf("expected nullable address reference, actual %+v", address)
+		}
+	})
+
+	t.Run("components", func(t *testing.T) {
+		address := doc.Components.Schemas["schemaAddress"]
+		if address == nil || !slices.Equal(address.Required, []string{"city"}) {
+			t.Errorf("expected schemaAddress component with required city, actual %+v", address)
+		}
+		if labels := s.Properties["labels"]; labels.Type != "object" || labels.AdditionalProperties.Type != "string" {
+			t.Errorf("expected labels string map, actual %+v", labels)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package openapi
+
+import (
+	_ "embed"
+	"html/template"
+	"net/http"
+
+	"github.com/jasonsites/gosk/internal/app"
+)
+
+//go:embed viewer.html
+var viewerHTML string
+
+// viewerTemplate renders the embedded document viewer page (Redoc, loaded from a version pinned URL with a
+// subresource integrity hash)
+var viewerTemplate = template.Must(template.New("viewer").Parse(viewerHTML))
+
+// ViewerConfig defines the input to ViewerHandler
+type ViewerConfig struct {
+	// Integrity defines the subresource integrity hash of the viewer script (e.g. sha384-...)
+	Integrity string `validate:"required,startswith=sha384-|startswith=sha512-"`
+	// Script defines the (version pinned) URL of the Redoc viewer bundle
+	Script  string `validate:"required,url"`
+	SpecURL string `validate:"required"`
+	Title   string `validate:"required"`
+}
+
+// ViewerHandler returns a handler serving a document viewer page for the document at the configured spec URL
+func ViewerHandler(c *ViewerConfig) http.HandlerFunc {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		panic(err)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := viewerTemplate.Execute(w, c); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package openapi_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/internal/http/openapi"
+)
+
+func Test_ViewerHandler(t *testing.T) {
+	conf := &openapi.ViewerConfig{
+		Integrity: "sha384-abc",
+		Script:    "https://cdn.example.com/redoc/v2.1.5/redoc.standalone.js",
+		SpecURL:   "/domain/openapi.json",
+		Title:     "test",
+	}
+
+	rec := httptest.NewRecorder()
+	openapi.ViewerHandler(conf)(rec, httptest.NewRequest(http.MethodGet, "/domain/docs", nil))
+
+	body := rec.Body.String()
+	expected := `<script src="https://cdn.example.com/redoc/v2.1.5/redoc.standalone.js" integrity="sha384-abc" crossorigin="anonymous">`
+	if rec.Code != http.StatusOK || !strings.Contains(body, expected) || !strings.Contains(body, conf.SpecURL) {
+		t.Errorf("expected viewer page with pinned script integrity, actual '%d' '%s'", rec.Code, body)
+	}
+
+	t.Run("integrity", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected viewer without script integrity to panic")
+			}
+		}()
+		openapi.ViewerHandler(&openapi.ViewerConfig{Script: conf.Script, SpecURL: conf.SpecURL, Title: conf.Title})
+	})
+}
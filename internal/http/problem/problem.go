@@ -0,0 +1,36 @@
+package problem
+
+// MediaType defines the RFC 9457 Problem Details media type
+const MediaType = "application/problem+json"
+
+// DefaultType defines the problem type of problems without a type URI
+const DefaultType = "about:blank"
+
+// Details defines an RFC 9457 Problem Details object, with the code, trace_id, errors (validation errors) and meta
+// extension members
+type Details struct {
+	// Type defines the problem type URI (e.g. https://docs.example.com/errors/not_found)
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	// Status defines the HTTP status code
+	Status int    `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Instance defines the URI reference of the request (the request path and query)
+	Instance string `json:"instance,omitempty"`
+	// Code defines the stable machine-readable error code (e.g. validation_failed)
+	Code string `json:"code"`
+	// TraceID defines the request trace id
+	TraceID string         `json:"trace_id,omitempty"`
+	Errors  []FieldError   `json:"errors,omitempty"`
+	Meta    map[string]any `json:"meta,omitempty"`
+}
+
+// FieldError defines a single invalid request field of a validation problem, located by a JSON pointer (request
+// body), a query parameter name or a request header name
+type FieldError struct {
+	Code      string `json:"code"`
+	Detail    string `json:"detail"`
+	Pointer   string `json:"pointer,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Header    string `json:"header,omitempty"`
+}
@@ -8,6 +8,9 @@ type Correlation struct {
 	TraceID ContextKey // TODO: consider uuid.UUID
 }
 
+// UnknownTraceID defines the trace ID of operation contexts without correlation data
+const UnknownTraceID = "unknown"
+
 // TraceIDContextKey defines the context key used for tracking operation trace ID
 const TraceIDContextKey ContextKey = "trace_id"
 
@@ -24,7 +27,7 @@ func GetTraceIDFromContext(ctx context.Context) string {
 	val := ctx.Value(TraceIDContextKey)
 	traceID, ok := val.(string)
 	if !ok {
-		traceID = "unknown"
+		traceID = UnknownTraceID
 	}
 	return traceID
 }
@@ -0,0 +1,187 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/database"
+)
+
+// DefaultQueue defines the queue used when none is specified on enqueue
+const DefaultQueue = "default"
+
+// DefaultMaxAttempts defines the maximum attempts used when none is specified on enqueue
+const DefaultMaxAttempts = 5
+
+// enqueueAttempts defines the maximum insert attempts of unique jobs whose conflicting job finishes before it is read
+const enqueueAttempts = 3
+
+// Status represents the job_status enum from the database
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusRunning   Status = "running"
+	StatusSucceeded Status = "succeeded"
+	StatusDead      Status = "dead"
+)
+
+// Job defines a single queued unit of work
+type Job struct {
+	Attempts    int
+	CreatedOn   time.Time
+	ID          uuid.UUID
+	Kind        string
+	LastError   *string
+	MaxAttempts int
+	Payload     json.RawMessage
+	Queue       string
+	// Result defines the result recorded by the job handler with SetResult (nil when none is recorded)
+	Result    json.RawMessage
+	RunAt     time.Time
+	Status    Status
+	UniqueKey *string
+}
+
+// EnqueueOptions defines optional parameters for a single enqueued job
+type EnqueueOptions struct {
+	// MaxAttempts defines the number of attempts before the job is dead-lettered
+	MaxAttempts int
+	// Queue defines the queue the job is enqueued on (default "default")
+	Queue string
+	// RunAt schedules the job to run no earlier than the given time (default now)
+	RunAt time.Time
+	// UniqueKey deduplicates jobs of the same kind while a matching job is pending or running
+	UniqueKey string
+}
+
+// Enqueuer defines the interface for enqueuing jobs from services
+type Enqueuer interface {
+	Enqueue(ctx context.Context, kind string, payload any, opts *EnqueueOptions) (*Job, error)
+}
+
+// QueueConfig defines the input to NewQueue
+type QueueConfig struct {
+	DBRouter *database.Router `validate:"required"`
+}
+
+// Queue enqueues jobs on the `jobs` table
+type Queue struct {
+	db *database.Router
+}
+
+// NewQueue returns a new Queue instance
+func NewQueue(c *QueueConfig) (*Queue, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	return &Queue{db: c.DBRouter}, nil
+}
+
+// Enqueue enqueues a job of the given kind, returning the existing job if one with the same
+// unique key is already pending or running
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any, opts *EnqueueOptions) (*Job, error) {
+	return q.EnqueueTx(ctx, q.db.Write(ctx), kind, payload, opts)
+}
+
+// EnqueueTx enqueues a job using the given db (e.g. a transaction), so that the job is only
+// visible to workers once the surrounding transaction commits
+func (q *Queue) EnqueueTx(ctx context.Context, db database.DB, kind string, payload any, opts *EnqueueOptions) (*Job, error) {
+	if opts == nil {
+		opts = &EnqueueOptions{}
+	}
+	if kind == "" {
+		return nil, fmt.Errorf("job kind required")
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("job payload marshal error: %w", err)
+	}
+
+	var (
+		maxAttempts = opts.MaxAttempts
+		queue       = opts.Queue
+		runAt       = opts.RunAt
+		uniqueKey   *string
+	)
+	if maxAttempts < 1 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	if queue == "" {
+		queue = DefaultQueue
+	}
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+	if opts.UniqueKey != "" {
+		uniqueKey = &opts.UniqueKey
+	}
+
+	query := `INSERT INTO jobs (queue, kind, payload, unique_key, max_attempts, run_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL AND status IN ('pending', 'running') DO NOTHING
+		RETURNING ` + jobColumns
+
+	existing := "SELECT " + jobColumns + ` FROM jobs
+		WHERE kind = $1 AND unique_key = $2 AND status IN ('pending', 'running')`
+
+	// the conflicting job may finish between the insert and the select, so retry both (bounded)
+	var job *Job
+	for range enqueueAttempts {
+		job, err = scanJob(db.QueryRow(ctx, query, queue, kind, data, uniqueKey, maxAttempts, runAt))
+		if !errors.Is(err, pgx.ErrNoRows) || uniqueKey == nil {
+			break
+		}
+		job, err = scanJob(db.QueryRow(ctx, existing, kind, *uniqueKey))
+		if !errors.Is(err, pgx.ErrNoRows) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("job enqueue error: %w", err)
+	}
+
+	return job, nil
+}
+
+// Get returns the job with the given id (pgx.ErrNoRows when none exists), read from the primary so that newly
+// enqueued jobs are always found
+func (q *Queue) Get(ctx context.Context, id uuid.UUID) (*Job, error) {
+	ctx = database.WithPrimary(ctx)
+	query := "SELECT " + jobColumns + " FROM jobs WHERE id = $1"
+	return scanJob(q.db.Read(ctx).QueryRow(ctx, query, id))
+}
+
+// jobColumns defines the columns returned for a Job
+const jobColumns = "id, queue, kind, payload, unique_key, status, attempts, max_attempts, last_error, result, run_at, created_on"
+
+// scanJob scans a single row of jobColumns into a Job
+func scanJob(row pgx.Row) (*Job, error) {
+	job := &Job{}
+	if err := row.Scan(
+		&job.ID,
+		&job.Queue,
+		&job.Kind,
+		&job.Payload,
+		&job.UniqueKey,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.Result,
+		&job.RunAt,
+		&job.CreatedOn,
+	); err != nil {
+		return nil, err
+	}
+
+	return job, nil
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/scheduler"
+)
+
+// PruneTask returns a scheduled task deleting succeeded and dead jobs finished before the retention period
+func PruneTask(db *pgxpool.Pool, log *logger.CustomLogger, schedule string, retention time.Duration) scheduler.Task {
+	return scheduler.Task{
+		Name:     "jobs.prune",
+		Schedule: schedule,
+		Run: func(ctx context.Context) error {
+			tag, err := db.Exec(ctx,
+				"DELETE FROM jobs WHERE status IN ('succeeded', 'dead') AND finished_on < now() - $1::interval",
+				retention,
+			)
+			if err != nil {
+				return err
+			}
+
+			traceID := trace.GetTraceIDFromContext(ctx)
+			log.CreateContextLogger(traceID).Info(fmt.Sprintf("pruned %d finished jobs", tag.RowsAffected()))
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,313 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/logger"
+)
+
+// Handler runs a single job, returning an error to retry (or dead-letter) the job
+type Handler func(ctx context.Context, job *Job) error
+
+// resultContextKey defines the context key of the result recorded by a running job handler
+type resultContextKey struct{}
+
+// SetResult records the result of the running job (marshaled as JSON), stored with the job when it succeeds or is
+// dead-lettered. It has no effect outside of a job handler
+func SetResult(ctx context.Context, result any) {
+	if holder, ok := ctx.Value(resultContextKey{}).(*any); ok {
+		*holder = result
+	}
+}
+
+// BackoffFunc returns the delay before the given (1-based) attempt is retried
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff returns a BackoffFunc doubling the base delay on each attempt, up to maxDelay
+func ExponentialBackoff(base, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := time.Duration(float64(base) * math.Pow(2, float64(attempt-1)))
+		if d <= 0 || d > maxDelay {
+			return maxDelay
+		}
+		return d
+	}
+}
+
+// WorkerConfig defines the input to NewWorker
+type WorkerConfig struct {
+	// Backoff defines the retry delay for failed jobs (default exponential from 1s up to 1h)
+	Backoff     BackoffFunc
+	Concurrency int                  `validate:"required,min=1"`
+	DBClient    *pgxpool.Pool        `validate:"required"`
+	Logger      *logger.CustomLogger `validate:"required"`
+	// PollInterval defines the delay between claim attempts when all queues are empty
+	PollInterval time.Duration `validate:"required"`
+	Queues       []string      `validate:"required,min=1"`
+	// Timeout defines the maximum run time of a single job, after which running jobs are
+	// considered abandoned (e.g. by a crashed worker) and are retried
+	Timeout time.Duration `validate:"required"`
+}
+
+// Worker claims and runs jobs from the configured queues
+type Worker struct {
+	backoff      BackoffFunc
+	concurrency  int
+	db           *pgxpool.Pool
+	handlers     map[string]Handler
+	id           string
+	logger       *logger.CustomLogger
+	mutex        sync.RWMutex
+	pollInterval time.Duration
+	queues       []string
+	timeout      time.Duration
+}
+
+// NewWorker returns a new Worker instance
+func NewWorker(c *WorkerConfig) (*Worker, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	backoff := c.Backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff(time.Second, time.Hour)
+	}
+
+	host, _ := os.Hostname()
+
+	worker := &Worker{
+		backoff:      backoff,
+		concurrency:  c.Concurrency,
+		db:           c.DBClient,
+		handlers:     make(map[string]Handler),
+		id:           fmt.Sprintf("%s:%d:%s", host, os.Getpid(), uuid.NewString()[:8]),
+		logger:       c.Logger,
+		pollInterval: c.PollInterval,
+		queues:       c.Queues,
+		timeout:      c.Timeout,
+	}
+
+	return worker, nil
+}
+
+// Register registers the handler for the given job kind, and must be called before Run
+func (w *Worker) Register(kind string, h Handler) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.handlers[kind] = h
+}
+
+// Run claims and runs jobs until the given context is done, then waits for in-flight jobs to complete
+func (w *Worker) Run(ctx context.Context) error {
+	w.logger.Log.Info(fmt.Sprintf("worker %s started (queues: %v, concurrency: %d)", w.id, w.queues, w.concurrency))
+
+	var wg sync.WaitGroup
+	for range w.concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.rescueLoop(ctx)
+	}()
+
+	wg.Wait()
+	w.logger.Log.Info(fmt.Sprintf("worker %s stopped", w.id))
+
+	return nil
+}
+
+// loop claims and runs jobs one at a time, sleeping for the poll interval while queues are empty
+func (w *Worker) loop(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.claim(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) && ctx.Err() == nil {
+			w.logger.Log.Error(fmt.Sprintf("job claim error: %s", err.Error()))
+		}
+		if job != nil {
+			w.run(context.WithoutCancel(ctx), job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.pollInterval):
+		}
+	}
+}
+
+// claim claims the next due job on the configured queues, skipping jobs locked by other workers
+func (w *Worker) claim(ctx context.Context) (*Job, error) {
+	query := `UPDATE jobs SET status = 'running', attempts = attempts + 1, locked_by = $2, locked_on = now(), modified_on = now()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE queue = ANY($1) AND status = 'pending' AND run_at <= now()
+			ORDER BY run_at, created_on
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	return scanJob(w.db.QueryRow(ctx, query, w.queues, w.id))
+}
+
+// run runs the handler for a single claimed job, recording the outcome
+func (w *Worker) run(ctx context.Context, job *Job) {
+	ctx = trace.CreateOpContext(ctx, job.ID.String())
+	log := w.logger.CreateContextLogger(job.ID.String()).With(
+		slog.String("kind", job.Kind),
+		slog.Int("attempt", job.Attempts),
+	)
+
+	w.mutex.RLock()
+	handler, ok := w.handlers[job.Kind]
+	w.mutex.RUnlock()
+
+	var result any
+	start := time.Now()
+	err := fmt.Errorf("no handler registered for job kind '%s'", job.Kind)
+	if ok {
+		err = w.call(context.WithValue(ctx, resultContextKey{}, &result), handler, job)
+	}
+
+	data, mErr := marshalResult(result)
+	if mErr != nil {
+		log.Error(fmt.Sprintf("job result marshal error: %s", mErr.Error()))
+	}
+
+	if err == nil {
+		ok, dbErr := w.finish(ctx, job,
+			`status = 'succeeded', locked_by = NULL, last_error = NULL, result = $3, finished_on = now()`,
+			data,
+		)
+		if !w.finished(log, ok, dbErr, "job completion error") {
+			return
+		}
+		log.Info("job succeeded", slog.Duration("duration", time.Since(start)))
+		return
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		ok, dbErr := w.finish(ctx, job,
+			`status = 'dead', locked_by = NULL, last_error = $3, result = $4, finished_on = now()`,
+			err.Error(), data,
+		)
+		if !w.finished(log, ok, dbErr, "job dead-letter error") {
+			return
+		}
+		log.Error(fmt.Sprintf("job dead-lettered after %d attempts: %s", job.Attempts, err.Error()))
+		return
+	}
+
+	delay := w.backoff(job.Attempts)
+	ok, dbErr := w.finish(ctx, job,
+		`status = 'pending', locked_by = NULL, locked_on = NULL, last_error = $3, run_at = now() + $4::interval`,
+		err.Error(), delay,
+	)
+	if !w.finished(log, ok, dbErr, "job retry error") {
+		return
+	}
+	log.Warn(fmt.Sprintf("job failed, retrying in %s: %s", delay, err.Error()))
+}
+
+// finish records the outcome of a job (the given SET assignments, with arguments from $3) only while the job is
+// still running and locked by this worker, returning false when the job has been reclaimed (e.g. rescued as
+// abandoned after exceeding the job timeout, and claimed by another worker)
+func (w *Worker) finish(ctx context.Context, job *Job, set string, args ...any) (bool, error) {
+	query := `UPDATE jobs SET ` + set + `, modified_on = now()
+		WHERE id = $1 AND locked_by = $2 AND status = 'running'`
+
+	tag, err := w.db.Exec(ctx, query, append([]any{job.ID, w.id}, args...)...)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// finished logs a failure to record a job outcome, returning true when the outcome was recorded
+func (w *Worker) finished(log *slog.Logger, ok bool, err error, msg string) bool {
+	if err != nil {
+		log.Error(fmt.Sprintf("%s: %s", msg, err.Error()))
+		return false
+	}
+	if !ok {
+		log.Warn(fmt.Sprintf("job lock lost to reclaim, outcome discarded (worker %s)", w.id))
+		return false
+	}
+	return true
+}
+
+// marshalResult marshals a recorded job result (nil when none is recorded)
+func marshalResult(result any) ([]byte, error) {
+	if result == nil {
+		return nil, nil
+	}
+	return json.Marshal(result)
+}
+
+// call runs the handler with the job timeout, recovering handler panics as errors
+func (w *Worker) call(ctx context.Context, handler Handler, job *Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("job panic: %v", rec)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// rescueLoop periodically returns abandoned running jobs (locked for twice the job timeout) to pending,
+// dead-lettering those without remaining attempts
+func (w *Worker) rescueLoop(ctx context.Context) {
+	ticker := time.NewTicker(w.timeout)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		tag, err := w.db.Exec(ctx,
+			`UPDATE jobs SET
+				status = CASE WHEN attempts >= max_attempts THEN 'dead'::job_status ELSE 'pending'::job_status END,
+				finished_on = CASE WHEN attempts >= max_attempts THEN now() END,
+				locked_by = NULL, locked_on = NULL, last_error = 'job abandoned', modified_on = now()
+			WHERE status = 'running' AND locked_on < now() - $1::interval`,
+			2*w.timeout,
+		)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.logger.Log.Error(fmt.Sprintf("job rescue error: %s", err.Error()))
+			}
+			continue
+		}
+		if n := tag.RowsAffected(); n > 0 {
+			w.logger.Log.Warn(fmt.Sprintf("rescued %d abandoned jobs", n))
+		}
+	}
+}
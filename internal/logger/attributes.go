@@ -15,13 +15,14 @@ type AppAttrKeys struct {
 }
 
 type HTTPAttrKeys struct {
-	Body     string
-	BodySize string
-	Header   string
-	Method   string
-	Path     string
-	Query    string
-	Status   string
+	Body          string
+	BodySize      string
+	BodyTruncated string
+	Header        string
+	Method        string
+	Path          string
+	Query         string
+	Status        string
 }
 
 var AttrKey = AttrKeys{
@@ -30,13 +31,14 @@ var AttrKey = AttrKeys{
 		Version: "version",
 	},
 	HTTP: HTTPAttrKeys{
-		Body:     "body",
-		BodySize: "body_size",
-		Header:   "header",
-		Method:   "method",
-		Path:     "path",
-		Query:    "query",
-		Status:   "status",
+		Body:          "body",
+		BodySize:      "body_size",
+		BodyTruncated: "body_truncated",
+		Header:        "header",
+		Method:        "method",
+		Path:          "path",
+		Query:         "query",
+		Status:        "status",
 	},
 	IP:           "ip",
 	PID:          "pid",
@@ -52,22 +52,25 @@ func NewDevHandler(meta app.Metadata, opts *slog.HandlerOptions) *DevHandler {
 			Level:     slog.LevelInfo,
 		}
 	}
-	if opts.ReplaceAttr == nil {
-		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
-			if a.Key == AttrKey.App.Name {
-				return slog.Attr{
-					Key:   AttrKey.App.Name,
-					Value: slog.StringValue(meta.Name),
-				}
+	// metadata attributes are always replaced, followed by the given ReplaceAttr option (e.g. Redactor.ReplaceAttr)
+	next := opts.ReplaceAttr
+	replace := func(groups []string, a slog.Attr) slog.Attr {
+		if a.Key == AttrKey.App.Name {
+			return slog.Attr{
+				Key:   AttrKey.App.Name,
+				Value: slog.StringValue(meta.Name),
 			}
-			if a.Key == AttrKey.App.Version {
-				return slog.Attr{
-					Key:   AttrKey.App.Version,
-					Value: slog.StringValue(meta.Version),
-				}
+		}
+		if a.Key == AttrKey.App.Version {
+			return slog.Attr{
+				Key:   AttrKey.App.Version,
+				Value: slog.StringValue(meta.Version),
 			}
-			return a
 		}
+		if next != nil {
+			return next(groups, a)
+		}
+		return a
 	}
 
 	b := &bytes.Buffer{}
@@ -76,11 +79,11 @@ func NewDevHandler(meta app.Metadata, opts *slog.HandlerOptions) *DevHandler {
 		handler: slog.NewJSONHandler(b, &slog.HandlerOptions{
 			AddSource:   opts.AddSource,
 			Level:       opts.Level,
-			ReplaceAttr: suppressDefaults(opts.ReplaceAttr),
+			ReplaceAttr: suppressDefaults(replace),
 		}),
 		metadata: meta,
 		mutex:    &sync.Mutex{},
-		replace:  opts.ReplaceAttr,
+		replace:  replace,
 	}
 }
 
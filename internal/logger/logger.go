@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/jasonsites/gosk/internal/http/trace"
@@ -14,6 +15,7 @@ const (
 )
 
 // CustomLogger encapsulates a logger with an associated log level and toggle
+// NOTE: Level reflects the configured level at construction; use DebugEnabled for the current level
 type CustomLogger struct {
 	Level string
 	Log   *slog.Logger
@@ -23,3 +25,8 @@ type CustomLogger struct {
 func (l *CustomLogger) CreateContextLogger(traceID string) *slog.Logger {
 	return l.Log.With(slog.String(string(trace.TraceIDContextKey), traceID))
 }
+
+// DebugEnabled reports whether the logger currently emits debug records
+func (l *CustomLogger) DebugEnabled(ctx context.Context) bool {
+	return l.Log.Enabled(ctx, slog.LevelDebug)
+}
@@ -0,0 +1,291 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces sensitive values in logs
+const RedactedValue = "[REDACTED]"
+
+var (
+	// formFieldPattern matches url-encoded form fields
+	formFieldPattern = regexp.MustCompile(`(^|&)([^=&]+)=([^&]*)`)
+	// jsonMemberPattern matches JSON object members with string or scalar values
+	jsonMemberPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"(\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,{}\[\]\s]+)`)
+	// keyNormalizer removes separators from normalized keys
+	keyNormalizer = strings.NewReplacer("_", "", "-", "")
+	// taggedFieldsCache caches the redacted field paths of struct types (by reflect.Type)
+	taggedFieldsCache sync.Map
+)
+
+// RedactionPolicy defines the sensitive values redacted from logs
+type RedactionPolicy struct {
+	// Fields defines the JSON paths of redacted body fields: dot-separated object keys (e.g. data.attributes.password),
+	// in which * matches any key, applied to each element of arrays
+	Fields []string
+	// Headers defines the (case-insensitive) names of redacted HTTP headers (e.g. Authorization)
+	Headers []string
+	// Keys defines the object keys and form fields redacted at any depth, ignoring case and separators (e.g. apikey
+	// matches api_key, apiKey and Api-Key)
+	Keys []string
+}
+
+// Redactor redacts sensitive values of log attributes by a redaction policy. Its ReplaceAttr method is set as the
+// ReplaceAttr option of slog handlers, so that sensitive values are redacted from all logs (at any log level)
+type Redactor struct {
+	mutex     sync.RWMutex
+	redaction *redaction
+}
+
+// redaction defines a compiled redaction policy
+type redaction struct {
+	fields  [][]string
+	headers []string
+	keys    []string
+}
+
+// NewRedactor returns a new Redactor instance
+func NewRedactor(p *RedactionPolicy) *Redactor {
+	r := &Redactor{}
+	r.SetPolicy(p)
+	return r
+}
+
+// SetPolicy replaces the redaction policy
+func (r *Redactor) SetPolicy(p *RedactionPolicy) {
+	rd := &redaction{}
+	for _, field := range p.Fields {
+		rd.fields = append(rd.fields, strings.Split(field, "."))
+	}
+	for _, header := range p.Headers {
+		rd.headers = append(rd.headers, http.CanonicalHeaderKey(header))
+	}
+	for _, key := range p.Keys {
+		rd.keys = append(rd.keys, normalizeKey(key))
+	}
+
+	r.mutex.Lock()
+	r.redaction = rd
+	r.mutex.Unlock()
+}
+
+// ReplaceAttr redacts sensitive values of log attributes: redacted headers of http.Header values, redacted keys of
+// decoded JSON values and text bodies, redacted fields of (http) body values, and struct fields with the `redact`
+// struct tag
+func (r *Redactor) ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	r.mutex.RLock()
+	rd := r.redaction
+	r.mutex.RUnlock()
+
+	body := a.Key == AttrKey.HTTP.Body
+	switch a.Value.Kind() {
+	case slog.KindString:
+		if body {
+			return slog.String(a.Key, rd.text(a.Value.String()))
+		}
+	case slog.KindAny:
+		switch v := a.Value.Any().(type) {
+		case http.Header:
+			return slog.Any(a.Key, rd.header(v))
+		case map[string]any, []any:
+			var fields [][]string
+			if body {
+				fields = rd.fields
+			}
+			return slog.Any(a.Key, rd.value(v, fields))
+		default:
+			if paths := taggedFields(reflect.TypeOf(v)); len(paths) > 0 {
+				return slog.Any(a.Key, rd.tagged(v, paths))
+			}
+		}
+	}
+
+	return a
+}
+
+// TaggedFields returns the JSON paths (below the optional prefix) of the struct fields of v with the `redact:"true"`
+// struct tag, e.g. the redacted fields of a request DTO below "data.attributes"
+func TaggedFields(prefix string, v any) []string {
+	var fields []string
+	for _, path := range taggedFields(reflect.TypeOf(v)) {
+		field := strings.Join(path, ".")
+		if prefix != "" {
+			field = prefix + "." + field
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// header returns a copy of h with the values of redacted headers replaced
+func (rd *redaction) header(h http.Header) http.Header {
+	redacted := make(http.Header, len(h))
+	for name, values := range h {
+		if slices.Contains(rd.headers, http.CanonicalHeaderKey(name)) {
+			values = []string{RedactedValue}
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+// value returns a copy of a decoded JSON value with the values of redacted keys (at any depth) and of the given
+// field paths replaced
+func (rd *redaction) value(v any, fields [][]string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, value := range v {
+			next, leaf := descend(fields, key)
+			if leaf || slices.Contains(rd.keys, normalizeKey(key)) {
+				redacted[key] = RedactedValue
+			} else {
+				redacted[key] = rd.value(value, next)
+			}
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, value := range v {
+			redacted[i] = rd.value(value, fields)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+// text returns text with the values of JSON object members and url-encoded form fields with redacted keys (or with
+// the last key of a redacted field path) replaced, for bodies which can not be decoded (e.g. truncated JSON)
+func (rd *redaction) text(s string) string {
+	redacted := func(key string) bool {
+		key = normalizeKey(key)
+		if slices.Contains(rd.keys, key) {
+			return true
+		}
+		for _, field := range rd.fields {
+			if last := field[len(field)-1]; last != "*" && normalizeKey(last) == key {
+				return true
+			}
+		}
+		return false
+	}
+
+	s = jsonMemberPattern.ReplaceAllStringFunc(s, func(member string) string {
+		m := jsonMemberPattern.FindStringSubmatch(member)
+		if !redacted(m[1]) {
+			return member
+		}
+		return `"` + m[1] + `"` + m[2] + `"` + RedactedValue + `"`
+	})
+
+	return formFieldPattern.ReplaceAllStringFunc(s, func(field string) string {
+		m := formFieldPattern.FindStringSubmatch(field)
+		if !redacted(m[2]) {
+			return field
+		}
+		return m[1] + m[2] + "=" + RedactedValue
+	})
+}
+
+// tagged returns a struct value as a decoded JSON value, with the values of its tagged field paths (and redacted
+// keys) replaced
+func (rd *redaction) tagged(v any, paths [][]string) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return RedactedValue
+	}
+	var decoded any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return RedactedValue
+	}
+	return rd.value(decoded, paths)
+}
+
+// descend returns the remaining field paths below key, and whether a field path ends at key
+func descend(fields [][]string, key string) ([][]string, bool) {
+	var next [][]string
+	for _, field := range fields {
+		if field[0] != "*" && field[0] != key {
+			continue
+		}
+		if len(field) == 1 {
+			return nil, true
+		}
+		next = append(next, field[1:])
+	}
+	return next, false
+}
+
+// normalizeKey returns a key in lowercase without separators
+func normalizeKey(key string) string {
+	return keyNormalizer.Replace(strings.ToLower(key))
+}
+
+// taggedFields returns the (cached) JSON paths of the struct fields of t with the `redact:"true"` struct tag
+func taggedFields(t reflect.Type) [][]string {
+	if t == nil {
+		return nil
+	}
+	if paths, ok := taggedFieldsCache.Load(t); ok {
+		return paths.([][]string)
+	}
+
+	paths := collectTaggedFields(t, nil, map[reflect.Type]bool{})
+	taggedFieldsCache.Store(t, paths)
+	return paths
+}
+
+// collectTaggedFields collects the tagged field paths of t below prefix, through nested structs, pointers, slices
+// (applied to each element) and maps (matching any key)
+func collectTaggedFields(t reflect.Type, prefix []string, seen map[reflect.Type]bool) [][]string {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return collectTaggedFields(t.Elem(), prefix, seen)
+	case reflect.Map:
+		return collectTaggedFields(t.Elem(), append(slices.Clone(prefix), "*"), seen)
+	case reflect.Struct:
+	default:
+		return nil
+	}
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	var paths [][]string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		path := prefix
+		if name != "" || !f.Anonymous {
+			if name == "" {
+				name = f.Name
+			}
+			path = append(slices.Clone(prefix), name)
+		}
+
+		if f.Tag.Get("redact") == "true" {
+			paths = append(paths, path)
+			continue
+		}
+		paths = append(paths, collectTaggedFields(f.Type, path, seen)...)
+	}
+
+	return paths
+}
@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// testPolicy defines the redaction policy used by redactor tests
+var testPolicy = &RedactionPolicy{
+	Fields:  []string{"data.attributes.note", "data.*.pin"},
+	Headers: []string{"authorization"},
+	Keys:    []string{"password", "apiKey"},
+}
+
+type testCredentials struct {
+	Name   string            `json:"name"`
+	Secret string            `json:"secret" redact:"true"`
+	Tokens map[string]string `json:"tokens" redact:"true"`
+}
+
+type testAccount struct {
+	testEmbedded
+	Credentials []testCredentials `json:"credentials"`
+	Ignored     string            `json:"-" redact:"true"`
+	Labels      map[string]testCredentials
+	Password    string `json:"password"`
+	User        string `json:"user"`
+}
+
+type testEmbedded struct {
+	Token string `json:"token" redact:"true"`
+}
+
+type ReplaceAttrSetup struct {
+	Name        string
+	Description string
+	Attr        slog.Attr
+	Expected    any
+}
+
+// Test_Redactor_ReplaceAttr verifies the redaction of headers, decoded and text bodies, redacted keys and tagged
+// struct fields
+func Test_Redactor_ReplaceAttr(t *testing.T) {
+	body := AttrKey.HTTP.Body
+
+	tests := []ReplaceAttrSetup{
+		{
+			Name:        "header",
+			Description: "redacts denylisted headers (case-insensitive)",
+			Attr:        slog.Any("headers", http.Header{"Authorization": {"Bearer t0ken"}, "Accept": {"*/*"}}),
+			Expected:    http.Header{"Authorization": {RedactedValue}, "Accept": {"*/*"}},
+		},
+		{
+			Name:        "body_fields",
+			Description: "redacts body field paths (with wildcards, through arrays) and keys at any depth",
+			Attr: slog.Any(body, map[string]any{"data": []any{map[string]any{
+				"attributes": map[string]any{"note": "s3cr3t", "pin": "1234", "title": "visible"},
+				"meta":       map[string]any{"api_key": "k3y"},
+			}}}),
+			Expected: map[string]any{"data": []any{map[string]any{
+				"attributes": map[string]any{"note": RedactedValue, "pin": RedactedValue, "title": "visible"},
+				"meta":       map[string]any{"api_key": RedactedValue},
+			}}},
+		},
+		{
+			Name:        "value_keys",
+			Description: "redacts keys but not body field paths of non-body values",
+			Attr:        slog.Any("value", map[string]any{"data": map[string]any{"attributes": map[string]any{"note": "visible", "Password": "s3cr3t"}}}),
+			Expected:    map[string]any{"data": map[string]any{"attributes": map[string]any{"note": "visible", "Password": RedactedValue}}},
+		},
+		{
+			Name:        "body_text_json",
+			Description: "redacts keys and field path leaves of undecodable JSON bodies",
+			Attr:        slog.String(body, `{"data":{"attributes":{"note":"s3cr3t","password":"s3cr3t","title":"vis`),
+			Expected:    `{"data":{"attributes":{"note":"[REDACTED]","password":"[REDACTED]","title":"vis`,
+		},
+		{
+			Name:        "body_text_form",
+			Description: "redacts keys of url-encoded form bodies",
+			Attr:        slog.String(body, "user=jane&api-key=k3y&pin=1234"),
+			Expected:    "user=jane&api-key=[REDACTED]&pin=[REDACTED]",
+		},
+		{
+			Name:        "text",
+			Description: "does not redact non-body strings",
+			Attr:        slog.String("message", "password=s3cr3t"),
+			Expected:    "password=s3cr3t",
+		},
+		{
+			Name:        "tagged",
+			Description: "redacts tagged struct fields (nested, embedded, in slices and maps) and keys",
+			Attr: slog.Any("account", testAccount{
+				testEmbedded: testEmbedded{Token: "t0ken"},
+				Credentials:  []testCredentials{{Name: "primary", Secret: "s3cr3t", Tokens: map[string]string{"a": "b"}}},
+				Labels:       map[string]testCredentials{"x": {Name: "label", Secret: "s3cr3t"}},
+				Password:     "s3cr3t",
+				User:         "jane",
+			}),
+			Expected: map[string]any{
+				"token":       RedactedValue,
+				"credentials": []any{map[string]any{"name": "primary", "secret": RedactedValue, "tokens": RedactedValue}},
+				"Labels":      map[string]any{"x": map[string]any{"name": "label", "secret": RedactedValue, "tokens": RedactedValue}},
+				"password":    RedactedValue,
+				"user":        "jane",
+			},
+		},
+		{
+			Name:        "untagged",
+			Description: "does not modify untagged struct values",
+			Attr:        slog.Any("value", struct{ Name string }{Name: "jane"}),
+			Expected:    struct{ Name string }{Name: "jane"},
+		},
+	}
+
+	r := NewRedactor(testPolicy)
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			a := r.ReplaceAttr(nil, tc.Attr)
+
+			if a.Key != tc.Attr.Key {
+				t.Errorf("expected key '%s', actual '%s'", tc.Attr.Key, a.Key)
+			}
+			if actual := a.Value.Any(); !reflect.DeepEqual(actual, tc.Expected) {
+				expected, _ := json.Marshal(tc.Expected)
+				b, _ := json.Marshal(actual)
+				t.Errorf("expected '%s', actual '%s'", expected, b)
+			}
+		})
+	}
+}
+
+// Test_Redactor_SetPolicy verifies that replacing the policy applies to subsequent attributes
+func Test_Redactor_SetPolicy(t *testing.T) {
+	r := NewRedactor(testPolicy)
+	attr := slog.Any("value", map[string]any{"password": "s3cr3t", "email": "jane@example.com"})
+
+	r.SetPolicy(&RedactionPolicy{Keys: []string{"email"}})
+
+	expected := map[string]any{"password": "s3cr3t", "email": RedactedValue}
+	if actual := r.ReplaceAttr(nil, attr).Value.Any(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected '%v', actual '%v'", expected, actual)
+	}
+}
+
+// Test_TaggedFields verifies the JSON paths of tagged struct fields
+func Test_TaggedFields(t *testing.T) {
+	expected := []string{
+		"data.attributes.token",
+		"data.attributes.credentials.secret",
+		"data.attributes.credentials.tokens",
+		"data.attributes.Labels.*.secret",
+		"data.attributes.Labels.*.tokens",
+	}
+	if actual := TaggedFields("data.attributes", &testAccount{}); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected '%v', actual '%v'", expected, actual)
+	}
+
+	if actual := TaggedFields("", testCredentials{}); !reflect.DeepEqual(actual, []string{"secret", "tokens"}) {
+		t.Errorf("expected unprefixed fields, actual '%v'", actual)
+	}
+	if actual := TaggedFields("data", struct{ Name string }{}); actual != nil {
+		t.Errorf("expected no fields, actual '%v'", actual)
+	}
+}
@@ -0,0 +1,306 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/app"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/export"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/openapi"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/logger"
+	query "github.com/jasonsites/gosk/internal/modules/common/models/query"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+)
+
+// CRUDControllerConfig defines the input to NewCRUDController
+type CRUDControllerConfig[T, D any] struct {
+	// CSV enables the text/csv list representation (negotiated by the request Accept header), with one column per
+	// model column
+	CSV    bool
+	Entity EntityDefinition     `validate:"required"`
+	Logger *logger.CustomLogger `validate:"required"`
+	// Namespace defines the router namespace, used for resource links
+	Namespace string `validate:"required"`
+	// Path defines the resource collection path within the router namespace (e.g. "examples")
+	Path    string        `validate:"required"`
+	Query   *QueryHandler `validate:"required"`
+	Service Service[T, D] `validate:"required"`
+}
+
+// CRUDController implements JSON:API create, delete, detail, list and update handlers for a CRUD resource, with
+// model (T) attributes serialized using their `json` struct tags
+type CRUDController[T, D any] struct {
+	basePath string
+	csv      bool
+	entity   EntityDefinition
+	fields   entityFields
+	key      entityField
+	logger   *logger.CustomLogger
+	path     string
+	query    *QueryHandler
+	service  Service[T, D]
+}
+
+// NewCRUDController returns a new CRUDController instance
+func NewCRUDController[T, D any](c *CRUDControllerConfig[T, D]) (*CRUDController[T, D], error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	entity := c.Entity
+	if entity.Key == "" {
+		entity.Key = "id"
+	}
+
+	fields, err := parseFields(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	key, ok := fields.Field(entity.Key)
+	if !ok {
+		return nil, fmt.Errorf("%s key column '%s' not found on %s", entity.Name, entity.Key, reflect.TypeFor[T]())
+	}
+
+	ctrl := &CRUDController[T, D]{
+		basePath: fmt.Sprintf("/%s/%s", c.Namespace, c.Path),
+		csv:      c.CSV,
+		entity:   entity,
+		fields:   fields,
+		key:      key,
+		logger:   c.Logger,
+		path:     c.Path,
+		query:    c.Query,
+		service:  c.Service,
+	}
+
+	return ctrl, nil
+}
+
+// Create
+func (c *CRUDController[T, D]) Create() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		data, err := c.decode(w, r)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		model, err := c.service.Create(ctx, data)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		response := &jsonapi.Response{Data: c.formatResource(model)}
+		jsonio.EncodeResponse(w, r, http.StatusCreated, response)
+	}
+}
+
+// Delete
+func (c *CRUDController[T, D]) Delete() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			err = cerror.NewValidationError(err, "resource id parse error")
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		if err := c.service.Delete(ctx, id); err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Detail
+func (c *CRUDController[T, D]) Detail() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			err = cerror.NewValidationError(err, "resource id parse error")
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		model, err := c.service.Detail(ctx, id)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		response := &jsonapi.Response{Data: c.formatResource(model)}
+		jsonio.EncodeResponse(w, r, http.StatusOK, response)
+	}
+}
+
+// List
+func (c *CRUDController[T, D]) List() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		q, err := c.query.ParseQuery([]byte(r.URL.RawQuery))
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		models, page, err := c.service.List(ctx, q)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		if c.csv && jsonio.RequestRepresentation(r).MediaType == export.MediaTypeCSV {
+			if err := c.encodeCSV(w, models); err != nil {
+				log.Error(fmt.Sprintf("csv encode error: %s", err.Error()))
+			}
+			return
+		}
+
+		data := make([]jsonapi.ResponseResource, 0, len(models))
+		for _, model := range models {
+			data = append(data, c.formatResource(model))
+		}
+
+		response := &jsonapi.Response{
+			Meta: &jsonapi.ResponseMetadata{
+				Page: formatPage(page),
+				Sort: q.Sort,
+			},
+			Data: data,
+		}
+		jsonio.EncodeResponse(w, r, http.StatusOK, response)
+	}
+}
+
+// Update
+func (c *CRUDController[T, D]) Update() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			err = cerror.NewValidationError(err, "resource id parse error")
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		data, err := c.decode(w, r)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		model, err := c.service.Update(ctx, data, id)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		response := &jsonapi.Response{Data: c.formatResource(model)}
+		jsonio.EncodeResponse(w, r, http.StatusOK, response)
+	}
+}
+
+// Operations describes the routes registered by Router for the resource
+func (c *CRUDController[T, D]) Operations() []openapi.Operation {
+	var filters, sort []string
+	for _, field := range c.query.fields {
+		if field.Filter != "" {
+			filters = append(filters, field.Column)
+		}
+		if field.Sort {
+			sort = append(sort, field.Column)
+		}
+	}
+
+	resource := openapi.Resource{
+		Name:       c.path,
+		Type:       c.entity.Type,
+		Attributes: openapi.Type[T](),
+		Input:      openapi.Type[D](),
+		Filters:    filters,
+		Sort:       sort,
+	}
+	if c.csv {
+		resource.ListAlternates = map[string]*openapi.Schema{export.MediaTypeCSV: {Type: "string"}}
+	}
+
+	return openapi.CRUDOperations(c.basePath, resource)
+}
+
+// decode decodes and validates a request body with DTO attributes (see jsonio.DecodeValidRequest)
+func (c *CRUDController[T, D]) decode(w http.ResponseWriter, r *http.Request) (*D, error) {
+	data := new(D)
+	resource := &jsonapi.RequestBody{
+		Data: &jsonapi.RequestResource{Attributes: data},
+	}
+
+	if err := jsonio.DecodeValidRequest(w, r, resource); err != nil {
+		return nil, err
+	}
+	if resource.Data.Type != c.entity.Type {
+		err := cerror.NewConflictError(nil, "invalid resource type '%s' (expected '%s')", resource.Data.Type, c.entity.Type)
+		return nil, cerror.WithCode(err, "resource_type_conflict")
+	}
+
+	return data, nil
+}
+
+// formatResource formats a single model as a JSON:API resource
+func (c *CRUDController[T, D]) formatResource(model *T) jsonapi.ResponseResource {
+	id := keyOf(reflect.ValueOf(model).Elem(), c.key)
+
+	return jsonapi.ResponseResource{
+		Type:       c.entity.Type,
+		ID:         id,
+		Attributes: model,
+		Links:      &jsonapi.Links{Self: fmt.Sprintf("%s/%s", c.basePath, id)},
+	}
+}
+
+// formatPage formats repository page data as response page metadata
+func formatPage(page repo.PageData) query.PageMetadata {
+	return query.PageMetadata{
+		Limit:  uint32(page.Limit),
+		Offset: uint32(page.Offset),
+		Total:  uint32(page.Total),
+	}
+}
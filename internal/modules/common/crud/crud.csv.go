@@ -0,0 +1,24 @@
+package common
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/jasonsites/gosk/internal/http/export"
+)
+
+// encodeCSV writes models as a CSV response body, with a header row of column names
+func (c *CRUDController[T, D]) encodeCSV(w http.ResponseWriter, models []*T) error {
+	ew, err := export.NewWriter(w, export.MediaTypeCSV, c.fields.Columns())
+	if err != nil {
+		return err
+	}
+
+	for _, model := range models {
+		if err := ew.Write(valuesOf(reflect.ValueOf(model).Elem(), c.fields)); err != nil {
+			return err
+		}
+	}
+
+	return ew.Close()
+}
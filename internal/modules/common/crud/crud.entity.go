@@ -0,0 +1,188 @@
+package common
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// FilterOp defines the comparison applied by a filter query parameter
+type FilterOp string
+
+const (
+	// FilterEq matches column values equal to the filter value (filter[status]=active)
+	FilterEq FilterOp = "eq"
+	// FilterILike matches column values containing the filter value, case-insensitively (string columns only)
+	FilterILike FilterOp = "ilike"
+)
+
+// EntityDefinition defines the database table and JSON:API resource backing a CRUD resource. Columns are derived
+// from the `db` struct tags of the model (T) and DTO (D) types, with sortable and filterable columns derived from
+// `query` struct tags on the model, e.g.
+//
+//	Title string `db:"title" json:"title" query:"sort,filter=ilike"`
+type EntityDefinition struct {
+	// Key defines the uuid primary key column (default "id")
+	Key string
+	// ModifiedOn defines a timestamp column set to now() on update (optional)
+	ModifiedOn string
+	// Name defines the table name
+	Name string `validate:"required"`
+	// Type defines the JSON:API resource type
+	Type string `validate:"required"`
+}
+
+// entityField defines a single model or DTO column
+type entityField struct {
+	Column string
+	Filter FilterOp
+	Index  []int
+	Sort   bool
+	Type   reflect.Type
+}
+
+// entityFields defines the columns of a model or DTO type, in struct field order
+type entityFields []entityField
+
+// Columns returns all column names
+func (f entityFields) Columns() []string {
+	columns := make([]string, 0, len(f))
+	for _, field := range f {
+		columns = append(columns, field.Column)
+	}
+	return columns
+}
+
+// Field returns the field for the given column name
+func (f entityFields) Field(column string) (entityField, bool) {
+	for _, field := range f {
+		if field.Column == column {
+			return field, true
+		}
+	}
+	return entityField{}, false
+}
+
+// parseFields derives the columns of the given struct type from `db` and `query` struct tags
+func parseFields(t reflect.Type) (entityFields, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s must be a struct type", t)
+	}
+
+	var fields entityFields
+	for _, sf := range reflect.VisibleFields(t) {
+		column, ok := sf.Tag.Lookup("db")
+		if !ok || column == "-" || !sf.IsExported() {
+			continue
+		}
+
+		field := entityField{
+			Column: column,
+			Index:  sf.Index,
+			Type:   sf.Type,
+		}
+
+		for _, opt := range strings.Split(sf.Tag.Get("query"), ",") {
+			switch name, value, _ := strings.Cut(strings.TrimSpace(opt), "="); name {
+			case "":
+			case "sort":
+				field.Sort = true
+			case "filter":
+				field.Filter = FilterEq
+				if value != "" {
+					field.Filter = FilterOp(value)
+				}
+				if field.Filter != FilterEq && field.Filter != FilterILike {
+					return nil, fmt.Errorf("%s.%s: invalid filter operator '%s'", t, sf.Name, value)
+				}
+				if field.Filter == FilterILike && indirect(sf.Type).Kind() != reflect.String {
+					return nil, fmt.Errorf("%s.%s: ilike filters require a string field", t, sf.Name)
+				}
+			default:
+				return nil, fmt.Errorf("%s.%s: invalid query tag option '%s'", t, sf.Name, name)
+			}
+		}
+
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%s has no `db` tagged fields", t)
+	}
+
+	return fields, nil
+}
+
+// keyOf returns the uuid key field value of the given model
+func keyOf(v reflect.Value, key entityField) uuid.UUID {
+	id, _ := v.FieldByIndex(key.Index).Interface().(uuid.UUID)
+	return id
+}
+
+// valuesOf returns the column values of the given struct value, in field order
+func valuesOf(v reflect.Value, fields entityFields) []any {
+	values := make([]any, 0, len(fields))
+	for _, field := range fields {
+		values = append(values, v.FieldByIndex(field.Index).Interface())
+	}
+	return values
+}
+
+// indirect returns the element type of pointer types
+func indirect(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// convertFilterValue converts a raw filter query parameter value to the (non-pointer) type of the given field
+func convertFilterValue(raw string, field entityField) (any, error) {
+	t := indirect(field.Type)
+	ptr := reflect.New(t)
+
+	if u, ok := ptr.Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(raw)); err != nil {
+			return nil, err
+		}
+		return ptr.Elem().Interface(), nil
+	}
+
+	v := ptr.Elem()
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(raw, 10, t.Bits())
+		if err != nil {
+			return nil, err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(raw, 10, t.Bits())
+		if err != nil {
+			return nil, err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, t.Bits())
+		if err != nil {
+			return nil, err
+		}
+		v.SetFloat(f)
+	default:
+		return nil, fmt.Errorf("unsupported filter type %s", t)
+	}
+
+	return v.Interface(), nil
+}
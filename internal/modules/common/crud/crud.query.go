@@ -0,0 +1,235 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/jasonsites/gosk/internal/app"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	query "github.com/jasonsites/gosk/internal/modules/common/models/query"
+)
+
+// SortPair defines a single sort column and order
+type SortPair struct {
+	Field string
+	Order query.SortOrder
+}
+
+// SortEntry implements query.SortableEntry for the sortable columns of a model, as derived from `query:"sort"`
+// struct tags (replacing hand-written per-module sort entries)
+type SortEntry struct {
+	pairs []SortPair
+	valid []string
+}
+
+// GetFieldCount returns the number of fields set in the entry
+func (se SortEntry) GetFieldCount() int {
+	return len(se.pairs)
+}
+
+// HasAnyField returns true if at least one field is set
+func (se SortEntry) HasAnyField() bool {
+	return len(se.pairs) > 0
+}
+
+// GetActiveFields returns a map of active field names and their orders
+func (se SortEntry) GetActiveFields() map[string]query.SortOrder {
+	fields := make(map[string]query.SortOrder, len(se.pairs))
+	for _, p := range se.pairs {
+		fields[p.Field] = p.Order
+	}
+	return fields
+}
+
+// GetSortPairs returns field-order pairs for database queries
+func (se SortEntry) GetSortPairs() []struct {
+	Field string
+	Order query.SortOrder
+} {
+	pairs := make([]struct {
+		Field string
+		Order query.SortOrder
+	}, 0, len(se.pairs))
+	for _, p := range se.pairs {
+		pairs = append(pairs, struct {
+			Field string
+			Order query.SortOrder
+		}(p))
+	}
+	return pairs
+}
+
+// SetFieldFromString sets a field by name from a string value
+func (se SortEntry) SetFieldFromString(fieldName string, order query.SortOrder) (query.SortableEntry, error) {
+	if !slices.Contains(se.valid, fieldName) {
+		return se, fmt.Errorf("invalid field name: %s", fieldName)
+	}
+
+	pairs := slices.DeleteFunc(slices.Clone(se.pairs), func(p SortPair) bool { return p.Field == fieldName })
+	se.pairs = append(pairs, SortPair{Field: fieldName, Order: order})
+
+	return se, nil
+}
+
+// GetValidFieldNames returns a list of valid field names for this entry type
+func (se SortEntry) GetValidFieldNames() []string {
+	return se.valid
+}
+
+// MarshalJSON implements the json.Marshaler interface, matching the sort metadata of hand-written entries
+// (e.g. {"title":"asc"})
+func (se SortEntry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(se.GetActiveFields())
+}
+
+// Filter defines a single parsed filter query parameter
+type Filter struct {
+	Column string
+	Op     FilterOp
+	Value  any
+}
+
+// ListQuery defines the parsed list query parameters of a CRUD resource
+type ListQuery struct {
+	Filters []Filter
+	Page    query.PageQuery
+	Sort    query.SortQuery[SortEntry]
+}
+
+// QueryConfig defines the input to NewQueryHandler
+type QueryConfig struct {
+	// DefaultSort defines the sort applied to queries that do not specify one (e.g. "-created_on")
+	DefaultSort string
+	// PageLimit defines the page limit applied to queries that do not specify one
+	PageLimit int `validate:"required,min=1"`
+}
+
+// QueryHandler parses list query parameters for a model, with sort and filter fields derived from struct tags
+type QueryHandler struct {
+	fields  entityFields
+	handler *query.QueryHandler[SortEntry]
+}
+
+// NewQueryHandler returns a new QueryHandler instance for the model type T
+func NewQueryHandler[T any](c *QueryConfig) (*QueryHandler, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	fields, err := parseFields(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	var valid []string
+	for _, field := range fields {
+		if field.Sort {
+			valid = append(valid, field.Column)
+		}
+	}
+	factory := func() SortEntry {
+		return SortEntry{valid: valid}
+	}
+
+	sort, err := query.ParseSortList(c.DefaultSort, factory)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default sort '%s': %w", c.DefaultSort, err)
+	}
+
+	limit := c.PageLimit
+	handler, err := query.NewQueryHandler(&query.QueryConfig[SortEntry]{
+		Defaults: &query.QueryDefaults[SortEntry]{
+			Page: query.PageQuery{Limit: &limit},
+			Sort: sort,
+		},
+		EntryFactory: factory,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryHandler{fields: fields, handler: handler}, nil
+}
+
+// ParseQuery parses list query parameters, returning a validation error (with field errors located by query
+// parameter name) for unsupported, repeated or invalid filters and invalid page or sort parameters
+func (h *QueryHandler) ParseQuery(qs []byte) (*ListQuery, error) {
+	values, err := url.ParseQuery(string(qs))
+	if err != nil {
+		return nil, cerror.NewValidationError(err, "query string parse error")
+	}
+
+	// filters are parsed here (rather than by the common query handler), as filterable fields vary by model
+	var (
+		errs    cerror.FieldErrors
+		filters []Filter
+	)
+	for key, v := range values {
+		column, ok := strings.CutPrefix(key, "filter[")
+		if !ok {
+			continue
+		}
+		column = strings.TrimSuffix(column, "]")
+
+		field, ok := h.fields.Field(column)
+		if !ok || field.Filter == "" {
+			errs = append(errs, cerror.FieldError{Code: "validation_unsupported_filter", Parameter: key, Detail: "unsupported filter"})
+			continue
+		}
+		if len(v) > 1 {
+			detail := "repeated filter (filters accept a single value)"
+			errs = append(errs, cerror.FieldError{Code: "validation_repeated_filter", Parameter: key, Detail: detail})
+			continue
+		}
+		value, err := convertFilterValue(v[0], field)
+		if err != nil {
+			errs = append(errs, cerror.FieldError{Code: "validation_invalid_type", Parameter: key, Detail: err.Error()})
+			continue
+		}
+		filters = append(filters, Filter{Column: column, Op: field.Filter, Value: value})
+	}
+	slices.SortFunc(filters, func(a, b Filter) int { return strings.Compare(a.Column, b.Column) })
+	slices.SortFunc(errs, func(a, b cerror.FieldError) int { return strings.Compare(a.Parameter, b.Parameter) })
+
+	data, err := h.handler.ParseQuery([]byte(withoutFilters(string(qs))))
+	if err != nil {
+		var qerrs cerror.FieldErrors
+		if !errors.As(err, &qerrs) {
+			return nil, err
+		}
+		errs = append(errs, qerrs...)
+	}
+	if len(errs) > 0 {
+		return nil, cerror.NewValidationError(errs, "query parameter validation error")
+	}
+
+	result := &ListQuery{
+		Filters: filters,
+		Page:    data.Page,
+		Sort:    data.Sort,
+	}
+
+	return result, nil
+}
+
+// withoutFilters removes filter parameters from the given query string, leaving other parameters as-is
+// (re-encoding url.Values would escape the bracket notation expected by the common query handler)
+func withoutFilters(qs string) string {
+	params := strings.Split(qs, "&")
+	params = slices.DeleteFunc(params, func(param string) bool {
+		key, _, _ := strings.Cut(param, "=")
+		key, err := url.QueryUnescape(key)
+		return err == nil && strings.HasPrefix(key, "filter[")
+	})
+	return strings.Join(params, "&")
+}
+
+// SetDefaultPageLimit replaces the page limit applied to queries that do not specify one
+func (h *QueryHandler) SetDefaultPageLimit(limit int) {
+	h.handler.SetDefaultPageLimit(limit)
+}
@@ -0,0 +1,255 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jasonsites/gosk/internal/app"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/database"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/logger"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+)
+
+// likeEscaper escapes LIKE pattern characters in user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Repository defines the interface for a repository managing a CRUD resource, with model type T and DTO type D
+type Repository[T, D any] interface {
+	Create(context.Context, *D) (*T, error)
+	Delete(context.Context, uuid.UUID) error
+	Detail(context.Context, uuid.UUID) (*T, error)
+	List(context.Context, *ListQuery) ([]*T, repo.PageData, error)
+	Update(context.Context, *D, uuid.UUID) (*T, error)
+}
+
+// PGRepositoryConfig defines the input to NewPGRepository
+type PGRepositoryConfig struct {
+	DBRouter *database.Router     `validate:"required"`
+	Entity   EntityDefinition     `validate:"required"`
+	Logger   *logger.CustomLogger `validate:"required"`
+}
+
+// PGRepository implements Repository for a single postgres table, with the selected columns derived from the
+// `db` struct tags of T, and the inserted/updated columns derived from the `db` struct tags of D
+type PGRepository[T, D any] struct {
+	db        *database.Router
+	dtoFields entityFields
+	entity    EntityDefinition
+	fields    entityFields
+	key       entityField
+	logger    *logger.CustomLogger
+}
+
+// NewPGRepository returns a new PGRepository instance
+func NewPGRepository[T, D any](c *PGRepositoryConfig) (*PGRepository[T, D], error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	entity := c.Entity
+	if entity.Key == "" {
+		entity.Key = "id"
+	}
+
+	fields, err := parseFields(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	dtoFields, err := parseFields(reflect.TypeFor[D]())
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := fields.Field(entity.Key)
+	if !ok || key.Type != reflect.TypeFor[uuid.UUID]() {
+		return nil, fmt.Errorf("%s key column '%s' must be a uuid.UUID field of %s", entity.Name, entity.Key, reflect.TypeFor[T]())
+	}
+
+	repository := &PGRepository[T, D]{
+		db:        c.DBRouter,
+		dtoFields: dtoFields,
+		entity:    entity,
+		fields:    fields,
+		key:       key,
+		logger:    c.Logger,
+	}
+
+	return repository, nil
+}
+
+// Create
+func (r *PGRepository[T, D]) Create(ctx context.Context, data *D) (*T, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	// build sql query
+	query := func() string {
+		statement := "INSERT INTO %s %s VALUES %s RETURNING %s"
+
+		insertFields, values := repo.BuildInsertFieldsAndValues(r.dtoFields.Columns()...)
+		returnFields := repo.BuildReturnFields(r.fields.Columns()...)
+
+		return fmt.Sprintf(statement, r.entity.Name, insertFields, values, returnFields)
+	}()
+
+	args := valuesOf(reflect.ValueOf(data).Elem(), r.dtoFields)
+	rows, _ := r.db.Write(ctx).Query(ctx, query, args...)
+	model, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[T])
+	if err != nil {
+		log.Error(err.Error())
+		return nil, repo.TranslateError(err, r.entity.Name, uuid.Nil)
+	}
+
+	return model, nil
+}
+
+// Delete
+func (r *PGRepository[T, D]) Delete(ctx context.Context, id uuid.UUID) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1", r.entity.Name, r.entity.Key)
+
+	tag, err := r.db.Write(ctx).Exec(ctx, query, id)
+	if err != nil {
+		log.Error(err.Error())
+		return repo.TranslateError(err, r.entity.Name, id)
+	}
+	if tag.RowsAffected() == 0 {
+		return r.notFound(id)
+	}
+
+	return nil
+}
+
+// Detail
+func (r *PGRepository[T, D]) Detail(ctx context.Context, id uuid.UUID) (*T, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	returnFields := repo.BuildReturnFields(r.fields.Columns()...)
+	query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1", returnFields, r.entity.Name, r.entity.Key)
+
+	rows, _ := r.db.Read(ctx).Query(ctx, query, id)
+	model, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[T])
+	if err != nil {
+		log.Error(err.Error())
+		return nil, repo.TranslateError(err, r.entity.Name, id)
+	}
+
+	return model, nil
+}
+
+// List
+func (r *PGRepository[T, D]) List(ctx context.Context, q *ListQuery) ([]*T, repo.PageData, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	var (
+		limit  = *q.Page.Limit
+		offset = *q.Page.Offset
+	)
+
+	// build sql where clause (shared by list and total count queries) and query args
+	where, args := func() (string, []any) {
+		var (
+			args       []any
+			conditions []string
+		)
+
+		for _, f := range q.Filters {
+			switch f.Op {
+			case FilterILike:
+				args = append(args, "%"+likeEscaper.Replace(fmt.Sprint(f.Value))+"%")
+				conditions = append(conditions, fmt.Sprintf("%s ILIKE $%d", pgx.Identifier{f.Column}.Sanitize(), len(args)))
+			default:
+				args = append(args, f.Value)
+				conditions = append(conditions, fmt.Sprintf("%s = $%d", pgx.Identifier{f.Column}.Sanitize(), len(args)))
+			}
+		}
+
+		if len(conditions) == 0 {
+			return "", args
+		}
+		return " WHERE " + strings.Join(conditions, " AND "), args
+	}()
+
+	// build sql query (sort fields are restricted to tagged columns by the query handler)
+	query := func() string {
+		statement := "SELECT %s FROM %s%s ORDER BY %s LIMIT $%d OFFSET $%d"
+
+		var order []string
+		for _, pair := range q.Sort.GetSortPairs() {
+			order = append(order, fmt.Sprintf("%s %s", pgx.Identifier{pair.Field}.Sanitize(), pair.Order))
+		}
+		// order by key last, so that paging is stable
+		order = append(order, r.entity.Key)
+
+		returnFields := repo.BuildReturnFields(r.fields.Columns()...)
+
+		return fmt.Sprintf(statement, returnFields, r.entity.Name, where, strings.Join(order, ", "), len(args)+1, len(args)+2)
+	}()
+
+	db := r.db.Read(ctx)
+	rows, _ := db.Query(ctx, query, append(args, limit, offset)...)
+	models, err := pgx.CollectRows(rows, pgx.RowToAddrOfStructByName[T])
+	if err != nil {
+		log.Error(err.Error())
+		return nil, repo.PageData{}, repo.TranslateError(err, r.entity.Name, uuid.Nil)
+	}
+
+	var total int
+	totalQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", r.entity.Name, where)
+	if err := db.QueryRow(ctx, totalQuery, args...).Scan(&total); err != nil {
+		log.Error(err.Error())
+		return nil, repo.PageData{}, repo.TranslateError(err, r.entity.Name, uuid.Nil)
+	}
+
+	page := repo.PageData{
+		Limit:  limit,
+		Offset: offset,
+		Total:  total,
+	}
+
+	return models, page, nil
+}
+
+// Update
+func (r *PGRepository[T, D]) Update(ctx context.Context, data *D, id uuid.UUID) (*T, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	// build sql query
+	query := func() string {
+		statement := "UPDATE %s SET %s WHERE %s = $%d RETURNING %s"
+
+		values := repo.BuildUpdateValues(r.dtoFields.Columns()...)
+		if r.entity.ModifiedOn != "" {
+			values = fmt.Sprintf("%s,%s=now()", values, r.entity.ModifiedOn)
+		}
+		returnFields := repo.BuildReturnFields(r.fields.Columns()...)
+
+		return fmt.Sprintf(statement, r.entity.Name, values, r.entity.Key, len(r.dtoFields)+1, returnFields)
+	}()
+
+	args := append(valuesOf(reflect.ValueOf(data).Elem(), r.dtoFields), id)
+	rows, _ := r.db.Write(ctx).Query(ctx, query, args...)
+	model, err := pgx.CollectExactlyOneRow(rows, pgx.RowToAddrOfStructByName[T])
+	if err != nil {
+		log.Error(err.Error())
+		return nil, repo.TranslateError(err, r.entity.Name, id)
+	}
+
+	return model, nil
+}
+
+// notFound returns a not found error for the given id
+func (r *PGRepository[T, D]) notFound(id uuid.UUID) error {
+	return cerror.NewNotFoundError(nil, fmt.Sprintf("unable to find %s with id '%s'", r.entity.Name, id))
+}
@@ -0,0 +1,34 @@
+package common
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jasonsites/gosk/internal/http/openapi"
+)
+
+// Controller defines the handlers registered by Router
+type Controller interface {
+	Create() http.HandlerFunc
+	Delete() http.HandlerFunc
+	Detail() http.HandlerFunc
+	List() http.HandlerFunc
+	Update() http.HandlerFunc
+
+	// Operations describes the registered routes for the OpenAPI document
+	Operations() []openapi.Operation
+}
+
+// Router implements a router group for a CRUD resource at /{ns}/{path}
+func Router(r *chi.Mux, ns, path string, c Controller) {
+	prefix := fmt.Sprintf("/%s/%s", ns, path)
+
+	r.Route(prefix, func(r chi.Router) {
+		r.Get("/", c.List())
+		r.Get("/{id}", c.Detail())
+		r.Post("/", c.Create())
+		r.Put("/{id}", c.Update())
+		r.Delete("/{id}", c.Delete())
+	})
+}
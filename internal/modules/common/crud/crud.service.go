@@ -0,0 +1,171 @@
+package common
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/logger"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+)
+
+// Hooks defines optional custom logic run by CRUDService around repository operations. Before hooks may modify
+// their input, and any hook error aborts the operation (returned as-is, so hooks should return cerror errors)
+type Hooks[T, D any] struct {
+	AfterCreate  func(ctx context.Context, model *T) error
+	AfterUpdate  func(ctx context.Context, model *T) error
+	BeforeCreate func(ctx context.Context, data *D) error
+	BeforeDelete func(ctx context.Context, id uuid.UUID) error
+	BeforeList   func(ctx context.Context, q *ListQuery) error
+	BeforeUpdate func(ctx context.Context, data *D, id uuid.UUID) error
+}
+
+// Service defines the interface for a service managing a CRUD resource
+type Service[T, D any] interface {
+	Create(context.Context, *D) (*T, error)
+	Delete(context.Context, uuid.UUID) error
+	Detail(context.Context, uuid.UUID) (*T, error)
+	List(context.Context, *ListQuery) ([]*T, repo.PageData, error)
+	Update(context.Context, *D, uuid.UUID) (*T, error)
+}
+
+// CRUDServiceConfig defines the input to NewCRUDService
+type CRUDServiceConfig[T, D any] struct {
+	Hooks  Hooks[T, D]
+	Logger *logger.CustomLogger `validate:"required"`
+	Repo   Repository[T, D]     `validate:"required"`
+}
+
+// CRUDService implements Service over a Repository, running the configured hooks
+type CRUDService[T, D any] struct {
+	hooks  Hooks[T, D]
+	logger *logger.CustomLogger
+	repo   Repository[T, D]
+}
+
+// NewCRUDService returns a new CRUDService instance
+func NewCRUDService[T, D any](c *CRUDServiceConfig[T, D]) (*CRUDService[T, D], error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	service := &CRUDService[T, D]{
+		hooks:  c.Hooks,
+		logger: c.Logger,
+		repo:   c.Repo,
+	}
+
+	return service, nil
+}
+
+// Create
+func (s *CRUDService[T, D]) Create(ctx context.Context, data *D) (*T, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	if hook := s.hooks.BeforeCreate; hook != nil {
+		if err := hook(ctx, data); err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	model, err := s.repo.Create(ctx, data)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	if hook := s.hooks.AfterCreate; hook != nil {
+		if err := hook(ctx, model); err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
+
+// Delete
+func (s *CRUDService[T, D]) Delete(ctx context.Context, id uuid.UUID) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	if hook := s.hooks.BeforeDelete; hook != nil {
+		if err := hook(ctx, id); err != nil {
+			log.Error(err.Error())
+			return err
+		}
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Detail
+func (s *CRUDService[T, D]) Detail(ctx context.Context, id uuid.UUID) (*T, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	model, err := s.repo.Detail(ctx, id)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// List
+func (s *CRUDService[T, D]) List(ctx context.Context, q *ListQuery) ([]*T, repo.PageData, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	if hook := s.hooks.BeforeList; hook != nil {
+		if err := hook(ctx, q); err != nil {
+			log.Error(err.Error())
+			return nil, repo.PageData{}, err
+		}
+	}
+
+	models, page, err := s.repo.List(ctx, q)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, repo.PageData{}, err
+	}
+
+	return models, page, nil
+}
+
+// Update
+func (s *CRUDService[T, D]) Update(ctx context.Context, data *D, id uuid.UUID) (*T, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	if hook := s.hooks.BeforeUpdate; hook != nil {
+		if err := hook(ctx, data, id); err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	model, err := s.repo.Update(ctx, data, id)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	if hook := s.hooks.AfterUpdate; hook != nil {
+		if err := hook(ctx, model); err != nil {
+			log.Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
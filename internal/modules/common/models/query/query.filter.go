@@ -2,11 +2,11 @@ package common
 
 // FilterMetadata defines the filter-related response query parameters
 type FilterMetadata struct {
-	Title *string `schema:"title"`
+	Title *string `schema:"title" json:"title,omitempty"`
 }
 
 // FilterQuery defines the filter-related request query paramaters
-// filtered[title]=test
+// filter[title]=test
 type FilterQuery struct {
 	Title *string `schema:"title" json:"title,omitempty"`
 }
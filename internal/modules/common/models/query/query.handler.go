@@ -1,18 +1,30 @@
 package common
 
 import (
+	"errors"
+	"fmt"
 	"net/url"
+	"slices"
 	"strings"
+	"sync/atomic"
 
 	"github.com/gorilla/schema"
 	"github.com/jasonsites/gosk/internal/app"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/validate"
 )
 
 // QueryData composes all query parameters into a single struct for use across the app
 type QueryData[T SortableEntry] struct {
-	Filter *FilterQuery `schema:"filter" json:"filter,omitempty"`
-	Page   PageQuery    `schema:"page" json:"page,omitempty"`
-	Sort   SortQuery[T] `schema:"sort" json:"sort,omitempty"`
+	// Fields defines the raw sparse fieldset query parameters by resource type (fields[type]=a,b), parsed separately
+	// by jsonapi.ParseFieldset
+	Fields map[string]string `schema:"-" json:"fields,omitempty"`
+	Filter *FilterQuery      `schema:"filter" json:"filter,omitempty"`
+	// Include defines the raw include query parameter (parsed separately by jsonapi.ParseInclude)
+	Include *string      `schema:"include" json:"include,omitempty"`
+	Page    PageQuery    `schema:"page" json:"page,omitempty"`
+	Search  *string      `schema:"q" json:"q,omitempty"`
+	Sort    SortQuery[T] `schema:"sort" json:"sort,omitempty"`
 }
 
 type QueryConfig[T SortableEntry] struct {
@@ -23,10 +35,12 @@ type QueryConfig[T SortableEntry] struct {
 type QueryDefaults[T SortableEntry] struct {
 	Page PageQuery    `validate:"required"`
 	Sort SortQuery[T] `validate:"required"`
+	// SearchSort defines the sort applied to search (q) queries that do not specify one (defaults to Sort)
+	SearchSort SortQuery[T]
 }
 
 type QueryHandler[T SortableEntry] struct {
-	defaults     *QueryDefaults[T]
+	defaults     atomic.Pointer[QueryDefaults[T]]
 	entryFactory func() T
 }
 
@@ -41,63 +55,102 @@ func NewQueryHandler[T SortableEntry](c *QueryConfig[T]) (*QueryHandler[T], erro
 	}
 
 	handler := &QueryHandler[T]{
-		defaults:     c.Defaults,
 		entryFactory: c.EntryFactory,
 	}
+	handler.defaults.Store(c.Defaults)
 
 	return handler, nil
 }
 
-func (q *QueryHandler[T]) ParseQuery(qs []byte) *QueryData[T] {
+// SetDefaultPageLimit replaces the page limit applied to queries that do not specify one
+func (q *QueryHandler[T]) SetDefaultPageLimit(limit int) {
+	defaults := *q.defaults.Load()
+	defaults.Page.Limit = &limit
+	q.defaults.Store(&defaults)
+}
+
+// ParseQuery parses and validates query parameters, applying defaults to page and sort parameters that are not
+// specified. Invalid (or unsupported) parameters are returned as a single validation error, with field errors
+// located by query parameter name
+func (q *QueryHandler[T]) ParseQuery(qs []byte) (*QueryData[T], error) {
+	defaults := q.defaults.Load()
 	data := &QueryData[T]{}
 	queryString := string(qs)
 
+	// Parse the query string into url.Values for standard parsing
+	values, err := url.ParseQuery(queryString)
+	if err != nil {
+		return nil, cerror.NewValidationError(err, "query string parse error")
+	}
+
+	var errs cerror.FieldErrors
+	sortError := func(err error) {
+		errs = append(errs, cerror.FieldError{Code: "validation_invalid_sort", Parameter: "sort", Detail: err.Error()})
+	}
+
 	// Check if we have the deeply nested bracket notation for sort
 	if strings.Contains(queryString, "sort[") && strings.Contains(queryString, "][") {
 		// Use custom parser for bracket notation
 		sortQuery, err := ParseDeepNestedQuery(queryString, q.entryFactory)
-		if err == nil {
-			data.Sort = sortQuery
-		} else {
-			// Fall back to defaults if parsing fails
-			data.Sort = q.defaults.Sort
+		if err != nil {
+			sortError(err)
 		}
-	} else {
-		// Parse the query string into url.Values for standard parsing
-		values, err := url.ParseQuery(queryString)
+		data.Sort = sortQuery
+		for key := range values {
+			if strings.HasPrefix(key, "sort[") {
+				values.Del(key)
+			}
+		}
+	} else if values.Has("sort") {
+		// Use custom parser for comma-separated notation (sort=-modified_on,title)
+		sortQuery, err := ParseSortList(values.Get("sort"), q.entryFactory)
 		if err != nil {
-			// If parsing fails, return data with defaults
-			data.Page = q.normalizePage(data.Page)
-			data.Sort = q.normalizeSort(data.Sort)
-			return data
+			sortError(err)
 		}
+		data.Sort = sortQuery
+		values.Del("sort")
+	}
+	if len(errs) == 0 && len(data.Sort) > 0 {
+		if err := data.Sort.Validate(); err != nil {
+			sortError(err)
+		}
+	}
 
-		// Create a new decoder
-		decoder := schema.NewDecoder()
-
-		// Decode the values into our struct
-		if err := decoder.Decode(data, values); err != nil {
-			// If decoding fails, return data with defaults
-			data.Page = q.normalizePage(data.Page)
-			data.Sort = q.normalizeSort(data.Sort)
-			return data
+	// Collect sparse fieldsets (fields[type]), keyed by resource type
+	for key := range values {
+		if t, ok := strings.CutPrefix(key, "fields["); ok && strings.HasSuffix(t, "]") {
+			if data.Fields == nil {
+				data.Fields = make(map[string]string)
+			}
+			data.Fields[strings.TrimSuffix(t, "]")] = values.Get(key)
+			values.Del(key)
 		}
 	}
 
-	// TODO: validate query
-	// if err := app.Validator.Validate.Struct(data); err != nil {
-	// 	return nil, err
-	// }
-	data.Page = q.normalizePage(data.Page)
-	data.Sort = q.normalizeSort(data.Sort)
+	// Decode the remaining values (bracket notation keys as dotted paths) into our struct
+	decoder := schema.NewDecoder()
+	if err := decoder.Decode(data, dottedKeys(values)); err != nil {
+		errs = append(errs, decodeErrors(err)...)
+	}
+
+	errs = validate.Merge(errs, validate.Struct(data, validate.Parameter))
+	if len(errs) > 0 {
+		return nil, cerror.NewValidationError(errs, "query parameter validation error")
+	}
+
+	if data.Search != nil && strings.TrimSpace(*data.Search) == "" {
+		data.Search = nil
+	}
+	data.Page = q.normalizePage(defaults, data.Page)
+	data.Sort = q.normalizeSort(defaults, data.Sort, data.Search)
 
-	return data
+	return data, nil
 }
 
-func (q *QueryHandler[T]) normalizePage(p PageQuery) PageQuery {
+func (q *QueryHandler[T]) normalizePage(defaults *QueryDefaults[T], p PageQuery) PageQuery {
 	page := PageQuery{
-		Limit:  q.defaults.Page.Limit,
-		Offset: q.defaults.Page.Offset,
+		Limit:  defaults.Page.Limit,
+		Offset: defaults.Page.Offset,
 	}
 
 	if p.Limit != nil {
@@ -110,12 +163,59 @@ func (q *QueryHandler[T]) normalizePage(p PageQuery) PageQuery {
 	return page
 }
 
-func (q *QueryHandler[T]) normalizeSort(s SortQuery[T]) SortQuery[T] {
+func (q *QueryHandler[T]) normalizeSort(defaults *QueryDefaults[T], s SortQuery[T], search *string) SortQuery[T] {
 	// If no sort query provided, use defaults
 	if len(s) == 0 {
-		return q.defaults.Sort
+		if search != nil && len(defaults.SearchSort) > 0 {
+			return defaults.SearchSort
+		}
+		return defaults.Sort
 	}
 
 	// Return the provided sort query as-is since validation happens elsewhere
 	return s
 }
+
+// dottedKeys converts bracket notation keys (e.g. page[limit]) to the dotted paths used by the schema decoder (page.limit)
+func dottedKeys(values url.Values) url.Values {
+	result := make(url.Values, len(values))
+	for key, v := range values {
+		key = strings.ReplaceAll(key, "][", ".")
+		key = strings.ReplaceAll(key, "[", ".")
+		key = strings.TrimSuffix(key, "]")
+		result[key] = append(result[key], v...)
+	}
+	return result
+}
+
+// decodeErrors returns query parameter decode errors as field errors, sorted by parameter name
+func decodeErrors(err error) cerror.FieldErrors {
+	var (
+		errs  cerror.FieldErrors
+		multi schema.MultiError
+	)
+	if !errors.As(err, &multi) {
+		return cerror.FieldErrors{{Detail: err.Error()}}
+	}
+
+	for key, err := range multi {
+		fe := cerror.FieldError{Parameter: validate.ParameterName(strings.Split(key, "."))}
+
+		var (
+			conversion schema.ConversionError
+			unknown    schema.UnknownKeyError
+		)
+		switch {
+		case errors.As(err, &conversion):
+			fe.Code, fe.Detail = "validation_invalid_type", fmt.Sprintf("must be a valid %s", conversion.Type)
+		case errors.As(err, &unknown):
+			fe.Code, fe.Detail = "validation_unsupported_parameter", "unsupported query parameter"
+		default:
+			fe.Detail = err.Error()
+		}
+		errs = append(errs, fe)
+	}
+	slices.SortFunc(errs, func(a, b cerror.FieldError) int { return strings.Compare(a.Parameter, b.Parameter) })
+
+	return errs
+}
@@ -10,6 +10,6 @@ type PageMetadata struct {
 // PageQuery defines the paging-related query paramaters
 // p[limit]=20&p[offset]=10
 type PageQuery struct {
-	Limit  *int `schema:"limit" json:"limit,omitempty"`
-	Offset *int `schema:"offset" json:"offset,omitempty"`
+	Limit  *int `schema:"limit" json:"limit,omitempty" validate:"omitempty,min=1"`
+	Offset *int `schema:"offset" json:"offset,omitempty" validate:"omitempty,min=0"`
 }
@@ -199,6 +199,32 @@ func ParseDeepNestedQuery[T SortableEntry](queryString string, createEntry func(
 	return result, nil
 }
 
+// ParseSortList handles comma-separated sort fields like sort=-modified_on,title (descending when
+// prefixed with "-"), converting them to a SortQuery structure with one entry per field
+func ParseSortList[T SortableEntry](list string, createEntry func() T) (SortQuery[T], error) {
+	var result SortQuery[T]
+
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		order := SortOrderAsc
+		if name, ok := strings.CutPrefix(field, "-"); ok {
+			field, order = name, SortOrderDesc
+		}
+
+		entry, err := createEntry().SetFieldFromString(field, order)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sort field: %s", field)
+		}
+		result = append(result, entry.(T))
+	}
+
+	return result, nil
+}
+
 // parseNestedKey parses a key like "sort[0][created_on]" and returns index and field
 func parseNestedKey(key string) (int, string, error) {
 	// Remove "sort[" prefix
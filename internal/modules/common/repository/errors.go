@@ -0,0 +1,71 @@
+package common
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+)
+
+// postgres error codes (https://www.postgresql.org/docs/current/errcodes-appendix.html)
+const (
+	pgForeignKeyViolation = "23503"
+	pgUniqueViolation     = "23505"
+	pgCheckViolation      = "23514"
+	pgQueryCanceled       = "57014"
+)
+
+// TranslateError translates a database error to a domain (custom) error of the given entity (e.g. example_entity)
+// and resource id (uuid.Nil for statements without a single resource id). Custom errors are returned unchanged, and
+// untranslated errors are returned as internal server errors
+func TranslateError(err error, entity string, id uuid.UUID) error {
+	var (
+		cerr  cerror.CustomError
+		pgErr *pgconn.PgError
+	)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &cerr):
+		return err
+	case errors.Is(err, pgx.ErrNoRows):
+		if id == uuid.Nil {
+			return cerror.NewNotFoundError(err, "unable to find %s", entity)
+		}
+		return cerror.NewNotFoundError(err, "unable to find %s with id '%s'", entity, id)
+	case errors.Is(err, context.DeadlineExceeded), pgconn.Timeout(err):
+		return cerror.NewTimeoutError(err, "%s statement timed out", entity)
+	case errors.As(err, &pgErr):
+		return translatePgError(err, pgErr, entity)
+	default:
+		return cerror.NewInternalServerError(err, "%s repository error", entity)
+	}
+}
+
+// translatePgError translates a postgres error, with the violated constraint (if any) as error metadata
+func translatePgError(err error, pgErr *pgconn.PgError, entity string) error {
+	var meta map[string]any
+	if pgErr.ConstraintName != "" {
+		meta = map[string]any{"constraint": pgErr.ConstraintName}
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		err = cerror.NewConflictError(err, "%s violates unique constraint '%s'", entity, pgErr.ConstraintName)
+		return cerror.WithMeta(cerror.WithCode(err, "conflict_unique_violation"), meta)
+	case pgForeignKeyViolation:
+		err = cerror.NewValidationError(err, "%s references a missing resource (constraint '%s')", entity, pgErr.ConstraintName)
+		return cerror.WithMeta(cerror.WithCode(err, "validation_foreign_key_violation"), meta)
+	case pgCheckViolation:
+		err = cerror.NewValidationError(err, "%s violates check constraint '%s'", entity, pgErr.ConstraintName)
+		return cerror.WithMeta(cerror.WithCode(err, "validation_check_violation"), meta)
+	case pgQueryCanceled:
+		return cerror.NewTimeoutError(err, "%s statement timed out", entity)
+	default:
+		return cerror.NewInternalServerError(err, "%s repository error", entity)
+	}
+}
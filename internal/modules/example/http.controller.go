@@ -2,12 +2,15 @@ package example
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/jasonsites/gosk/internal/app"
 	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/export"
 	"github.com/jasonsites/gosk/internal/http/jsonapi"
 	"github.com/jasonsites/gosk/internal/http/jsonio"
 	"github.com/jasonsites/gosk/internal/http/trace"
@@ -19,22 +22,42 @@ type ExampleService interface {
 	Create(context.Context, any) (*ModelContainer, error)
 	Delete(context.Context, uuid.UUID) error
 	Detail(context.Context, uuid.UUID) (*ModelContainer, error)
+	Export(context.Context, ExampleQueryData, func(*ExampleModel) error) error
 	List(context.Context, ExampleQueryData) (*ModelContainer, error)
 	Update(context.Context, any, uuid.UUID) (*ModelContainer, error)
+
+	CreateImport(context.Context, *ImportUpload) (*ImportModel, error)
+	Import(context.Context, io.Reader, string, ImportMode) (*ImportReport, error)
+	ImportStatus(context.Context, uuid.UUID) (*ImportModel, error)
+	RunImport(context.Context, uuid.UUID) error
+
+	IncludeTags(context.Context, *ModelContainer) error
+	Tags(context.Context, uuid.UUID) ([]TagModel, error)
+	UpdateTags(context.Context, RelationshipOp, uuid.UUID, []uuid.UUID) error
 }
 
 // ControllerConfig defines the input to NewController
 type ControllerConfig struct {
-	Logger  *logger.CustomLogger `validate:"required"`
-	Query   *ExampleQueryHandler `validate:"required"`
-	Service ExampleService       `validate:"required"`
+	// ImportSyncMaxSize defines the maximum size (bytes) of imports run synchronously (the maximum size of import
+	// request bodies is a route limit of the body limit middleware)
+	ImportSyncMaxSize int64                `validate:"required,min=1"`
+	Logger            *logger.CustomLogger `validate:"required"`
+	// MaxIncludeDepth defines the maximum relationship path depth of the include query parameter
+	MaxIncludeDepth int `validate:"required,min=1"`
+	// Namespace defines the router namespace, used for resource and relationship links
+	Namespace string               `validate:"required"`
+	Query     *ExampleQueryHandler `validate:"required"`
+	Service   ExampleService       `validate:"required"`
 }
 
 // exampleController
 type exampleController struct {
-	logger  *logger.CustomLogger
-	query   *ExampleQueryHandler
-	service ExampleService
+	basePath          string
+	importSyncMaxSize int64
+	logger            *logger.CustomLogger
+	maxIncludeDepth   int
+	query             *ExampleQueryHandler
+	service           ExampleService
 }
 
 // NewController returns a new Controller instance
@@ -44,9 +67,12 @@ func NewController(c *ControllerConfig) (*exampleController, error) {
 	}
 
 	ctrl := &exampleController{
-		logger:  c.Logger,
-		query:   c.Query,
-		service: c.Service,
+		basePath:          fmt.Sprintf("/%s/examples", c.Namespace),
+		importSyncMaxSize: c.ImportSyncMaxSize,
+		logger:            c.Logger,
+		maxIncludeDepth:   c.MaxIncludeDepth,
+		query:             c.Query,
+		service:           c.Service,
 	}
 
 	return ctrl, nil
@@ -60,8 +86,7 @@ func (c *exampleController) Create(f func() *jsonapi.RequestBody) http.HandlerFu
 		log := c.logger.CreateContextLogger(traceID)
 
 		resource := f()
-		if err := jsonio.DecodeRequest(w, r, resource); err != nil {
-			err = cerror.NewValidationError(err, "request body decode error")
+		if err := jsonio.DecodeValidRequest(w, r, resource); err != nil {
 			log.Error(err.Error())
 			jsonio.EncodeError(w, r, err)
 			return
@@ -75,6 +100,7 @@ func (c *exampleController) Create(f func() *jsonapi.RequestBody) http.HandlerFu
 			return
 		}
 
+		model.BasePath = c.basePath
 		response, err := model.FormatResponse()
 		if err != nil {
 			err = cerror.NewInternalServerError(err, "model format response error")
@@ -129,6 +155,13 @@ func (c *exampleController) Detail() http.HandlerFunc {
 			return
 		}
 
+		include, err := c.parseInclude(r)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
 		model, err := c.service.Detail(ctx, uuid)
 		if err != nil {
 			log.Error(err.Error())
@@ -136,6 +169,13 @@ func (c *exampleController) Detail() http.HandlerFunc {
 			return
 		}
 
+		if err := c.include(ctx, include, model); err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		model.BasePath = c.basePath
 		response, err := model.FormatResponse()
 		if err != nil {
 			err = cerror.NewInternalServerError(err, "error formatting response from model")
@@ -155,8 +195,25 @@ func (c *exampleController) List() http.HandlerFunc {
 		traceID := trace.GetTraceIDFromContext(ctx)
 		log := c.logger.CreateContextLogger(traceID)
 
+		include, err := c.parseInclude(r)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
 		qs := []byte(r.URL.RawQuery)
-		query := c.query.ParseQuery(qs)
+		query, err := c.query.ParseQuery(qs)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		if mediaType := jsonio.RequestRepresentation(r).MediaType; export.IsExport(mediaType) {
+			c.export(w, r, *query, mediaType)
+			return
+		}
 
 		model, err := c.service.List(ctx, *query)
 		if err != nil {
@@ -165,6 +222,13 @@ func (c *exampleController) List() http.HandlerFunc {
 			return
 		}
 
+		if err := c.include(ctx, include, model); err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		model.BasePath = c.basePath
 		response, err := model.FormatResponse()
 		if err != nil {
 			err = cerror.NewInternalServerError(err, "error formatting response from model")
@@ -194,8 +258,7 @@ func (c *exampleController) Update(f func() *jsonapi.RequestBody) http.HandlerFu
 		}
 
 		resource := f()
-		if err := jsonio.DecodeRequest(w, r, resource); err != nil {
-			err = cerror.NewValidationError(err, "request body decode error")
+		if err := jsonio.DecodeValidRequest(w, r, resource); err != nil {
 			log.Error(err.Error())
 			jsonio.EncodeError(w, r, err)
 			return
@@ -209,6 +272,7 @@ func (c *exampleController) Update(f func() *jsonapi.RequestBody) http.HandlerFu
 			return
 		}
 
+		model.BasePath = c.basePath
 		response, err := model.FormatResponse()
 		if err != nil {
 			err = cerror.NewInternalServerError(err, "model format response error")
@@ -220,3 +284,151 @@ func (c *exampleController) Update(f func() *jsonapi.RequestBody) http.HandlerFu
 		jsonio.EncodeResponse(w, r, http.StatusOK, response)
 	}
 }
+
+// Tags responds with the tags related to an example (related resource endpoint)
+func (c *exampleController) Tags() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		id := chi.URLParam(r, "id")
+		uuid, err := uuid.Parse(id)
+		if err != nil {
+			err = cerror.NewValidationError(err, "resource id parse error")
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		tags, err := c.service.Tags(ctx, uuid)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		data := make([]jsonapi.ResponseResource, 0, len(tags))
+		for _, tag := range tags {
+			data = append(data, tag.FormatResource())
+		}
+
+		response := &jsonapi.Response{
+			Links: &jsonapi.Links{Self: fmt.Sprintf("%s/%s/tags", c.basePath, uuid)},
+			Data:  data,
+		}
+
+		jsonio.EncodeResponse(w, r, http.StatusOK, response)
+	}
+}
+
+// TagRelationships responds with the tag resource linkage of an example (relationship endpoint)
+func (c *exampleController) TagRelationships() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		id := chi.URLParam(r, "id")
+		uuid, err := uuid.Parse(id)
+		if err != nil {
+			err = cerror.NewValidationError(err, "resource id parse error")
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		tags, err := c.service.Tags(ctx, uuid)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		data := make([]jsonapi.ResourceIdentifier, 0, len(tags))
+		for _, tag := range tags {
+			data = append(data, tag.Identifier())
+		}
+
+		self := fmt.Sprintf("%s/%s", c.basePath, uuid)
+		response := &jsonapi.RelationshipResponse{
+			Links: &jsonapi.Links{
+				Self:    self + "/relationships/tags",
+				Related: self + "/tags",
+			},
+			Data: data,
+		}
+
+		jsonio.EncodeResponse(w, r, http.StatusOK, response)
+	}
+}
+
+// UpdateTagRelationships adds, removes or replaces (per op) the tags related to an example
+func (c *exampleController) UpdateTagRelationships(op RelationshipOp) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			err = cerror.NewValidationError(err, "resource id parse error")
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		body := &jsonapi.RelationshipRequestBody{}
+		if err := jsonio.DecodeValidRequest(w, r, body); err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		tagIDs := make([]uuid.UUID, 0, len(body.Data))
+		for i, rid := range body.Data {
+			if rid.Type != TagResourceType {
+				errs := cerror.FieldErrors{{
+					Code:    "validation_invalid_resource_type",
+					Detail:  fmt.Sprintf("invalid resource type '%s' (expected '%s')", rid.Type, TagResourceType),
+					Pointer: fmt.Sprintf("/data/%d/type", i),
+				}}
+				err := cerror.NewValidationError(errs, "invalid relationship resource type")
+				log.Error(err.Error())
+				jsonio.EncodeError(w, r, err)
+				return
+			}
+			tagIDs = append(tagIDs, rid.ID)
+		}
+
+		if err := c.service.UpdateTags(ctx, op, id, tagIDs); err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// parseInclude parses the include query parameter
+func (c *exampleController) parseInclude(r *http.Request) (jsonapi.IncludePaths, error) {
+	include, err := jsonapi.ParseInclude(r.URL.Query().Get("include"), c.maxIncludeDepth, ExampleIncludePaths)
+	if err != nil {
+		errs := cerror.FieldErrors{{Code: "validation_unsupported_include", Parameter: "include", Detail: err.Error()}}
+		return nil, cerror.NewValidationError(errs, "invalid include query parameter")
+	}
+
+	return include, nil
+}
+
+// include loads the included relationships of all examples in the given model
+func (c *exampleController) include(ctx context.Context, include jsonapi.IncludePaths, model *ModelContainer) error {
+	if include.Has("tags") {
+		if err := c.service.IncludeTags(ctx, model); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package example
+
+import (
+	"net/http"
+
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/export"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/trace"
+)
+
+// ExportFields defines the example attributes available to list exports (and sparse fieldsets), in column order
+var ExportFields = []string{
+	"title",
+	"description",
+	"status",
+	"enabled",
+	"created_on",
+	"created_by",
+	"modified_on",
+	"modified_by",
+}
+
+// exportValues returns the export column values of an example (id first, then the given fields)
+func exportValues(model *ExampleModel, fields []string) []any {
+	a := model.Attributes
+	values := []any{a.ID}
+	for _, field := range fields {
+		switch field {
+		case "title":
+			values = append(values, a.Title)
+		case "description":
+			values = append(values, a.Description)
+		case "status":
+			values = append(values, a.Status)
+		case "enabled":
+			values = append(values, a.Enabled)
+		case "created_on":
+			values = append(values, a.CreatedOn)
+		case "created_by":
+			values = append(values, a.CreatedBy)
+		case "modified_on":
+			values = append(values, a.ModifiedOn)
+		case "modified_by":
+			values = append(values, a.ModifiedBy)
+		}
+	}
+	return values
+}
+
+// exportFields returns the export fields selected by the fields[example] sparse fieldset (all fields by default)
+func exportFields(query ExampleQueryData) ([]string, error) {
+	raw, ok := query.Fields[ExampleResourceType]
+	if !ok {
+		return ExportFields, nil
+	}
+
+	fields, err := jsonapi.ParseFieldset(raw, ExportFields)
+	if err != nil {
+		parameter := "fields[" + ExampleResourceType + "]"
+		errs := cerror.FieldErrors{{Code: "validation_unsupported_field", Parameter: parameter, Detail: err.Error()}}
+		return nil, cerror.NewValidationError(errs, "invalid %s query parameter", parameter)
+	}
+
+	return fields, nil
+}
+
+// export streams all examples matching the list query (without paging) as a CSV or NDJSON response body. Errors
+// returned before the first row are sent as error responses, and later errors abort the response
+func (c *exampleController) export(w http.ResponseWriter, r *http.Request, query ExampleQueryData, mediaType string) {
+	ctx := r.Context()
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := c.logger.CreateContextLogger(traceID)
+
+	fields, err := exportFields(query)
+	if err != nil {
+		log.Error(err.Error())
+		jsonio.EncodeError(w, r, err)
+		return
+	}
+
+	ew, err := export.NewWriter(w, mediaType, append([]string{"id"}, fields...))
+	if err != nil {
+		err = cerror.NewInternalServerError(err, "error creating export writer")
+		log.Error(err.Error())
+		jsonio.EncodeError(w, r, err)
+		return
+	}
+
+	err = c.service.Export(ctx, query, func(model *ExampleModel) error {
+		return ew.Write(exportValues(model, fields))
+	})
+	if err == nil {
+		err = ew.Close()
+	}
+	if err != nil {
+		log.Error(err.Error())
+		if !ew.Started() {
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+		// the response header has been sent, so the response is aborted (leaving the body incomplete)
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,131 @@
+package example
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/export"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/jobs"
+)
+
+// Import imports the rows of a CSV or NDJSON request body (the import mode query parameter defines whether invalid
+// rows reject the import, or are skipped). Imports up to the synchronous size limit respond with the import report,
+// and larger imports are accepted (202) as import jobs, polled with ImportStatus
+func (c *exampleController) Import() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		mediaType, mode, err := parseImportRequest(r)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		// imports without a Content-Length, or above the synchronous size limit, are run by import jobs
+		if r.ContentLength < 0 || r.ContentLength > c.importSyncMaxSize {
+			model, err := c.service.CreateImport(ctx, &ImportUpload{Body: r.Body, MediaType: mediaType, Mode: mode})
+			if err != nil {
+				err = jsonio.BodyError(err)
+				log.Error(err.Error())
+				jsonio.EncodeError(w, r, err)
+				return
+			}
+
+			w.Header().Set("Location", model.Link(c.basePath))
+			jsonio.EncodeResponse(w, r, http.StatusAccepted, model.FormatResponse(c.basePath))
+			return
+		}
+
+		report, err := c.service.Import(ctx, r.Body, mediaType, mode)
+		if err != nil {
+			err = jsonio.BodyError(err)
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		if mode == ImportModeAtomic && report.Failed > 0 {
+			err := cerror.NewValidationError(report.FieldErrors(), "import rejected with %d invalid rows", report.Failed)
+			err = cerror.WithMeta(err, map[string]any{"total": report.Total, "failed": report.Failed})
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		// synchronous import reports are not stored, and are identified by the request trace id
+		model := &ImportModel{
+			ID:         importReportID(traceID),
+			Attributes: ImportAttributes{Status: jobs.StatusSucceeded, Report: report},
+		}
+		jsonio.EncodeResponse(w, r, http.StatusOK, model.FormatResponse(c.basePath))
+	}
+}
+
+// ImportStatus returns an asynchronous import (by import job id), with the import report once the import has run
+func (c *exampleController) ImportStatus() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		traceID := trace.GetTraceIDFromContext(ctx)
+		log := c.logger.CreateContextLogger(traceID)
+
+		id, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			err = cerror.NewValidationError(err, "resource id parse error")
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		model, err := c.service.ImportStatus(ctx, id)
+		if err != nil {
+			log.Error(err.Error())
+			jsonio.EncodeError(w, r, err)
+			return
+		}
+
+		jsonio.EncodeResponse(w, r, http.StatusOK, model.FormatResponse(c.basePath))
+	}
+}
+
+// importReportID returns the resource id of a synchronous import report: the request trace id, or an id derived
+// from it (for trace ids which are not uuids)
+func importReportID(traceID string) uuid.UUID {
+	if id, err := uuid.Parse(traceID); err == nil {
+		return id
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(traceID))
+}
+
+// parseImportRequest returns the import media type (CSV or NDJSON) and mode (default atomic) of an import request
+func parseImportRequest(r *http.Request) (string, ImportMode, error) {
+	header := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil || !export.IsExport(mediaType) {
+		detail := fmt.Sprintf("unsupported import media type '%s' (supported: %s)", header, strings.Join(export.MediaTypes, ", "))
+		errs := cerror.FieldErrors{{Detail: detail, Header: "Content-Type"}}
+		return "", "", cerror.NewUnsupportedMediaTypeError(errs, "%s", detail)
+	}
+
+	mode := ImportModeAtomic
+	if value := r.URL.Query().Get("mode"); value != "" {
+		mode = ImportMode(value)
+		if !slices.Contains(ImportModes, mode) {
+			detail := fmt.Sprintf("unsupported import mode '%s' (modes: atomic, partial)", value)
+			errs := cerror.FieldErrors{{Code: "validation_oneof", Detail: detail, Parameter: "mode"}}
+			return "", "", cerror.NewValidationError(errs, "invalid mode query parameter")
+		}
+	}
+
+	return mediaType, mode, nil
+}
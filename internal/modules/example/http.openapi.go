@@ -0,0 +1,126 @@
+package example
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jasonsites/gosk/internal/http/export"
+	"github.com/jasonsites/gosk/internal/http/openapi"
+	query "github.com/jasonsites/gosk/internal/modules/common/models/query"
+)
+
+// ExampleOperations describes the routes registered by ExampleRouter
+func ExampleOperations(ns string) []openapi.Operation {
+	prefix := fmt.Sprintf("/%s/examples", ns)
+	tags := []string{"examples"}
+	include := openapi.IncludeParameter(ExampleIncludePaths...)
+
+	ops := openapi.CRUDOperations(prefix, openapi.Resource{
+		Name:             "examples",
+		Type:             ExampleResourceType,
+		Attributes:       openapi.Type[ModelAttributes](),
+		Input:            openapi.Type[ExampleDTORequest](),
+		Filters:          openapi.QueryFields[query.FilterQuery](),
+		Sort:             openapi.QueryFields[SortEntry](),
+		DetailParameters: []*openapi.Parameter{include},
+		ListParameters: []*openapi.Parameter{
+			openapi.QueryParameter("q", "full-text search query (results sorted by relevance by default)", &openapi.Schema{Type: "string"}),
+			include,
+			openapi.FieldsParameter(ExampleResourceType, ExportFields...),
+		},
+		ListAlternates: map[string]*openapi.Schema{
+			export.MediaTypeCSV:    {Type: "string"},
+			export.MediaTypeNDJSON: {Type: "string"},
+		},
+	})
+
+	relationship := func(method, id, summary string) openapi.Operation {
+		op := openapi.Operation{
+			Method:     method,
+			Pattern:    prefix + "/{id}/relationships/tags",
+			ID:         id,
+			Summary:    summary,
+			Tags:       tags,
+			Parameters: []*openapi.Parameter{openapi.IDParameter()},
+			Responses: []openapi.Response{
+				{Status: http.StatusNoContent},
+				openapi.ErrorResponse(http.StatusBadRequest),
+				openapi.ErrorResponse(http.StatusNotFound),
+				openapi.ErrorResponse(http.StatusConflict),
+				openapi.ErrorResponse(http.StatusInternalServerError),
+			},
+		}
+		if method != http.MethodGet {
+			op.Request = openapi.RelationshipRequest(TagResourceType)
+		}
+		return op
+	}
+
+	tagRelationships := relationship(http.MethodGet, "examples.tags.relationships", "Get example tag relationships")
+	tagRelationships.Responses = []openapi.Response{
+		{Status: http.StatusOK, Schema: openapi.RelationshipResponse(TagResourceType)},
+		openapi.ErrorResponse(http.StatusBadRequest),
+		openapi.ErrorResponse(http.StatusNotFound),
+		openapi.ErrorResponse(http.StatusInternalServerError),
+	}
+
+	ops = append(ops,
+		openapi.Operation{
+			Method:     http.MethodGet,
+			Pattern:    prefix + "/{id}/tags",
+			ID:         "examples.tags",
+			Summary:    "List example tags",
+			Tags:       tags,
+			Parameters: []*openapi.Parameter{openapi.IDParameter()},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Schema: openapi.CollectionResponse(TagResourceType, openapi.Type[TagAttributes]())},
+				openapi.ErrorResponse(http.StatusBadRequest),
+				openapi.ErrorResponse(http.StatusNotFound),
+				openapi.ErrorResponse(http.StatusInternalServerError),
+			},
+		},
+		openapi.Operation{
+			Method:  http.MethodPost,
+			Pattern: prefix + "/import",
+			ID:      "examples.import",
+			Summary: "Import examples from CSV or NDJSON (asynchronously above the synchronous size limit)",
+			Tags:    tags,
+			Parameters: []*openapi.Parameter{
+				openapi.QueryParameter("mode", "import mode (default atomic): atomic imports no rows when any row is invalid, and partial skips invalid rows",
+					&openapi.Schema{Type: "string", Enum: []any{ImportModeAtomic, ImportModePartial}}),
+			},
+			RequestAlternates: map[string]*openapi.Schema{
+				export.MediaTypeCSV:    {Type: "string"},
+				export.MediaTypeNDJSON: {Type: "string"},
+			},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Schema: openapi.ResourceResponse(ImportResourceType, openapi.Type[ImportAttributes]())},
+				{Status: http.StatusAccepted, Schema: openapi.ResourceResponse(ImportResourceType, openapi.Type[ImportAttributes]())},
+				openapi.ErrorResponse(http.StatusBadRequest),
+				openapi.ErrorResponse(http.StatusRequestEntityTooLarge),
+				openapi.ErrorResponse(http.StatusUnsupportedMediaType),
+				openapi.ErrorResponse(http.StatusInternalServerError),
+			},
+		},
+		openapi.Operation{
+			Method:     http.MethodGet,
+			Pattern:    prefix + "/import/{id}",
+			ID:         "examples.import.status",
+			Summary:    "Get example import job status",
+			Tags:       tags,
+			Parameters: []*openapi.Parameter{openapi.IDParameter()},
+			Responses: []openapi.Response{
+				{Status: http.StatusOK, Schema: openapi.ResourceResponse(ImportResourceType, openapi.Type[ImportAttributes]())},
+				openapi.ErrorResponse(http.StatusBadRequest),
+				openapi.ErrorResponse(http.StatusNotFound),
+				openapi.ErrorResponse(http.StatusInternalServerError),
+			},
+		},
+		tagRelationships,
+		relationship(http.MethodPost, "examples.tags.add", "Add example tag relationships"),
+		relationship(http.MethodPatch, "examples.tags.replace", "Replace example tag relationships"),
+		relationship(http.MethodDelete, "examples.tags.remove", "Remove example tag relationships"),
+	)
+
+	return ops
+}
@@ -13,8 +13,14 @@ type ExampleController interface {
 	Create(func() *jsonapi.RequestBody) http.HandlerFunc
 	Delete() http.HandlerFunc
 	Detail() http.HandlerFunc
+	Import() http.HandlerFunc
+	ImportStatus() http.HandlerFunc
 	List() http.HandlerFunc
 	Update(func() *jsonapi.RequestBody) http.HandlerFunc
+
+	Tags() http.HandlerFunc
+	TagRelationships() http.HandlerFunc
+	UpdateTagRelationships(RelationshipOp) http.HandlerFunc
 }
 
 // ExampleRouter implements a router group for an Example resource
@@ -37,5 +43,14 @@ func ExampleRouter(r *chi.Mux, ns string, c ExampleController) {
 		r.Post("/", c.Create(resource))
 		r.Put("/{id}", c.Update(resource))
 		r.Delete("/{id}", c.Delete())
+
+		r.Post("/import", c.Import())
+		r.Get("/import/{id}", c.ImportStatus())
+
+		r.Get("/{id}/tags", c.Tags())
+		r.Get("/{id}/relationships/tags", c.TagRelationships())
+		r.Post("/{id}/relationships/tags", c.UpdateTagRelationships(RelationshipAdd))
+		r.Patch("/{id}/relationships/tags", c.UpdateTagRelationships(RelationshipReplace))
+		r.Delete("/{id}/relationships/tags", c.UpdateTagRelationships(RelationshipRemove))
 	})
 }
@@ -1,6 +1,7 @@
 package example
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,21 +11,27 @@ import (
 
 // ModelContainer contains one or more ExampleModel(s) and related metadata
 type ModelContainer struct {
-	Data []ExampleModel
-	Meta *ModelContainerMeta
-	Solo bool
+	// BasePath defines the collection path used for resource and relationship links (e.g. /domain/examples),
+	// with links omitted when empty
+	BasePath string
+	Data     []ExampleModel
+	Meta     *ModelContainerMeta
+	Solo     bool
 }
 
 type ModelContainerMeta struct {
 	Filter *query.FilterMetadata `json:"filter,omitempty"`
 	Page   query.PageMetadata    `json:"page,omitempty"`
+	Search *string               `json:"q,omitempty"`
 	Sort   ExampleSortMetadata   `json:"sort,omitempty"`
 }
 
 // ExampleModel
 type ExampleModel struct {
-	Meta       any
+	Meta       *jsonapi.ResourceMetadata
 	Attributes ModelAttributes
+	// Tags defines the related tags, when loaded (nil when not loaded)
+	Tags []TagModel
 }
 
 // Example defines an Example domain model for application logic
@@ -42,9 +49,14 @@ type ModelAttributes struct {
 }
 
 func (m *ModelContainer) FormatResponse() (*jsonapi.Response, error) {
+	included := &jsonapi.Included{}
+
 	if m.Solo {
-		resource := formatResource(&m.Data[0])
-		response := &jsonapi.Response{Data: resource}
+		resource := m.formatResource(&m.Data[0], included)
+		response := &jsonapi.Response{
+			Data:     resource,
+			Included: included.Resources(),
+		}
 		return response, nil
 	}
 
@@ -55,28 +67,63 @@ func (m *ModelContainer) FormatResponse() (*jsonapi.Response, error) {
 			Offset: m.Meta.Page.Offset,
 			Total:  m.Meta.Page.Total,
 		},
-		Sort: &m.Meta.Sort,
+		Search: m.Meta.Search,
+		Sort:   &m.Meta.Sort,
 	}
 
 	data := make([]jsonapi.ResponseResource, 0, len(m.Data))
 	for _, domo := range m.Data {
-		resource := formatResource(&domo)
+		resource := m.formatResource(&domo, included)
 		data = append(data, resource)
 	}
 	response := &jsonapi.Response{
-		Meta: meta,
-		Data: data,
+		Meta:     meta,
+		Data:     data,
+		Included: included.Resources(),
 	}
 
 	return response, nil
 }
 
-// serializeResource
-func formatResource(domo *ExampleModel) jsonapi.ResponseResource {
+// formatResource formats a single resource, adding loaded related resources to included
+func (m *ModelContainer) formatResource(domo *ExampleModel, included *jsonapi.Included) jsonapi.ResponseResource {
+	var (
+		links         *jsonapi.Links
+		relationships map[string]jsonapi.Relationship
+	)
+
+	if m.BasePath != "" {
+		self := fmt.Sprintf("%s/%s", m.BasePath, domo.Attributes.ID)
+		links = &jsonapi.Links{Self: self}
+		relationships = map[string]jsonapi.Relationship{
+			"tags": {
+				Links: &jsonapi.Links{
+					Self:    self + "/relationships/tags",
+					Related: self + "/tags",
+				},
+			},
+		}
+	}
+
+	if domo.Tags != nil {
+		tags := relationships["tags"]
+		ids := make([]jsonapi.ResourceIdentifier, 0, len(domo.Tags))
+		for _, tag := range domo.Tags {
+			ids = append(ids, tag.Identifier())
+			included.Add(tag.FormatResource())
+		}
+		if relationships == nil {
+			relationships = make(map[string]jsonapi.Relationship)
+		}
+		relationships["tags"] = jsonapi.ToMany(tags.Links, ids)
+	}
+
 	return jsonapi.ResponseResource{
-		Type: "example", // TODO
-		ID:   domo.Attributes.ID,
-		// Meta: domo.Meta,
+		Type:          ExampleResourceType,
+		ID:            domo.Attributes.ID,
+		Meta:          domo.Meta,
+		Relationships: relationships,
+		Links:         links,
 		Attributes: ModelAttributes{
 			Title:       domo.Attributes.Title,
 			Description: domo.Attributes.Description,
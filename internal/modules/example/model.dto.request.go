@@ -2,12 +2,13 @@ package example
 
 import (
 	v "github.com/invopop/validation"
+	"github.com/jasonsites/gosk/internal/logger"
 )
 
 // ExampleDTORequest defines the subset of Example domain model attributes that are accepted
-// for input data request binding
+// for input data request binding. Free-text descriptions may contain personal data, and are redacted from logs
 type ExampleDTORequest struct {
-	Description *string `json:"description" validate:"omitempty,min=3,max=999"`
+	Description *string `json:"description" redact:"true" validate:"omitempty,min=3,max=999"`
 	Title       string  `json:"title" validate:"required,omitempty,min=2,max=255"`
 }
 
@@ -21,3 +22,8 @@ func (e ExampleDTORequest) Validate() error {
 
 	return nil
 }
+
+// ExampleRedactedFields returns the JSON paths of the redacted (tagged) request body fields of Example resources
+func ExampleRedactedFields() []string {
+	return logger.TaggedFields("data.attributes", ExampleDTORequest{})
+}
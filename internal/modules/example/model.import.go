@@ -0,0 +1,157 @@
+package example
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/jobs"
+)
+
+const (
+	// ImportResourceType defines the JSON:API resource type of example imports
+	ImportResourceType = "example-import"
+	// ImportJobKind defines the job kind of asynchronous example imports
+	ImportJobKind = "example.import"
+	// importBatchSize defines the number of rows inserted per CopyFrom batch
+	importBatchSize = 500
+	// importChunkSize defines the size of the chunks in which uploaded import files are stored (and read back), so
+	// that asynchronous imports are not held in memory
+	importChunkSize = 1 << 20
+	// maxImportErrors defines the maximum number of row errors listed by an import report (further invalid rows
+	// are only counted)
+	maxImportErrors = 1000
+)
+
+// ImportMode defines how invalid rows are handled by an import
+type ImportMode string
+
+const (
+	// ImportModeAtomic imports all rows or none: no rows are imported when any row is invalid
+	ImportModeAtomic ImportMode = "atomic"
+	// ImportModePartial imports all valid rows, skipping invalid rows (best effort)
+	ImportModePartial ImportMode = "partial"
+)
+
+// ImportModes defines all import modes
+var ImportModes = []ImportMode{ImportModeAtomic, ImportModePartial}
+
+// ImportUpload defines an uploaded import file, stored (in chunks) for asynchronous imports
+type ImportUpload struct {
+	// Body defines the uploaded file, streamed from the request body when stored, and from the stored chunks when
+	// imported
+	Body      io.Reader
+	MediaType string
+	Mode      ImportMode
+}
+
+// ImportReport defines the outcome of an import, with the errors of invalid rows
+type ImportReport struct {
+	Mode ImportMode `json:"mode"`
+	// Total defines the number of rows read, Imported the number of rows inserted and Failed the number of invalid
+	// rows (including an invalid CSV header row)
+	Total    int              `json:"total"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// ImportRowError defines a single invalid row (and field) of an import
+type ImportRowError struct {
+	// Row defines the (1-based) row number, excluding the CSV header row (row 0 for errors of the header row)
+	Row    int    `json:"row"`
+	Field  string `json:"field,omitempty"`
+	Code   string `json:"code"`
+	Detail string `json:"detail"`
+}
+
+// addRowErrors adds the field errors of an invalid row to the report
+func (ir *ImportReport) addRowErrors(row int, errs cerror.FieldErrors) {
+	ir.Failed++
+	for _, fe := range errs {
+		if len(ir.Errors) == maxImportErrors {
+			return
+		}
+		code := fe.Code
+		if code == "" {
+			code = cerror.ErrorCode.Validation
+		}
+		ir.Errors = append(ir.Errors, ImportRowError{Row: row, Field: fe.Parameter, Code: code, Detail: fe.Detail})
+	}
+}
+
+// FieldErrors returns the row errors of the report as field errors, located by /{row}/{field} JSON pointers
+func (ir *ImportReport) FieldErrors() cerror.FieldErrors {
+	errs := make(cerror.FieldErrors, 0, len(ir.Errors))
+	for _, e := range ir.Errors {
+		pointer := fmt.Sprintf("/%d", e.Row)
+		if e.Field != "" {
+			pointer += "/" + e.Field
+		}
+		errs = append(errs, cerror.FieldError{Code: e.Code, Detail: e.Detail, Pointer: pointer})
+	}
+	return errs
+}
+
+// ImportAttributes defines the attributes of an example import resource. Status defines the status of the import
+// job (pending, running, succeeded or dead), and Report is set once the import has run
+type ImportAttributes struct {
+	Status   jobs.Status   `json:"status"`
+	Attempts int           `json:"attempts"`
+	Error    *string       `json:"error,omitempty"`
+	Report   *ImportReport `json:"report,omitempty"`
+}
+
+// ImportModel defines an example import, run synchronously or by an import job
+type ImportModel struct {
+	ID         uuid.UUID
+	Attributes ImportAttributes
+	// Async defines whether the import is run by an import job (which may be polled for status)
+	Async bool
+}
+
+// ImportModelFromJob returns the example import model of an import job
+func ImportModelFromJob(job *jobs.Job) (*ImportModel, error) {
+	model := &ImportModel{
+		ID:    job.ID,
+		Async: true,
+		Attributes: ImportAttributes{
+			Status:   job.Status,
+			Attempts: job.Attempts,
+			Error:    job.LastError,
+		},
+	}
+
+	if len(job.Result) > 0 {
+		report := &ImportReport{}
+		if err := json.Unmarshal(job.Result, report); err != nil {
+			return nil, fmt.Errorf("import job result unmarshal error: %w", err)
+		}
+		model.Attributes.Report = report
+	}
+
+	return model, nil
+}
+
+// FormatResponse returns the example import as a JSON:API response, linked to the import job resource (for
+// asynchronous imports)
+func (m *ImportModel) FormatResponse(basePath string) *jsonapi.Response {
+	resource := jsonapi.ResponseResource{
+		Type:       ImportResourceType,
+		ID:         m.ID,
+		Attributes: m.Attributes,
+	}
+	if m.Async {
+		resource.Links = &jsonapi.Links{Self: m.Link(basePath)}
+	}
+
+	return &jsonapi.Response{Data: resource}
+}
+
+// Link returns the import job resource link of an asynchronous import
+func (m *ImportModel) Link(basePath string) string {
+	return fmt.Sprintf("%s/import/%s", basePath, m.ID)
+}
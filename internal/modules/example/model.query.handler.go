@@ -1,6 +1,7 @@
 package example
 
 import (
+	cerror "github.com/jasonsites/gosk/internal/cerror"
 	q "github.com/jasonsites/gosk/internal/modules/common/models/query"
 )
 
@@ -26,8 +27,37 @@ func DefaultExampleSortQuery() q.SortQuery[SortEntry] {
 	}
 }
 
-// ParseQuery parses query parameters for the Example module
-func (h *ExampleQueryHandler) ParseQuery(qs []byte) *ExampleQueryData {
-	result := (*q.QueryHandler[SortEntry])(h).ParseQuery(qs)
-	return (*ExampleQueryData)(result)
+// DefaultExampleSearchSortQuery returns the default sort configuration for Example search (q) queries
+func DefaultExampleSearchSortQuery() q.SortQuery[SortEntry] {
+	desc := q.SortOrderDesc
+	return q.SortQuery[SortEntry]{
+		SortEntry{Relevance: &desc},
+	}
+}
+
+// ParseQuery parses and validates query parameters for the Example module
+func (h *ExampleQueryHandler) ParseQuery(qs []byte) (*ExampleQueryData, error) {
+	result, err := (*q.QueryHandler[SortEntry])(h).ParseQuery(qs)
+	if err != nil {
+		return nil, err
+	}
+	// relevance is only defined for search queries
+	if result.Search == nil {
+		for _, pair := range result.Sort.GetSortPairs() {
+			if pair.Field == "relevance" {
+				errs := cerror.FieldErrors{{
+					Code:      "validation_invalid_sort",
+					Parameter: "sort",
+					Detail:    "relevance sort requires a search (q) query",
+				}}
+				return nil, cerror.NewValidationError(errs, "query parameter validation error")
+			}
+		}
+	}
+	return (*ExampleQueryData)(result), nil
+}
+
+// SetDefaultPageLimit replaces the default page limit for the Example module
+func (h *ExampleQueryHandler) SetDefaultPageLimit(limit int) {
+	(*q.QueryHandler[SortEntry])(h).SetDefaultPageLimit(limit)
 }
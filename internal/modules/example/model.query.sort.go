@@ -17,7 +17,9 @@ type ExampleSortQuery q.SortQuery[SortEntry]
 type SortEntry struct {
 	CreatedOn  *q.SortOrder `schema:"created_on" json:"created_on,omitempty"`
 	ModifiedOn *q.SortOrder `schema:"modified_on" json:"modified_on,omitempty"`
-	Title      *q.SortOrder `schema:"title" json:"title,omitempty"`
+	// Relevance orders search (q) results by rank, and is rejected for non-search queries
+	Relevance *q.SortOrder `schema:"relevance" json:"relevance,omitempty"`
+	Title     *q.SortOrder `schema:"title" json:"title,omitempty"`
 }
 
 // GetFieldCount returns the number of non-nil fields in the entry
@@ -29,6 +31,9 @@ func (se SortEntry) GetFieldCount() int {
 	if se.ModifiedOn != nil {
 		count++
 	}
+	if se.Relevance != nil {
+		count++
+	}
 	if se.Title != nil {
 		count++
 	}
@@ -37,7 +42,7 @@ func (se SortEntry) GetFieldCount() int {
 
 // HasAnyField returns true if at least one field is set
 func (se SortEntry) HasAnyField() bool {
-	return se.CreatedOn != nil || se.ModifiedOn != nil || se.Title != nil
+	return se.CreatedOn != nil || se.ModifiedOn != nil || se.Relevance != nil || se.Title != nil
 }
 
 // GetActiveFields returns a map of active field names and their orders
@@ -49,6 +54,9 @@ func (se SortEntry) GetActiveFields() map[string]q.SortOrder {
 	if se.ModifiedOn != nil {
 		fields["modified_on"] = *se.ModifiedOn
 	}
+	if se.Relevance != nil {
+		fields["relevance"] = *se.Relevance
+	}
 	if se.Title != nil {
 		fields["title"] = *se.Title
 	}
@@ -77,6 +85,12 @@ func (se SortEntry) GetSortPairs() []struct {
 			Order q.SortOrder
 		}{Field: "modified_on", Order: *se.ModifiedOn})
 	}
+	if se.Relevance != nil {
+		pairs = append(pairs, struct {
+			Field string
+			Order q.SortOrder
+		}{Field: "relevance", Order: *se.Relevance})
+	}
 	if se.Title != nil {
 		pairs = append(pairs, struct {
 			Field string
@@ -94,6 +108,8 @@ func (se SortEntry) SetFieldFromString(fieldName string, order q.SortOrder) (q.S
 		se.CreatedOn = &order
 	case "modified_on":
 		se.ModifiedOn = &order
+	case "relevance":
+		se.Relevance = &order
 	case "title":
 		se.Title = &order
 	default:
@@ -104,5 +120,5 @@ func (se SortEntry) SetFieldFromString(fieldName string, order q.SortOrder) (q.S
 
 // GetValidFieldNames returns a list of valid field names for this entry type
 func (se SortEntry) GetValidFieldNames() []string {
-	return []string{"created_on", "modified_on", "title"}
+	return []string{"created_on", "modified_on", "relevance", "title"}
 }
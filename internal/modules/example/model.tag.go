@@ -0,0 +1,53 @@
+package example
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+)
+
+const (
+	// ExampleResourceType defines the JSON:API resource type of Example resources
+	ExampleResourceType = "example"
+	// TagResourceType defines the JSON:API resource type of Tag resources
+	TagResourceType = "tag"
+)
+
+// RelationshipOp defines a to-many relationship update operation
+type RelationshipOp string
+
+const (
+	RelationshipAdd     RelationshipOp = "add"
+	RelationshipRemove  RelationshipOp = "remove"
+	RelationshipReplace RelationshipOp = "replace"
+)
+
+// ExampleIncludePaths defines the relationship paths supported by the include query parameter
+var ExampleIncludePaths = []string{"tags"}
+
+// TagModel defines a Tag domain model, related to Examples (to-many)
+type TagModel struct {
+	Attributes TagAttributes
+}
+
+// TagAttributes defines the Tag domain model attributes
+type TagAttributes struct {
+	ID        uuid.UUID `json:"-"`
+	Name      string    `json:"name"`
+	CreatedOn time.Time `json:"created_on"`
+}
+
+// Identifier returns the tag resource identifier (resource linkage)
+func (t TagModel) Identifier() jsonapi.ResourceIdentifier {
+	return jsonapi.ResourceIdentifier{Type: TagResourceType, ID: t.Attributes.ID}
+}
+
+// FormatResource formats the tag as a JSON:API resource (e.g. for included)
+func (t TagModel) FormatResource() jsonapi.ResponseResource {
+	return jsonapi.ResponseResource{
+		Type:       TagResourceType,
+		ID:         t.Attributes.ID,
+		Attributes: t.Attributes,
+	}
+}
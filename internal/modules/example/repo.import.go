@@ -0,0 +1,205 @@
+package example
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jasonsites/gosk/internal/database"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/jobs"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+)
+
+const (
+	// importUploadTable defines the table of uploaded files awaiting asynchronous import, keyed by import job id
+	importUploadTable = "example_import"
+	// importChunkTable defines the table of uploaded file chunks, keyed by import job id and sequence number
+	importChunkTable = "example_import_chunk"
+)
+
+// errImportRollback rolls back atomic import transactions
+var errImportRollback = errors.New("import rolled back")
+
+// copier defines the CopyFrom interface shared by pools and transactions
+type copier interface {
+	CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error)
+}
+
+// Import runs fn with an insert function copying batches of examples (with CopyFrom). Atomic imports run in a
+// single transaction, committed only when fn returns commit true, and batches of other imports are committed as they
+// are inserted
+func (r *exampleRepository) Import(ctx context.Context, atomic bool, fn func(insert func([]*ExampleDTORequest) error) (bool, error)) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	createdContext, err := json.Marshal(map[string]any{"user_id": "system"})
+	if err != nil {
+		return err
+	}
+
+	field := r.Entity.Field
+	columns := []string{field.Title, field.Description, field.CreatedContext}
+	insert := func(db copier) func([]*ExampleDTORequest) error {
+		return func(batch []*ExampleDTORequest) error {
+			_, err := db.CopyFrom(ctx, pgx.Identifier{r.Entity.Name}, columns,
+				pgx.CopyFromSlice(len(batch), func(i int) ([]any, error) {
+					return []any{batch[i].Title, batch[i].Description, createdContext}, nil
+				}),
+			)
+			return err
+		}
+	}
+
+	if atomic {
+		err = pgx.BeginFunc(ctx, r.db.Primary(), func(tx pgx.Tx) error {
+			commit, err := fn(insert(tx))
+			if err == nil && !commit {
+				err = errImportRollback
+			}
+			return err
+		})
+		if errors.Is(err, errImportRollback) {
+			err = nil
+		}
+	} else {
+		_, err = fn(insert(r.db.Primary()))
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return repo.TranslateError(err, r.Entity.Name, uuid.Nil)
+	}
+
+	return nil
+}
+
+// CreateImport stores an uploaded import file (read in chunks of importChunkSize) and enqueues its import job in a
+// single transaction, returning the job. Upload read errors are returned as-is (e.g. for body size limits)
+func (r *exampleRepository) CreateImport(ctx context.Context, upload *ImportUpload) (*jobs.Job, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	var (
+		job     *jobs.Job
+		readErr error
+	)
+	err := pgx.BeginFunc(ctx, r.db.Primary(), func(tx pgx.Tx) error {
+		var err error
+		// imports are not retried, as rows of partial imports may already be committed
+		if job, err = r.jobs.EnqueueTx(ctx, tx, ImportJobKind, struct{}{}, &jobs.EnqueueOptions{MaxAttempts: 1}); err != nil {
+			return err
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (job_id, media_type, mode) VALUES ($1, $2, $3)", importUploadTable)
+		if _, err = tx.Exec(ctx, query, job.ID, upload.MediaType, upload.Mode); err != nil {
+			return err
+		}
+
+		query = fmt.Sprintf("INSERT INTO %s (job_id, seq, data) VALUES ($1, $2, $3)", importChunkTable)
+		chunk := make([]byte, importChunkSize)
+		for seq := 0; ; seq++ {
+			n, err := io.ReadFull(upload.Body, chunk)
+			if n > 0 {
+				if _, err := tx.Exec(ctx, query, job.ID, seq, chunk[:n]); err != nil {
+					return err
+				}
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return nil
+			}
+			if err != nil {
+				readErr = err
+				return err
+			}
+		}
+	})
+	if readErr != nil {
+		log.Error(readErr.Error())
+		return nil, readErr
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return nil, repo.TranslateError(err, importUploadTable, uuid.Nil)
+	}
+
+	return job, nil
+}
+
+// DeleteImportUpload deletes the uploaded file of an import job
+func (r *exampleRepository) DeleteImportUpload(ctx context.Context, id uuid.UUID) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE job_id = $1", importUploadTable)
+	if _, err := r.db.Write(ctx).Exec(ctx, query, id); err != nil {
+		log.Error(err.Error())
+		return repo.TranslateError(err, importUploadTable, id)
+	}
+
+	return nil
+}
+
+// ImportJob returns an import job (not found for jobs of other kinds)
+func (r *exampleRepository) ImportJob(ctx context.Context, id uuid.UUID) (*jobs.Job, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	job, err := r.jobs.Get(ctx, id)
+	if err == nil && job.Kind != ImportJobKind {
+		err = pgx.ErrNoRows
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return nil, repo.TranslateError(err, ImportResourceType, id)
+	}
+
+	return job, nil
+}
+
+// ImportUpload returns the uploaded file of an import job, with a body reading the stored chunks one at a time
+func (r *exampleRepository) ImportUpload(ctx context.Context, id uuid.UUID) (*ImportUpload, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	upload := &ImportUpload{}
+	query := fmt.Sprintf("SELECT media_type, mode FROM %s WHERE job_id = $1", importUploadTable)
+	if err := r.db.Write(ctx).QueryRow(ctx, query, id).Scan(&upload.MediaType, &upload.Mode); err != nil {
+		log.Error(err.Error())
+		return nil, repo.TranslateError(err, importUploadTable, id)
+	}
+	upload.Body = &importChunkReader{ctx: ctx, db: r.db.Write(ctx), id: id}
+
+	return upload, nil
+}
+
+// importChunkReader reads the stored chunks of an uploaded import file in sequence, holding a single chunk in memory
+type importChunkReader struct {
+	chunk []byte
+	ctx   context.Context
+	db    database.DB
+	id    uuid.UUID
+	seq   int
+}
+
+// Read implements io.Reader, querying the next chunk once the current chunk has been read
+func (cr *importChunkReader) Read(p []byte) (int, error) {
+	for len(cr.chunk) == 0 {
+		query := fmt.Sprintf("SELECT data FROM %s WHERE job_id = $1 AND seq = $2", importChunkTable)
+		err := cr.db.QueryRow(cr.ctx, query, cr.id, cr.seq).Scan(&cr.chunk)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, io.EOF
+		}
+		if err != nil {
+			return 0, err
+		}
+		cr.seq++
+	}
+
+	n := copy(p, cr.chunk)
+	cr.chunk = cr.chunk[n:]
+	return n, nil
+}
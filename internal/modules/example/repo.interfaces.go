@@ -22,6 +22,14 @@ type ExampleEntity struct {
 
 type ExampleEntityModel struct {
 	Record ExampleEntity
+	Search *ExampleSearchMatch
+}
+
+// ExampleSearchMatch defines the full-text search rank and highlighted snippets of a matched entity
+type ExampleSearchMatch struct {
+	Description string
+	Rank        float32
+	Title       string
 }
 
 // exampleEntityDefinition
@@ -40,6 +48,7 @@ type exampleEntityFieldMap struct {
 	CreatedOn       string
 	ModifiedContext string
 	ModifiedOn      string
+	Search          string
 }
 
 // exampleEntity
@@ -54,5 +63,52 @@ var exampleEntity = exampleEntityDefinition{
 		CreatedOn:       "created_on",
 		ModifiedContext: "modified_context",
 		ModifiedOn:      "modified_on",
+		Search:          "search",
+	},
+}
+
+// TagEntity defines a Tag database entity
+type TagEntity struct {
+	ID        uuid.UUID
+	Name      string
+	CreatedOn time.Time
+}
+
+// tagEntityDefinition
+type tagEntityDefinition struct {
+	Field tagEntityFieldMap
+	Join  exampleTagEntityFieldMap
+	Name  string
+	// JoinName defines the example_entity/tag join table
+	JoinName string
+}
+
+// tagEntityFieldMap
+type tagEntityFieldMap struct {
+	ID        string
+	Name      string
+	CreatedOn string
+}
+
+// exampleTagEntityFieldMap
+type exampleTagEntityFieldMap struct {
+	ExampleID string
+	TagID     string
+	CreatedOn string
+}
+
+// tagEntity
+var tagEntity = tagEntityDefinition{
+	Name: "tag",
+	Field: tagEntityFieldMap{
+		ID:        "id",
+		Name:      "name",
+		CreatedOn: "created_on",
+	},
+	JoinName: "example_entity_tag",
+	Join: exampleTagEntityFieldMap{
+		ExampleID: "example_id",
+		TagID:     "tag_id",
+		CreatedOn: "created_on",
 	},
 }
@@ -2,8 +2,10 @@ package example
 
 import (
 	"encoding/json"
+	"strings"
 	"time"
 
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
 	query "github.com/jasonsites/gosk/internal/modules/common/models/query"
 	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
 )
@@ -25,6 +27,7 @@ func MarshalEntityModelList(ems []*ExampleEntityModel, lqd ListQueryData) *Model
 
 	for _, em := range ems {
 		edo := marshalEntity(em.Record)
+		edo.Meta = marshalSearchMatch(em.Search)
 		data = append(data, *edo)
 	}
 
@@ -37,8 +40,10 @@ func MarshalEntityModelList(ems []*ExampleEntityModel, lqd ListQueryData) *Model
 }
 
 type ListQueryData struct {
-	Page repo.PageData
-	Sort ExampleSortMetadata
+	Filter *query.FilterQuery
+	Page   repo.PageData
+	Search *string
+	Sort   ExampleSortMetadata
 }
 
 func MarshalListMetadata(lqd ListQueryData) *ModelContainerMeta {
@@ -48,12 +53,38 @@ func MarshalListMetadata(lqd ListQueryData) *ModelContainerMeta {
 			Offset: uint32(lqd.Page.Offset),
 			Total:  uint32(lqd.Page.Total),
 		},
-		Sort: lqd.Sort,
+		Search: lqd.Search,
+		Sort:   lqd.Sort,
+	}
+	if lqd.Filter != nil && lqd.Filter.Title != nil {
+		meta.Filter = &query.FilterMetadata{Title: lqd.Filter.Title}
 	}
 
 	return meta
 }
 
+// marshalSearchMatch returns the resource metadata for a search match (nil for non-search queries)
+func marshalSearchMatch(m *ExampleSearchMatch) *jsonapi.ResourceMetadata {
+	if m == nil {
+		return nil
+	}
+
+	highlights := make(map[string]string)
+	if strings.Contains(m.Title, "<mark>") {
+		highlights["title"] = m.Title
+	}
+	if strings.Contains(m.Description, "<mark>") {
+		highlights["description"] = m.Description
+	}
+
+	return &jsonapi.ResourceMetadata{
+		Search: &jsonapi.SearchMetadata{
+			Highlights: highlights,
+			Rank:       m.Rank,
+		},
+	}
+}
+
 func marshalEntity(e ExampleEntity) *ExampleModel {
 	var (
 		description *string
@@ -133,3 +164,13 @@ func marshalEntity(e ExampleEntity) *ExampleModel {
 		Attributes: attributes,
 	}
 }
+
+func marshalTag(e TagEntity) TagModel {
+	return TagModel{
+		Attributes: TagAttributes{
+			CreatedOn: e.CreatedOn,
+			ID:        e.ID,
+			Name:      e.Name,
+		},
+	}
+}
@@ -0,0 +1,159 @@
+package example
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+)
+
+// ListTags returns the tags related to each of the given examples, keyed by example id (in tag name order).
+// Examples without tags are keyed with an empty slice, so that loaded relationships are distinguishable
+func (r *exampleRepository) ListTags(ctx context.Context, ids ...uuid.UUID) (map[uuid.UUID][]TagModel, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	// build sql query
+	query := func() string {
+		var (
+			statement = "SELECT j.%s, t.%s, t.%s, t.%s FROM %s j JOIN %s t ON t.%s = j.%s WHERE j.%s = ANY($1) ORDER BY t.%s, t.%s"
+			field     = tagEntity.Field
+			join      = tagEntity.Join
+		)
+
+		return fmt.Sprintf(statement,
+			join.ExampleID, field.ID, field.Name, field.CreatedOn,
+			tagEntity.JoinName, tagEntity.Name,
+			field.ID, join.TagID,
+			join.ExampleID,
+			field.Name, field.ID,
+		)
+	}()
+
+	rows, err := r.db.Read(ctx).Query(ctx, query, ids)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, repo.TranslateError(err, tagEntity.Name, uuid.Nil)
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID][]TagModel, len(ids))
+	for _, id := range ids {
+		result[id] = []TagModel{}
+	}
+
+	for rows.Next() {
+		var (
+			exampleID uuid.UUID
+			entity    TagEntity
+		)
+		if err := rows.Scan(&exampleID, &entity.ID, &entity.Name, &entity.CreatedOn); err != nil {
+			log.Error(err.Error())
+			return nil, repo.TranslateError(err, tagEntity.Name, uuid.Nil)
+		}
+		result[exampleID] = append(result[exampleID], marshalTag(entity))
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err.Error())
+		return nil, repo.TranslateError(err, tagEntity.Name, uuid.Nil)
+	}
+
+	return result, nil
+}
+
+// AddTags relates the given tags to an example, ignoring tags which are already related
+func (r *exampleRepository) AddTags(ctx context.Context, id uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.modifyTags(ctx, id, tagIDs, false, func(tx pgx.Tx) error {
+		return r.insertTags(ctx, tx, id, tagIDs)
+	})
+}
+
+// RemoveTags removes the given tags from an example, ignoring tags which are not related
+func (r *exampleRepository) RemoveTags(ctx context.Context, id uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.modifyTags(ctx, id, tagIDs, true, func(tx pgx.Tx) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND %s = ANY($2)",
+			tagEntity.JoinName, tagEntity.Join.ExampleID, tagEntity.Join.TagID,
+		)
+		_, err := tx.Exec(ctx, query, id, tagIDs)
+		return err
+	})
+}
+
+// ReplaceTags replaces all tags related to an example with the given tags (none to clear)
+func (r *exampleRepository) ReplaceTags(ctx context.Context, id uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.modifyTags(ctx, id, tagIDs, false, func(tx pgx.Tx) error {
+		query := fmt.Sprintf("DELETE FROM %s WHERE %s = $1 AND NOT (%s = ANY($2))",
+			tagEntity.JoinName, tagEntity.Join.ExampleID, tagEntity.Join.TagID,
+		)
+		if _, err := tx.Exec(ctx, query, id, tagIDs); err != nil {
+			return err
+		}
+		return r.insertTags(ctx, tx, id, tagIDs)
+	})
+}
+
+// modifyTags runs fn in a transaction, after locking the example and (unless skipTagCheck) verifying that
+// all given tags exist
+func (r *exampleRepository) modifyTags(ctx context.Context, id uuid.UUID, tagIDs []uuid.UUID, skipTagCheck bool, fn func(pgx.Tx) error) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	tagIDs = uniqueIDs(tagIDs)
+
+	err := pgx.BeginFunc(ctx, r.db.Primary(), func(tx pgx.Tx) error {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s = $1 FOR UPDATE", r.Entity.Field.ID, r.Entity.Name, r.Entity.Field.ID)
+		if err := tx.QueryRow(ctx, query, id).Scan(&id); err != nil {
+			return err
+		}
+
+		if !skipTagCheck && len(tagIDs) > 0 {
+			var count int
+			query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ANY($1)", tagEntity.Name, tagEntity.Field.ID)
+			if err := tx.QueryRow(ctx, query, tagIDs).Scan(&count); err != nil {
+				return err
+			}
+			if count != len(tagIDs) {
+				return cerror.NewNotFoundError(nil, fmt.Sprintf("unable to find one or more %s resources", tagEntity.Name))
+			}
+		}
+
+		return fn(tx)
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return repo.TranslateError(err, r.Entity.Name, id)
+	}
+
+	return nil
+}
+
+// insertTags relates the given (existing) tags to an example
+func (r *exampleRepository) insertTags(ctx context.Context, tx pgx.Tx, id uuid.UUID, tagIDs []uuid.UUID) error {
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s, %s) SELECT $1, unnest($2::uuid[]) ON CONFLICT DO NOTHING",
+		tagEntity.JoinName, tagEntity.Join.ExampleID, tagEntity.Join.TagID,
+	)
+	_, err := tx.Exec(ctx, query, id, tagIDs)
+
+	return err
+}
+
+// uniqueIDs returns the given ids without duplicates (never nil)
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -4,28 +4,43 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
 
 	"github.com/jasonsites/gosk/internal/app"
-	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/database"
 	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/jobs"
 	"github.com/jasonsites/gosk/internal/logger"
 	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
 )
 
+const (
+	// searchConfig defines the text search configuration of the example_entity search column
+	searchConfig = "english"
+	// searchHeadlineOptions defines the ts_headline options used for search result snippets
+	searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=24, MinWords=8"
+)
+
+// likeEscaper escapes LIKE pattern characters in user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
 // ExampleRepoConfig defines the input to NewExampleRepository
 type ExampleRepoConfig struct {
-	DBClient *pgxpool.Pool        `validate:"required"`
-	Logger   *logger.CustomLogger `validate:"required"`
+	DBRouter *database.Router `validate:"required"`
+	// Jobs defines the job queue of asynchronous imports
+	Jobs   *jobs.Queue          `validate:"required"`
+	Logger *logger.CustomLogger `validate:"required"`
 }
 
 // exampleRepository
 type exampleRepository struct {
 	Entity exampleEntityDefinition
-	db     *pgxpool.Pool
+	db     *database.Router
+	jobs   *jobs.Queue
 	logger *logger.CustomLogger
 }
 
@@ -37,7 +52,8 @@ func NewExampleRepository(c *ExampleRepoConfig) (*exampleRepository, error) {
 
 	repo := &exampleRepository{
 		Entity: exampleEntity,
-		db:     c.DBClient,
+		db:     c.DBRouter,
+		jobs:   c.Jobs,
 		logger: c.Logger,
 	}
 
@@ -101,7 +117,7 @@ func (r *exampleRepository) Create(ctx context.Context, data *ExampleDTORequest)
 
 	// create new entity for db row scan and execute query
 	entity := ExampleEntity{}
-	if err := r.db.QueryRow(
+	if err := r.db.Write(ctx).QueryRow(
 		ctx,
 		query,
 		requestData.Title,
@@ -118,7 +134,7 @@ func (r *exampleRepository) Create(ctx context.Context, data *ExampleDTORequest)
 		&entity.ModifiedOn,
 	); err != nil {
 		log.Error(err.Error())
-		return nil, err
+		return nil, repo.TranslateError(err, r.Entity.Name, uuid.Nil)
 	}
 
 	em := &ExampleEntityModel{
@@ -150,9 +166,9 @@ func (r *exampleRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	// create new entity for db row scan and execute query
 	entity := ExampleEntity{}
-	if err := r.db.QueryRow(ctx, query).Scan(&entity.ID); err != nil {
+	if err := r.db.Write(ctx).QueryRow(ctx, query).Scan(&entity.ID); err != nil {
 		log.Error(err.Error())
-		return err
+		return repo.TranslateError(err, r.Entity.Name, id)
 	}
 
 	return nil
@@ -187,7 +203,7 @@ func (r *exampleRepository) Detail(ctx context.Context, id uuid.UUID) (*ModelCon
 
 	// create new entity for db row scan and execute query
 	entity := ExampleEntity{}
-	if err := r.db.QueryRow(ctx, query).Scan(
+	if err := r.db.Read(ctx).QueryRow(ctx, query).Scan(
 		&entity.ID,
 		&entity.Title,
 		&entity.Description,
@@ -198,8 +214,7 @@ func (r *exampleRepository) Detail(ctx context.Context, id uuid.UUID) (*ModelCon
 		&entity.ModifiedOn,
 	); err != nil {
 		log.Error(err.Error())
-		err := cerror.NewNotFoundError(nil, fmt.Sprintf("unable to find %s with id '%s'", r.Entity.Name, id))
-		return nil, err
+		return nil, repo.TranslateError(err, r.Entity.Name, id)
 	}
 
 	em := &ExampleEntityModel{
@@ -219,49 +234,22 @@ func (r *exampleRepository) List(ctx context.Context, eqd ExampleQueryData) (*Mo
 	var (
 		limit  = *eqd.Page.Limit
 		offset = *eqd.Page.Offset
+		search = eqd.Search
 	)
 
-	// build sql query
-	query := func() string {
-		var (
-			statement = "SELECT %s FROM %s ORDER BY %s %s LIMIT %s OFFSET %s"
-			field     = r.Entity.Field
-			name      = r.Entity.Name
-			limit     = fmt.Sprint(limit)
-			offset    = fmt.Sprint(offset)
-		)
-
-		// Get the first sort pair from the query
-		sortPairs := eqd.Sort.GetSortPairs()
-		var orderField, orderDir string
-		if len(sortPairs) > 0 {
-			orderField = sortPairs[0].Field
-			orderDir = string(sortPairs[0].Order)
-		} else {
-			// Fallback to default
-			orderField = "modified_on"
-			orderDir = "desc"
-		}
-
-		returnFields := repo.BuildReturnFields(
-			field.ID,
-			field.Title,
-			field.Description,
-			field.Status,
-			field.CreatedContext,
-			field.CreatedOn,
-			field.ModifiedContext,
-			field.ModifiedOn,
-		)
+	// build sql from/where clauses (shared by list and total count queries) and query args
+	from, where, args := r.listClauses(eqd)
 
-		return fmt.Sprintf(statement, returnFields, name, orderField, orderDir, limit, offset)
-	}()
+	// build sql query
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s LIMIT $%d OFFSET $%d",
+		r.listFields(search), from, where, r.listOrder(eqd), len(args)+1, len(args)+2)
 
 	// execute query, returning rows
-	rows, err := r.db.Query(ctx, query)
+	db := r.db.Read(ctx)
+	rows, err := db.Query(ctx, query, append(args, limit, offset)...)
 	if err != nil {
 		log.Error(err.Error())
-		return nil, err
+		return nil, repo.TranslateError(err, r.Entity.Name, uuid.Nil)
 	}
 	defer rows.Close()
 
@@ -270,24 +258,10 @@ func (r *exampleRepository) List(ctx context.Context, eqd ExampleQueryData) (*Mo
 
 	// scan row data into new entities, appending to repo result
 	for rows.Next() {
-		entity := ExampleEntity{}
-
-		if err := rows.Scan(
-			&entity.ID,
-			&entity.Title,
-			&entity.Description,
-			&entity.Status,
-			&entity.CreatedContext,
-			&entity.CreatedOn,
-			&entity.ModifiedContext,
-			&entity.ModifiedOn,
-		); err != nil {
+		em, err := scanListRow(rows, search != nil)
+		if err != nil {
 			log.Error(err.Error())
-			return nil, err
-		}
-
-		em := &ExampleEntityModel{
-			Record: entity,
+			return nil, repo.TranslateError(err, r.Entity.Name, uuid.Nil)
 		}
 
 		ems = append(ems, em)
@@ -295,25 +269,27 @@ func (r *exampleRepository) List(ctx context.Context, eqd ExampleQueryData) (*Mo
 
 	if err := rows.Err(); err != nil {
 		log.Error(err.Error())
-		return nil, err
+		return nil, repo.TranslateError(err, r.Entity.Name, uuid.Nil)
 	}
 
 	// TODO: Investigate https://stackoverflow.com/questions/28888375/run-a-query-with-a-limit-offset-and-also-get-the-total-number-of-rows
 	// query for total count
 	var total int
-	totalQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s", r.Entity.Name)
-	if err := r.db.QueryRow(ctx, totalQuery).Scan(&total); err != nil {
+	totalQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s%s", from, where)
+	if err := db.QueryRow(ctx, totalQuery, args...).Scan(&total); err != nil {
 		log.Error(err.Error())
-		return nil, err
+		return nil, repo.TranslateError(err, r.Entity.Name, uuid.Nil)
 	}
 
 	gmd := ListQueryData{
+		Filter: eqd.Filter,
 		Page: repo.PageData{
 			Limit:  limit,
 			Offset: offset,
 			Total:  total,
 		},
-		Sort: ExampleSortMetadata(eqd.Sort.ToSortMetadata()),
+		Search: search,
+		Sort:   ExampleSortMetadata(eqd.Sort.ToSortMetadata()),
 	}
 
 	result := MarshalEntityModelList(ems, gmd)
@@ -321,6 +297,147 @@ func (r *exampleRepository) List(ctx context.Context, eqd ExampleQueryData) (*Mo
 	return result, nil
 }
 
+// Export streams all examples matching the query filter, search and sort (without paging) to fn, reading each row
+// from the connection as fn returns, so that the result is never buffered
+func (r *exampleRepository) Export(ctx context.Context, eqd ExampleQueryData, fn func(*ExampleModel) error) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := r.logger.CreateContextLogger(traceID)
+
+	from, where, args := r.listClauses(eqd)
+	query := fmt.Sprintf("SELECT %s FROM %s%s ORDER BY %s", r.listFields(eqd.Search), from, where, r.listOrder(eqd))
+
+	rows, err := r.db.Read(ctx).Query(ctx, query, args...)
+	if err != nil {
+		log.Error(err.Error())
+		return repo.TranslateError(err, r.Entity.Name, uuid.Nil)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		em, err := scanListRow(rows, eqd.Search != nil)
+		if err != nil {
+			log.Error(err.Error())
+			return repo.TranslateError(err, r.Entity.Name, uuid.Nil)
+		}
+
+		model := marshalEntity(em.Record)
+		model.Meta = marshalSearchMatch(em.Search)
+		if err := fn(model); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		log.Error(err.Error())
+		return repo.TranslateError(err, r.Entity.Name, uuid.Nil)
+	}
+
+	return nil
+}
+
+// listClauses returns the sql from/where clauses of list and export queries (shared by total count queries), with
+// their query args
+func (r *exampleRepository) listClauses(eqd ExampleQueryData) (string, string, []any) {
+	var (
+		field      = r.Entity.Field
+		from       = r.Entity.Name
+		args       []any
+		conditions []string
+	)
+
+	if eqd.Search != nil {
+		args = append(args, *eqd.Search)
+		from = fmt.Sprintf("%s, websearch_to_tsquery('%s', $%d) AS search_query", from, searchConfig, len(args))
+		conditions = append(conditions, fmt.Sprintf("%s @@ search_query", field.Search))
+	}
+	if eqd.Filter != nil && eqd.Filter.Title != nil {
+		args = append(args, "%"+likeEscaper.Replace(*eqd.Filter.Title)+"%")
+		conditions = append(conditions, fmt.Sprintf("%s ILIKE $%d", field.Title, len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	return from, where, args
+}
+
+// listFields returns the sql return fields of list and export queries, with the search rank and highlighted
+// snippets of search queries
+func (r *exampleRepository) listFields(search *string) string {
+	field := r.Entity.Field
+
+	fields := []string{
+		field.ID,
+		field.Title,
+		field.Description,
+		field.Status,
+		field.CreatedContext,
+		field.CreatedOn,
+		field.ModifiedContext,
+		field.ModifiedOn,
+	}
+	if search != nil {
+		fields = append(fields,
+			fmt.Sprintf("ts_rank(%s, search_query) AS rank", field.Search),
+			fmt.Sprintf("ts_headline('%s', %s, search_query, '%s')", searchConfig, field.Title, searchHeadlineOptions),
+			fmt.Sprintf("ts_headline('%s', coalesce(%s, ''), search_query, '%s')", searchConfig, field.Description, searchHeadlineOptions),
+		)
+	}
+
+	return repo.BuildReturnFields(fields...)
+}
+
+// listOrder returns the sql order by clause of list and export queries
+func (r *exampleRepository) listOrder(eqd ExampleQueryData) string {
+	var order []string
+
+	for _, pair := range eqd.Sort.GetSortPairs() {
+		if pair.Field == "relevance" {
+			// relevance is only defined for search queries (rejected by ParseQuery otherwise)
+			order = append(order, fmt.Sprintf("rank %s", pair.Order))
+			continue
+		}
+		order = append(order, fmt.Sprintf("%s %s", pair.Field, pair.Order))
+	}
+	if len(order) == 0 {
+		// Fallback to default
+		order = append(order, fmt.Sprintf("%s desc", r.Entity.Field.ModifiedOn))
+	}
+	// order by id last, so that paging is stable
+	order = append(order, r.Entity.Field.ID)
+
+	return strings.Join(order, ", ")
+}
+
+// scanListRow scans a single list or export query row, with the search match of search queries
+func scanListRow(rows pgx.Rows, search bool) (*ExampleEntityModel, error) {
+	entity := ExampleEntity{}
+	dest := []any{
+		&entity.ID,
+		&entity.Title,
+		&entity.Description,
+		&entity.Status,
+		&entity.CreatedContext,
+		&entity.CreatedOn,
+		&entity.ModifiedContext,
+		&entity.ModifiedOn,
+	}
+
+	var match *ExampleSearchMatch
+	if search {
+		match = &ExampleSearchMatch{}
+		dest = append(dest, &match.Rank, &match.Title, &match.Description)
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	return &ExampleEntityModel{Record: entity, Search: match}, nil
+}
+
 // Update
 func (r *exampleRepository) Update(ctx context.Context, data *ExampleDTORequest, id uuid.UUID) (*ModelContainer, error) {
 	traceID := trace.GetTraceIDFromContext(ctx)
@@ -380,7 +497,7 @@ func (r *exampleRepository) Update(ctx context.Context, data *ExampleDTORequest,
 
 	// create new entity for db row scan and execute query
 	entity := ExampleEntity{}
-	if err := r.db.QueryRow(
+	if err := r.db.Write(ctx).QueryRow(
 		ctx,
 		query,
 		requestData.Title,
@@ -398,7 +515,7 @@ func (r *exampleRepository) Update(ctx context.Context, data *ExampleDTORequest,
 		&entity.ModifiedOn,
 	); err != nil {
 		log.Error(err.Error())
-		return nil, err
+		return nil, repo.TranslateError(err, r.Entity.Name, id)
 	}
 
 	em := &ExampleEntityModel{
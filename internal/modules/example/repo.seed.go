@@ -0,0 +1,52 @@
+package example
+
+import (
+	fake "github.com/brianvoe/gofakeit/v6"
+	"github.com/jasonsites/gosk/internal/database"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+)
+
+// ExampleSeedTable returns the example entity table definition for database seeding
+func ExampleSeedTable() database.SeedTable {
+	field := exampleEntity.Field
+
+	return database.SeedTable{
+		Generate: func(f *fake.Faker) database.SeedRecord {
+			return database.SeedRecord{
+				field.ID:             f.UUID(),
+				field.Title:          f.JobTitle(),
+				field.Description:    f.Sentence(4),
+				field.Status:         string(repo.RecordStatusActive),
+				field.CreatedContext: map[string]any{"user_id": "seed"},
+			}
+		},
+		Key:  []string{field.ID},
+		Name: exampleEntity.Name,
+	}
+}
+
+// TagSeedTable returns the tag entity table definition for database seeding
+func TagSeedTable() database.SeedTable {
+	field := tagEntity.Field
+
+	return database.SeedTable{
+		Generate: func(f *fake.Faker) database.SeedRecord {
+			return database.SeedRecord{
+				field.ID:   f.UUID(),
+				field.Name: f.Noun() + "-" + f.DigitN(4),
+			}
+		},
+		Key:  []string{field.ID},
+		Name: tagEntity.Name,
+	}
+}
+
+// ExampleTagSeedTable returns the example/tag join table definition for database seeding (records only)
+func ExampleTagSeedTable() database.SeedTable {
+	join := tagEntity.Join
+
+	return database.SeedTable{
+		Key:  []string{join.ExampleID, join.TagID},
+		Name: tagEntity.JoinName,
+	}
+}
@@ -0,0 +1,39 @@
+package example
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jasonsites/gosk/internal/database"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/logger"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+	"github.com/jasonsites/gosk/internal/scheduler"
+)
+
+// ExamplePurgeTask returns a scheduled task permanently deleting examples which were soft-deleted
+// (status 'deleted') before the retention period
+func ExamplePurgeTask(db *database.Router, log *logger.CustomLogger, schedule string, retention time.Duration) scheduler.Task {
+	return scheduler.Task{
+		Name:     "example.purge",
+		Schedule: schedule,
+		Run: func(ctx context.Context) error {
+			var (
+				statement = "DELETE FROM %s WHERE %s = $1 AND %s < now() - $2::interval"
+				field     = exampleEntity.Field
+				query     = fmt.Sprintf(statement, exampleEntity.Name, field.Status, field.ModifiedOn)
+			)
+
+			tag, err := db.Write(ctx).Exec(ctx, query, repo.RecordStatusDeleted, retention)
+			if err != nil {
+				return err
+			}
+
+			traceID := trace.GetTraceIDFromContext(ctx)
+			log.CreateContextLogger(traceID).Info(fmt.Sprintf("purged %d deleted examples", tag.RowsAffected()))
+
+			return nil
+		},
+	}
+}
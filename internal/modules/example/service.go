@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jasonsites/gosk/internal/app"
 	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/jobs"
 	"github.com/jasonsites/gosk/internal/logger"
 )
 
@@ -15,8 +16,20 @@ type ExampleRepository interface {
 	Create(context.Context, *ExampleDTORequest) (*ModelContainer, error)
 	Delete(context.Context, uuid.UUID) error
 	Detail(context.Context, uuid.UUID) (*ModelContainer, error)
+	Export(context.Context, ExampleQueryData, func(*ExampleModel) error) error
 	List(context.Context, ExampleQueryData) (*ModelContainer, error)
 	Update(context.Context, *ExampleDTORequest, uuid.UUID) (*ModelContainer, error)
+
+	CreateImport(context.Context, *ImportUpload) (*jobs.Job, error)
+	DeleteImportUpload(context.Context, uuid.UUID) error
+	Import(context.Context, bool, func(insert func([]*ExampleDTORequest) error) (bool, error)) error
+	ImportJob(context.Context, uuid.UUID) (*jobs.Job, error)
+	ImportUpload(context.Context, uuid.UUID) (*ImportUpload, error)
+
+	AddTags(context.Context, uuid.UUID, []uuid.UUID) error
+	ListTags(context.Context, ...uuid.UUID) (map[uuid.UUID][]TagModel, error)
+	RemoveTags(context.Context, uuid.UUID, []uuid.UUID) error
+	ReplaceTags(context.Context, uuid.UUID, []uuid.UUID) error
 }
 
 // ExampleServiceConfig defines the input to NewExampleService
@@ -93,6 +106,19 @@ func (s *exampleService) Detail(ctx context.Context, id uuid.UUID) (*ModelContai
 	return model, nil
 }
 
+// Export
+func (s *exampleService) Export(ctx context.Context, q ExampleQueryData, fn func(*ExampleModel) error) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	if err := s.repo.Export(ctx, q, fn); err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
 // List
 func (s *exampleService) List(ctx context.Context, q ExampleQueryData) (*ModelContainer, error) {
 	traceID := trace.GetTraceIDFromContext(ctx)
@@ -127,3 +153,70 @@ func (s *exampleService) Update(ctx context.Context, data any, id uuid.UUID) (*M
 
 	return model, nil
 }
+
+// IncludeTags loads the related tags of all examples in the given model
+func (s *exampleService) IncludeTags(ctx context.Context, model *ModelContainer) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	ids := make([]uuid.UUID, 0, len(model.Data))
+	for _, domo := range model.Data {
+		ids = append(ids, domo.Attributes.ID)
+	}
+
+	tags, err := s.repo.ListTags(ctx, ids...)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	for i := range model.Data {
+		model.Data[i].Tags = tags[model.Data[i].Attributes.ID]
+	}
+
+	return nil
+}
+
+// Tags returns the tags related to an example
+func (s *exampleService) Tags(ctx context.Context, id uuid.UUID) ([]TagModel, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	// ensure the example exists (not found otherwise)
+	if _, err := s.repo.Detail(ctx, id); err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	tags, err := s.repo.ListTags(ctx, id)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	return tags[id], nil
+}
+
+// UpdateTags adds, removes or replaces (per op) the tags related to an example
+func (s *exampleService) UpdateTags(ctx context.Context, op RelationshipOp, id uuid.UUID, tagIDs []uuid.UUID) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	var err error
+	switch op {
+	case RelationshipAdd:
+		err = s.repo.AddTags(ctx, id, tagIDs)
+	case RelationshipRemove:
+		err = s.repo.RemoveTags(ctx, id, tagIDs)
+	case RelationshipReplace:
+		err = s.repo.ReplaceTags(ctx, id, tagIDs)
+	default:
+		err = fmt.Errorf("unsupported relationship operation '%s'", op)
+	}
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	return nil
+}
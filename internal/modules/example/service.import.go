@@ -0,0 +1,261 @@
+package example
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/export"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/jobs"
+	"github.com/jasonsites/gosk/internal/validate"
+)
+
+// importColumns defines the CSV import columns (the ExampleDTORequest attributes)
+var importColumns = []string{"title", "description"}
+
+// Import validates and imports the rows of a CSV or NDJSON file in batches, returning the import report. Atomic
+// imports with invalid rows are rolled back (without imported rows), and partial imports skip invalid rows
+func (s *exampleService) Import(ctx context.Context, body io.Reader, mediaType string, mode ImportMode) (*ImportReport, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	report := &ImportReport{Mode: mode, Errors: []ImportRowError{}}
+	atomic := mode == ImportModeAtomic
+
+	err := s.repo.Import(ctx, atomic, func(insert func([]*ExampleDTORequest) error) (bool, error) {
+		batch := make([]*ExampleDTORequest, 0, importBatchSize)
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := insert(batch); err != nil {
+				return err
+			}
+			report.Imported += len(batch)
+			batch = batch[:0]
+			return nil
+		}
+
+		err := readImportRows(body, mediaType, func(row int, dto *ExampleDTORequest, errs cerror.FieldErrors) error {
+			if row > 0 {
+				report.Total++
+			}
+			if len(errs) > 0 {
+				report.addRowErrors(row, errs)
+				return nil
+			}
+			// rows of atomic imports are no longer inserted once any row is invalid, but are still validated
+			if atomic && report.Failed > 0 {
+				return nil
+			}
+
+			batch = append(batch, dto)
+			if len(batch) == importBatchSize {
+				return flush()
+			}
+			return nil
+		})
+		if err != nil {
+			return false, err
+		}
+
+		if atomic && report.Failed > 0 {
+			return false, nil
+		}
+		return true, flush()
+	})
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	if atomic && report.Failed > 0 {
+		report.Imported = 0
+	}
+
+	return report, nil
+}
+
+// CreateImport stores an uploaded import file for asynchronous import by an import job, returning the import
+func (s *exampleService) CreateImport(ctx context.Context, upload *ImportUpload) (*ImportModel, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	job, err := s.repo.CreateImport(ctx, upload)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	return ImportModelFromJob(job)
+}
+
+// ImportStatus returns an asynchronous import (by import job id)
+func (s *exampleService) ImportStatus(ctx context.Context, id uuid.UUID) (*ImportModel, error) {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	job, err := s.repo.ImportJob(ctx, id)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	model, err := ImportModelFromJob(job)
+	if err != nil {
+		log.Error(err.Error())
+		return nil, err
+	}
+
+	return model, nil
+}
+
+// RunImport imports the uploaded file of an import job, recording the import report as the job result, and then
+// deletes the uploaded file
+func (s *exampleService) RunImport(ctx context.Context, id uuid.UUID) error {
+	traceID := trace.GetTraceIDFromContext(ctx)
+	log := s.logger.CreateContextLogger(traceID)
+
+	upload, err := s.repo.ImportUpload(ctx, id)
+	if err != nil {
+		log.Error(err.Error())
+		return err
+	}
+
+	report, err := s.Import(ctx, upload.Body, upload.MediaType, upload.Mode)
+	if err != nil {
+		return err
+	}
+	jobs.SetResult(ctx, report)
+
+	return s.repo.DeleteImportUpload(ctx, id)
+}
+
+// ImportJobHandler returns the job handler of asynchronous imports (ImportJobKind)
+func ImportJobHandler(s ExampleService) jobs.Handler {
+	return func(ctx context.Context, job *jobs.Job) error {
+		return s.RunImport(ctx, job.ID)
+	}
+}
+
+// readImportRows reads the rows of a CSV or NDJSON import body, calling fn with the (1-based) row number and the
+// decoded row, or with the errors of an invalid row (validated with the ExampleDTORequest rules). Errors of the CSV
+// header row are reported as row 0, and reading stops at malformed CSV. Only read errors and errors returned by fn
+// are returned
+func readImportRows(body io.Reader, mediaType string, fn func(int, *ExampleDTORequest, cerror.FieldErrors) error) error {
+	switch mediaType {
+	case export.MediaTypeCSV:
+		return readCSVRows(body, fn)
+	case export.MediaTypeNDJSON:
+		return readNDJSONRows(body, fn)
+	default:
+		return fmt.Errorf("unsupported import media type '%s'", mediaType)
+	}
+}
+
+// readCSVRows reads the rows of a CSV import body, with a header row of importColumns (in any order)
+func readCSVRows(body io.Reader, fn func(int, *ExampleDTORequest, cerror.FieldErrors) error) error {
+	r := csv.NewReader(body)
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil
+	}
+	if err != nil {
+		return csvRowError(err, 0, fn)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, column := range header {
+		if !slices.Contains(importColumns, column) {
+			detail := fmt.Sprintf("unsupported column '%s' (columns: title, description)", column)
+			return fn(0, nil, cerror.FieldErrors{{Code: "validation_unsupported_column", Detail: detail}})
+		}
+		index[column] = i
+	}
+	if _, ok := index["title"]; !ok {
+		return fn(0, nil, cerror.FieldErrors{{Code: "validation_required", Detail: "missing required column 'title'"}})
+	}
+
+	for row := 1; ; row++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			if errors.As(err, &perr) && errors.Is(perr.Err, csv.ErrFieldCount) {
+				detail := fmt.Sprintf("expected %d fields, actual %d", len(header), len(record))
+				if err := fn(row, nil, cerror.FieldErrors{{Code: "validation_field_count", Detail: detail}}); err != nil {
+					return err
+				}
+				continue
+			}
+			return csvRowError(err, row, fn)
+		}
+
+		dto := &ExampleDTORequest{Title: record[index["title"]]}
+		if i, ok := index["description"]; ok && record[i] != "" {
+			dto.Description = &record[i]
+		}
+		if err := validRow(row, dto, fn); err != nil {
+			return err
+		}
+	}
+}
+
+// csvRowError reports a malformed CSV row (after which reading stops), returning read errors
+func csvRowError(err error, row int, fn func(int, *ExampleDTORequest, cerror.FieldErrors) error) error {
+	var perr *csv.ParseError
+	if !errors.As(err, &perr) {
+		return err
+	}
+	return fn(row, nil, cerror.FieldErrors{{Code: "validation_malformed_csv", Detail: perr.Error()}})
+}
+
+// readNDJSONRows reads the rows of an NDJSON import body, skipping blank lines
+func readNDJSONRows(body io.Reader, fn func(int, *ExampleDTORequest, cerror.FieldErrors) error) error {
+	r := bufio.NewReader(body)
+
+	for row := 1; ; {
+		line, err := r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			dto := &ExampleDTORequest{}
+			dec := json.NewDecoder(bytes.NewReader(line))
+			dec.DisallowUnknownFields()
+			if derr := dec.Decode(dto); derr != nil {
+				if err := fn(row, nil, cerror.FieldErrors{{Code: "validation_malformed_json", Detail: derr.Error()}}); err != nil {
+					return err
+				}
+			} else if err := validRow(row, dto, fn); err != nil {
+				return err
+			}
+			row++
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// validRow validates a decoded row, calling fn with the row or its field errors
+func validRow(row int, dto *ExampleDTORequest, fn func(int, *ExampleDTORequest, cerror.FieldErrors) error) error {
+	if errs := validate.Struct(dto, validate.Parameter); len(errs) > 0 {
+		return fn(row, nil, errs)
+	}
+	return fn(row, dto, nil)
+}
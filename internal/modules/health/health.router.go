@@ -5,18 +5,99 @@ import (
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/jasonsites/gosk/internal/cerror"
 	"github.com/jasonsites/gosk/internal/http/jsonapi"
 	"github.com/jasonsites/gosk/internal/http/jsonio"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/http/openapi"
 )
 
+// StatusProvider provides the current status of an app component for inclusion in healthcheck metadata
+type StatusProvider func() any
+
 // HealthRouter implements a router for healthcheck
-func HealthRouter(r *chi.Mux, ns string) {
+func HealthRouter(r *chi.Mux, ns string, providers map[string]StatusProvider) {
 	prefix := fmt.Sprintf("/%s/health", ns)
 
 	status := func(w http.ResponseWriter, r *http.Request) {
-		data := jsonapi.Envelope{"meta": jsonapi.Envelope{"status": "healthy"}}
+		meta := jsonapi.Envelope{"status": "healthy"}
+		for key, provider := range providers {
+			meta[key] = provider()
+		}
+
+		data := jsonapi.Envelope{"meta": meta}
 		jsonio.EncodeResponse(w, r, http.StatusOK, data)
 	}
 
 	r.Get(prefix, status)
 }
+
+// AdminRouter implements a router for admin status, with component details (e.g. errors and hosts) that are not
+// exposed by the healthcheck, served to clients with a verified admin identity (see mw.ClientIdentity)
+func AdminRouter(r *chi.Mux, ns string, providers map[string]StatusProvider) {
+	prefix := fmt.Sprintf("/%s/admin/status", ns)
+
+	status := func(w http.ResponseWriter, r *http.Request) {
+		id, ok := mw.GetClientIdentity(r.Context())
+		if !ok {
+			jsonio.EncodeError(w, r, cerror.NewUnauthorizedError(nil, "authentication required"))
+			return
+		}
+		if !id.Admin {
+			jsonio.EncodeError(w, r, cerror.NewForbiddenError(nil, "admin identity required"))
+			return
+		}
+
+		meta := jsonapi.Envelope{}
+		for key, provider := range providers {
+			meta[key] = provider()
+		}
+
+		data := jsonapi.Envelope{"meta": meta}
+		jsonio.EncodeResponse(w, r, http.StatusOK, data)
+	}
+
+	r.Get(prefix, status)
+}
+
+// HealthOperations describes the routes registered by HealthRouter
+func HealthOperations(ns string) []openapi.Operation {
+	meta := &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"status": {Type: "string", Enum: []any{"healthy"}},
+		},
+		AdditionalProperties: &openapi.Schema{},
+	}
+
+	return []openapi.Operation{
+		{
+			Method:  http.MethodGet,
+			Pattern: fmt.Sprintf("/%s/health", ns),
+			ID:      "health.status",
+			Summary: "Get the app health status, with component status metadata",
+			Tags:    []string{"health"},
+			Responses: []openapi.Response{{
+				Status: http.StatusOK,
+				Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{"meta": meta}},
+			}},
+		},
+		{
+			Method:  http.MethodGet,
+			Pattern: fmt.Sprintf("/%s/admin/status", ns),
+			ID:      "admin.status",
+			Summary: "Get the detailed component status metadata (admin only)",
+			Tags:    []string{"health"},
+			Responses: []openapi.Response{
+				{
+					Status: http.StatusOK,
+					Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{
+						"meta": {Type: "object", AdditionalProperties: &openapi.Schema{}},
+					}},
+				},
+				openapi.ErrorResponse(http.StatusUnauthorized),
+				openapi.ErrorResponse(http.StatusForbidden),
+			},
+		},
+	}
+}
@@ -3,18 +3,30 @@ package resolver
 import (
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
+	"slices"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jasonsites/gosk/config"
+	dbfs "github.com/jasonsites/gosk/database"
 	app "github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/database"
 	"github.com/jasonsites/gosk/internal/http/httpserver"
+	"github.com/jasonsites/gosk/internal/http/jsonio"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
 	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/modules/example"
+	"github.com/jasonsites/gosk/internal/modules/health"
 )
 
 // Config provides a singleton config.Configuration instance
 func (r *Resolver) Config() *config.Configuration {
-	if r.config == nil {
+	r.configMutex.RLock()
+	conf := r.config
+	r.configMutex.RUnlock()
+
+	if conf == nil {
 		conf, err := config.LoadConfiguration()
 		if err != nil {
 			err = fmt.Errorf("configuration load error: %w", err)
@@ -22,10 +34,67 @@ func (r *Resolver) Config() *config.Configuration {
 			panic(err)
 		}
 
+		r.configMutex.Lock()
 		r.config = conf
+		r.configMutex.Unlock()
+
+		r.initConfigStatus(conf)
+
+		return conf
 	}
 
-	return r.config
+	return conf
+}
+
+// CORS provides a singleton middleware.CORS instance
+func (r *Resolver) CORS() *mw.CORS {
+	if r.cors == nil {
+		c := r.Config()
+
+		r.cors = mw.NewCORS(c.HTTP.CORS.AllowedOrigins)
+
+		r.SubscribeConfig("cors", func(prev, next *config.Configuration) (func(), error) {
+			return func() {
+				if !slices.Equal(prev.HTTP.CORS.AllowedOrigins, next.HTTP.CORS.AllowedOrigins) {
+					r.cors.SetAllowedOrigins(next.HTTP.CORS.AllowedOrigins)
+				}
+			}, nil
+		})
+	}
+
+	return r.cors
+}
+
+// DatabaseRouter provides a singleton database.Router instance, routing reads to healthy replicas
+func (r *Resolver) DatabaseRouter() *database.Router {
+	if r.databaseRouter == nil {
+		c := r.Config()
+
+		log := r.Log().With(slog.String("tags", "database"))
+		cLogger := &logger.CustomLogger{
+			Level: c.Logger.Level,
+			Log:   log,
+		}
+
+		routerConfig := &database.RouterConfig{
+			HealthCheckPeriod: c.Postgres.Pool.HealthCheckPeriod,
+			Logger:            cLogger,
+			Primary:           r.PostgreSQLClient(),
+			Replicas:          r.PostgreSQLReplicaClients(),
+		}
+
+		router, err := database.NewRouter(routerConfig)
+		if err != nil {
+			err = fmt.Errorf("database router load error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+		go router.Monitor(r.appContext)
+
+		r.databaseRouter = router
+	}
+
+	return r.databaseRouter
 }
 
 // HTTPServer provides a singleton httpserver.Server instance
@@ -41,9 +110,42 @@ func (r *Resolver) HTTPServer() *httpserver.Server {
 
 		controllers := &httpserver.ControllerRegistry{
 			ExampleController: r.ExampleController(),
+			// gosk:controllers (generated module controllers are added above)
 		}
 		routerConfig := &httpserver.RouterConfig{
-			Namespace: c.HTTP.Router.Namespace,
+			Authenticator:   r.authenticator,
+			BodyLimit:       c.HTTP.Router.BodyLimit.Default,
+			BodyLimitRoutes: bodyLimitRoutes(c),
+			CORS:            r.CORS(),
+			Health: map[string]health.StatusProvider{
+				"config":   func() any { return r.ConfigStatus() },
+				"recovery": func() any { return r.Recoverer().Status() },
+				"replicas": func() any { return r.DatabaseRouter().Status() },
+			},
+			ErrorAbout:        c.HTTP.Router.ErrorAbout,
+			ErrorFormat:       jsonio.ErrorFormat(c.HTTP.Router.ErrorFormat),
+			JSONAPIExtensions: c.HTTP.Router.JSONAPI.Extensions,
+			JSONAPIProfiles:   c.HTTP.Router.JSONAPI.Profiles,
+			Namespace:         c.HTTP.Router.Namespace,
+			Recoverer:         r.Recoverer(),
+			TrustedProxies:    c.HTTP.Server.TrustedProxies,
+		}
+		if c.HTTP.Router.OpenAPI.Enabled {
+			routerConfig.Docs = &httpserver.DocsConfig{
+				Title:   c.App.Metadata.Name,
+				Version: c.App.Metadata.Version,
+				Viewer:  c.HTTP.Router.OpenAPI.Viewer,
+
+				ViewerIntegrity: c.HTTP.Router.OpenAPI.ViewerIntegrity,
+				ViewerScript:    c.HTTP.Router.OpenAPI.ViewerScript,
+			}
+		}
+		if r.scheduler != nil {
+			routerConfig.Admin = map[string]health.StatusProvider{"tasks": r.adminTaskStatus}
+			routerConfig.Health["tasks"] = r.taskStatus
+		}
+		if c.HTTP.RateLimit.Enabled {
+			routerConfig.RateLimiter = r.RateLimiter()
 		}
 		serverConfig := &httpserver.ServerConfig{
 			Controllers:  controllers,
@@ -71,9 +173,15 @@ func (r *Resolver) Log() *slog.Logger {
 	if r.log == nil {
 		c := r.Config()
 
+		r.logLevel = new(slog.LevelVar)
+		r.logLevel.Set(logLevel(c.Logger.Level))
+
 		var handler slog.Handler
+		r.redactor = logger.NewRedactor(redactionPolicy(c))
+
 		opts := &slog.HandlerOptions{
-			Level: logLevel(c.Logger.Level),
+			Level:       r.logLevel,
+			ReplaceAttr: r.redactor.ReplaceAttr,
 		}
 		if c.Logger.Verbose {
 			opts.AddSource = true
@@ -95,6 +203,19 @@ func (r *Resolver) Log() *slog.Logger {
 		slog.SetDefault(logger)
 
 		r.log = logger
+
+		r.SubscribeConfig("logger", func(prev, next *config.Configuration) (func(), error) {
+			policy := redactionPolicy(next)
+			return func() {
+				if prev.Logger.Level != next.Logger.Level {
+					r.logLevel.Set(logLevel(next.Logger.Level))
+				}
+				r.redactor.SetPolicy(policy)
+				if prev.Logger.Format != next.Logger.Format || prev.Logger.Verbose != next.Logger.Verbose {
+					slog.Warn("logger format and verbosity changes require restart")
+				}
+			}, nil
+		})
 	}
 
 	return r.log
@@ -115,16 +236,54 @@ func (r *Resolver) Metadata() *app.Metadata {
 	return r.metadata
 }
 
+// Migrator provides a singleton database.Migrator instance for the embedded migrations
+func (r *Resolver) Migrator() *database.Migrator {
+	if r.migrator == nil {
+		c := r.Config()
+
+		log := r.Log().With(slog.String("tags", "database,migrate"))
+		cLogger := &logger.CustomLogger{
+			Level: c.Logger.Level,
+			Log:   log,
+		}
+
+		migratorConfig := &database.MigratorConfig{
+			DBClient: r.PostgreSQLClient(),
+			FS:       dbfs.Migrations,
+			Logger:   cLogger,
+			Path:     "migrations",
+		}
+
+		migrator, err := database.NewMigrator(migratorConfig)
+		if err != nil {
+			err = fmt.Errorf("migrator load error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+
+		r.migrator = migrator
+	}
+
+	return r.migrator
+}
+
 // PostgreSQLClient provides a singleton postgres pgxpool.Pool instance
 func (r *Resolver) PostgreSQLClient() *pgxpool.Pool {
 	if r.postgreSQLClient == nil {
-		if err := app.Validator.Validate.StructPartial(r.config, "Postgres"); err != nil {
+		if err := app.Validator.Validate.StructPartial(r.Config(), "Postgres"); err != nil {
 			err = fmt.Errorf("invalid postgres config: %w", err)
 			slog.Error(err.Error())
 			panic(err)
 		}
 
-		client, err := pgxpool.New(r.appContext, postgresDSN(r.config.Postgres))
+		poolConfig, err := postgresConfig(r.Config().Postgres, r.Metadata().Name)
+		if err != nil {
+			err = fmt.Errorf("postgres client config error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+
+		client, err := pgxpool.NewWithConfig(r.appContext, poolConfig)
 		if err != nil {
 			err = fmt.Errorf("postgres client load error: %w", err)
 			slog.Error(err.Error())
@@ -136,3 +295,169 @@ func (r *Resolver) PostgreSQLClient() *pgxpool.Pool {
 
 	return r.postgreSQLClient
 }
+
+// RateLimiter provides a singleton middleware.RateLimiter instance
+func (r *Resolver) RateLimiter() *mw.RateLimiter {
+	if r.rateLimiter == nil {
+		c := r.Config()
+
+		log := r.Log().With(slog.String("tags", "http,ratelimit"))
+		cLogger := &logger.CustomLogger{
+			Level: c.Logger.Level,
+			Log:   log,
+		}
+
+		ns := c.HTTP.Router.Namespace
+		rlConfig := &mw.RateLimiterConfig{
+			Default: rateLimitQuota(c.HTTP.RateLimit.Default.Limit, c.HTTP.RateLimit.Default.Period),
+			Logger:  cLogger,
+			Next: func(req *http.Request) bool {
+				return req.URL.Path == fmt.Sprintf("/%s/health", ns)
+			},
+			Routes: rateLimitRoutes(c.HTTP.RateLimit.Routes),
+			Store:  r.RateLimitStore(),
+		}
+
+		limiter, err := mw.NewRateLimiter(rlConfig)
+		if err != nil {
+			err = fmt.Errorf("rate limiter load error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+
+		r.rateLimiter = limiter
+
+		r.SubscribeConfig("ratelimit", func(prev, next *config.Configuration) (func(), error) {
+			rl := next.HTTP.RateLimit
+			def, routes := rateLimitQuota(rl.Default.Limit, rl.Default.Period), rateLimitRoutes(rl.Routes)
+			return func() {
+				r.rateLimiter.SetQuotas(def, routes)
+				if prev.HTTP.RateLimit.Enabled != rl.Enabled || prev.HTTP.RateLimit.Store != rl.Store {
+					slog.Warn("rate limit enabled and store changes require restart")
+				}
+			}, nil
+		})
+	}
+
+	return r.rateLimiter
+}
+
+// RateLimitStore provides a singleton middleware.RateLimitStore instance
+func (r *Resolver) RateLimitStore() mw.RateLimitStore {
+	if r.rateLimitStore == nil {
+		c := r.Config()
+
+		switch c.HTTP.RateLimit.Store {
+		case "postgres":
+			storeConfig := &mw.PostgresRateLimitStoreConfig{
+				DBClient: r.PostgreSQLClient(),
+			}
+			store, err := mw.NewPostgresRateLimitStore(storeConfig)
+			if err != nil {
+				err = fmt.Errorf("rate limit store load error: %w", err)
+				slog.Error(err.Error())
+				panic(err)
+			}
+			r.rateLimitStore = store
+		default:
+			r.rateLimitStore = mw.NewMemoryRateLimitStore()
+		}
+	}
+
+	return r.rateLimitStore
+}
+
+// Recoverer provides a singleton middleware.Recoverer instance
+func (r *Resolver) Recoverer() *mw.Recoverer {
+	if r.recoverer == nil {
+		c := r.Config()
+
+		log := r.Log().With(slog.String("tags", "http,recovery"))
+		cLogger := &logger.CustomLogger{
+			Level: c.Logger.Level,
+			Log:   log,
+		}
+
+		recConfig := &mw.RecovererConfig{
+			Logger:   cLogger,
+			Reporter: r.errorReporter,
+		}
+
+		recoverer, err := mw.NewRecoverer(recConfig)
+		if err != nil {
+			err = fmt.Errorf("recoverer load error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+
+		r.recoverer = recoverer
+	}
+
+	return r.recoverer
+}
+
+// Seeder provides a singleton database.Seeder instance, registering all seedable module tables
+func (r *Resolver) Seeder() *database.Seeder {
+	if r.seeder == nil {
+		c := r.Config()
+
+		log := r.Log().With(slog.String("tags", "database,seed"))
+		cLogger := &logger.CustomLogger{
+			Level: c.Logger.Level,
+			Log:   log,
+		}
+
+		seederConfig := &database.SeederConfig{
+			DBClient: r.PostgreSQLClient(),
+			FS:       dbfs.Seeds,
+			Logger:   cLogger,
+			Path:     "seeds",
+			Tables: []database.SeedTable{
+				example.ExampleSeedTable(),
+				example.TagSeedTable(),
+				example.ExampleTagSeedTable(),
+			},
+		}
+
+		seeder, err := database.NewSeeder(seederConfig)
+		if err != nil {
+			err = fmt.Errorf("seeder load error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+
+		r.seeder = seeder
+	}
+
+	return r.seeder
+}
+
+// PostgreSQLReplicaClients provides singleton postgres pgxpool.Pool instances for each configured read replica
+func (r *Resolver) PostgreSQLReplicaClients() []*pgxpool.Pool {
+	if r.postgreSQLReplicas == nil {
+		c := r.Config()
+
+		replicas := make([]*pgxpool.Pool, 0, len(c.Postgres.Replicas))
+		for _, dsn := range c.Postgres.Replicas {
+			poolConfig, err := postgresReplicaConfig(c.Postgres, dsn.Reveal(), r.Metadata().Name)
+			if err != nil {
+				err = fmt.Errorf("postgres replica client config error: %w", err)
+				slog.Error(err.Error())
+				panic(err)
+			}
+
+			client, err := pgxpool.NewWithConfig(r.appContext, poolConfig)
+			if err != nil {
+				err = fmt.Errorf("postgres replica client load error: %w", err)
+				slog.Error(err.Error())
+				panic(err)
+			}
+
+			replicas = append(replicas, client)
+		}
+
+		r.postgreSQLReplicas = replicas
+	}
+
+	return r.postgreSQLReplicas
+}
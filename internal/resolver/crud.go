@@ -0,0 +1,90 @@
+package resolver
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/logger"
+	crud "github.com/jasonsites/gosk/internal/modules/common/crud"
+)
+
+// crudModuleConfig defines the input to newCRUDController
+type crudModuleConfig[T, D any] struct {
+	// CSV enables the text/csv list representation
+	CSV bool
+	// DefaultSort defines the sort applied to list queries that do not specify one (e.g. "-created_on")
+	DefaultSort string
+	Entity      crud.EntityDefinition
+	Hooks       crud.Hooks[T, D]
+	// Name defines the module name used in logger tags and load errors (e.g. "widget")
+	Name string
+	// Path defines the resource collection path within the router namespace (e.g. "widgets")
+	Path string
+}
+
+// newCRUDController wires the repository, service, query handler and controller of a generic CRUD module,
+// panicking on load errors (as with all resolver providers)
+func newCRUDController[T, D any](r *Resolver, m crudModuleConfig[T, D]) *crud.CRUDController[T, D] {
+	c := r.Config()
+
+	cLogger := func(layer string) *logger.CustomLogger {
+		return &logger.CustomLogger{
+			Level: c.Logger.Level,
+			Log:   r.Log().With(slog.String("tags", fmt.Sprintf("%s,%s", layer, m.Name))),
+		}
+	}
+	fail := func(layer string, err error) {
+		err = fmt.Errorf("%s %s load error: %w", m.Name, layer, err)
+		slog.Error(err.Error())
+		panic(err)
+	}
+
+	repo, err := crud.NewPGRepository[T, D](&crud.PGRepositoryConfig{
+		DBRouter: r.DatabaseRouter(),
+		Entity:   m.Entity,
+		Logger:   cLogger("repo"),
+	})
+	if err != nil {
+		fail("repository", err)
+	}
+
+	svc, err := crud.NewCRUDService(&crud.CRUDServiceConfig[T, D]{
+		Hooks:  m.Hooks,
+		Logger: cLogger("service"),
+		Repo:   repo,
+	})
+	if err != nil {
+		fail("service", err)
+	}
+
+	queryHandler, err := crud.NewQueryHandler[T](&crud.QueryConfig{
+		DefaultSort: m.DefaultSort,
+		PageLimit:   int(c.HTTP.Router.Paging.DefaultLimit),
+	})
+	if err != nil {
+		fail("query handler", err)
+	}
+	r.SubscribeConfig(m.Name+".query", func(prev, next *config.Configuration) (func(), error) {
+		return func() {
+			if prev.HTTP.Router.Paging.DefaultLimit != next.HTTP.Router.Paging.DefaultLimit {
+				queryHandler.SetDefaultPageLimit(int(next.HTTP.Router.Paging.DefaultLimit))
+			}
+		}, nil
+	})
+
+	ctrl, err := crud.NewCRUDController(&crud.CRUDControllerConfig[T, D]{
+		CSV:       m.CSV,
+		Entity:    m.Entity,
+		Logger:    cLogger("controller"),
+		Namespace: c.HTTP.Router.Namespace,
+		Path:      m.Path,
+		Query:     queryHandler,
+		Service:   svc,
+	})
+	if err != nil {
+		fail("controller", err)
+	}
+
+	return ctrl
+}
@@ -0,0 +1,84 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	crud "github.com/jasonsites/gosk/internal/modules/common/crud"
+)
+
+// crudRecord defines a generic CRUD model over the example_entity table
+type crudRecord struct {
+	ID         uuid.UUID `db:"id" json:"-"`
+	Title      string    `db:"title" json:"title" query:"sort,filter=ilike"`
+	CreatedOn  time.Time `db:"created_on" json:"created_on" query:"sort"`
+	ModifiedOn time.Time `db:"modified_on" json:"modified_on" query:"sort"`
+}
+
+// crudInput defines a generic CRUD DTO over the example_entity table
+type crudInput struct {
+	Title string `db:"title" json:"title" validate:"required"`
+}
+
+// Test_NewCRUDController verifies the wiring of generic CRUD modules (as generated by the module generator), with
+// requests rejected by the wired query handler before reaching the database
+func Test_NewCRUDController(t *testing.T) {
+	conf, err := config.LoadConfiguration()
+	if err != nil {
+		t.Fatalf("configuration load error: %+v\n", err)
+	}
+
+	r := NewResolver(context.Background(), &Config{Config: conf})
+	ctrl := newCRUDController(r, crudModuleConfig[crudRecord, crudInput]{
+		DefaultSort: "-modified_on",
+		Entity:      crud.EntityDefinition{ModifiedOn: "modified_on", Name: "example_entity", Type: "record"},
+		Name:        "record",
+		Path:        "records",
+	})
+
+	mux := chi.NewRouter()
+	crud.Router(mux, conf.HTTP.Router.Namespace, "records", ctrl)
+
+	tests := []struct {
+		Name      string
+		Query     string
+		ErrorCode string
+	}{
+		{Name: "unsupported_filter", Query: "filter[created_on]=x", ErrorCode: "validation_unsupported_filter"},
+		{Name: "repeated_filter", Query: "filter[title]=a&filter[title]=b", ErrorCode: "validation_repeated_filter"},
+		{Name: "invalid_sort", Query: "sort=unknown", ErrorCode: "validation_invalid_sort"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/"+conf.HTTP.Router.Namespace+"/records?"+tc.Query, nil)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected '%d', actual '%d' (%s)", http.StatusBadRequest, rec.Code, rec.Body.String())
+			}
+			var body jsonapi.ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatalf("response decode error: %+v\n", err)
+			}
+			if len(body.Errors) != 1 || body.Errors[0].Code != tc.ErrorCode {
+				t.Errorf("expected '%s' error, actual %+v", tc.ErrorCode, body.Errors)
+			}
+		})
+	}
+
+	t.Run("operations", func(t *testing.T) {
+		if ops := ctrl.Operations(); len(ops) != 5 {
+			t.Errorf("expected 5 described operations, actual %d", len(ops))
+		}
+	})
+}
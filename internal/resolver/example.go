@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log/slog"
 
+	"github.com/jasonsites/gosk/config"
 	"github.com/jasonsites/gosk/internal/logger"
 	query "github.com/jasonsites/gosk/internal/modules/common/models/query"
 	"github.com/jasonsites/gosk/internal/modules/example"
@@ -21,9 +22,12 @@ func (r *Resolver) ExampleController() example.ExampleController {
 		}
 
 		ctrlConfig := &example.ControllerConfig{
-			Logger:  cLogger,
-			Query:   r.ExampleQueryHandler(),
-			Service: r.ExampleService(),
+			ImportSyncMaxSize: c.HTTP.Router.Import.SyncMaxSize,
+			Logger:            cLogger,
+			MaxIncludeDepth:   int(c.HTTP.Router.MaxIncludeDepth),
+			Namespace:         c.HTTP.Router.Namespace,
+			Query:             r.ExampleQueryHandler(),
+			Service:           r.ExampleService(),
 		}
 		ctrl, err := example.NewController(ctrlConfig)
 		if err != nil {
@@ -50,7 +54,8 @@ func (r *Resolver) ExampleQueryHandler() *example.ExampleQueryHandler {
 				Page: query.PageQuery{
 					Limit: &limit,
 				},
-				Sort: example.DefaultExampleSortQuery(),
+				Sort:       example.DefaultExampleSortQuery(),
+				SearchSort: example.DefaultExampleSearchSortQuery(),
 			},
 			EntryFactory: example.CreateSortEntry,
 		}
@@ -63,6 +68,14 @@ func (r *Resolver) ExampleQueryHandler() *example.ExampleQueryHandler {
 		}
 
 		r.exampleQueryHandler = queryHandler
+
+		r.SubscribeConfig("example.query", func(prev, next *config.Configuration) (func(), error) {
+			return func() {
+				if prev.HTTP.Router.Paging.DefaultLimit != next.HTTP.Router.Paging.DefaultLimit {
+					r.exampleQueryHandler.SetDefaultPageLimit(int(next.HTTP.Router.Paging.DefaultLimit))
+				}
+			}, nil
+		})
 	}
 
 	return r.exampleQueryHandler
@@ -79,7 +92,8 @@ func (r *Resolver) ExampleRepository() example.ExampleRepository {
 			Log:   log,
 		}
 		repoConfig := &example.ExampleRepoConfig{
-			DBClient: r.PostgreSQLClient(),
+			DBRouter: r.DatabaseRouter(),
+			Jobs:     r.JobQueue(),
 			Logger:   cLogger,
 		}
 
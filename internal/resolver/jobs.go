@@ -0,0 +1,66 @@
+package resolver
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/jasonsites/gosk/internal/jobs"
+	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/modules/example"
+)
+
+// JobQueue provides a singleton jobs.Queue instance for enqueuing jobs
+func (r *Resolver) JobQueue() *jobs.Queue {
+	if r.jobQueue == nil {
+		queueConfig := &jobs.QueueConfig{
+			DBRouter: r.DatabaseRouter(),
+		}
+
+		queue, err := jobs.NewQueue(queueConfig)
+		if err != nil {
+			err = fmt.Errorf("job queue load error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+
+		r.jobQueue = queue
+	}
+
+	return r.jobQueue
+}
+
+// Worker provides a singleton jobs.Worker instance
+// NOTE: module job handlers should be registered here (i.e. worker.Register(kind, handler))
+func (r *Resolver) Worker() *jobs.Worker {
+	if r.worker == nil {
+		c := r.Config()
+
+		log := r.Log().With(slog.String("tags", "jobs,worker"))
+		cLogger := &logger.CustomLogger{
+			Level: c.Logger.Level,
+			Log:   log,
+		}
+
+		workerConfig := &jobs.WorkerConfig{
+			Concurrency:  c.Jobs.Concurrency,
+			DBClient:     r.PostgreSQLClient(),
+			Logger:       cLogger,
+			PollInterval: c.Jobs.PollInterval,
+			Queues:       c.Jobs.Queues,
+			Timeout:      c.Jobs.Timeout,
+		}
+
+		worker, err := jobs.NewWorker(workerConfig)
+		if err != nil {
+			err = fmt.Errorf("worker load error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+
+		worker.Register(example.ImportJobKind, example.ImportJobHandler(r.ExampleService()))
+
+		r.worker = worker
+	}
+
+	return r.worker
+}
@@ -0,0 +1,174 @@
+package resolver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/jasonsites/gosk/config"
+	app "github.com/jasonsites/gosk/internal/app"
+)
+
+// ConfigSubscriber prepares a validated configuration change for a running app component, returning an error if the
+// component can not apply it, or the function applying it. Changes are only applied once prepared by all subscribers
+type ConfigSubscriber func(prev, next *config.Configuration) (apply func(), err error)
+
+// ConfigStatus defines configuration reload metadata: the number of applied (Reloads) and rejected configuration
+// changes (invalid, or not prepared by a subscriber), and the time and version of the current configuration
+type ConfigStatus struct {
+	AppliedOn time.Time `json:"applied_on"`
+	Rejected  uint64    `json:"rejected"`
+	Reloads   uint64    `json:"reloads"`
+	Version   string    `json:"version"`
+}
+
+// configSubscription defines a named ConfigSubscriber
+type configSubscription struct {
+	name string
+	fn   ConfigSubscriber
+}
+
+// configReloader tracks config subscriptions and reload status
+type configReloader struct {
+	mutex         sync.Mutex
+	status        ConfigStatus
+	subscriptions []configSubscription
+	watching      bool
+}
+
+// SubscribeConfig registers a subscriber to be called with each validated configuration change
+func (r *Resolver) SubscribeConfig(name string, fn ConfigSubscriber) {
+	r.reloader.mutex.Lock()
+	defer r.reloader.mutex.Unlock()
+
+	r.reloader.subscriptions = append(r.reloader.subscriptions, configSubscription{name: name, fn: fn})
+}
+
+// ConfigStatus returns the configuration reload status
+func (r *Resolver) ConfigStatus() ConfigStatus {
+	r.reloader.mutex.Lock()
+	defer r.reloader.mutex.Unlock()
+
+	return r.reloader.status
+}
+
+// ReloadConfig applies the given configuration to all subscribers after validation (once per change, as
+// configuration is not validated by config.WatchConfiguration) and preparation by all subscribers, rejecting (and
+// logging) configuration that failed to load, validate or prepare, and keeping the previous configuration
+func (r *Resolver) ReloadConfig(next *config.Configuration, err error) {
+	r.reloader.mutex.Lock()
+	defer r.reloader.mutex.Unlock()
+
+	reject := func(err error) {
+		r.reloader.status.Rejected++
+		err = fmt.Errorf("configuration change rejected: %w", err)
+		slog.Error(err.Error())
+	}
+
+	if err == nil {
+		err = app.Validator.Validate.Struct(next)
+	}
+	if err != nil {
+		reject(err)
+		return
+	}
+
+	prev := r.Config()
+	applies := make([]func(), 0, len(r.reloader.subscriptions))
+	for _, sub := range r.reloader.subscriptions {
+		apply, err := sub.fn(prev, next)
+		if err != nil {
+			reject(fmt.Errorf("%s: %w", sub.name, err))
+			return
+		}
+		applies = append(applies, apply)
+	}
+	for _, apply := range applies {
+		apply()
+	}
+
+	r.configMutex.Lock()
+	r.config = next
+	r.configMutex.Unlock()
+
+	r.reloader.status.AppliedOn = time.Now()
+	r.reloader.status.Reloads++
+	r.reloader.status.Version = configVersion(next)
+
+	slog.Info(fmt.Sprintf("configuration change applied (version %s)", r.reloader.status.Version))
+}
+
+// WatchConfig starts watching the config file for changes (once per resolver), applying each change with
+// ReloadConfig. It is started by the runtime, so that one-shot commands (e.g. config validate) do not watch the
+// config file. Resolvers constructed with a Config are watched alike, but only when a config file has been loaded
+// (by config.LoadConfiguration), and changes then replace the given Config
+func (r *Resolver) WatchConfig() {
+	r.Config()
+
+	r.reloader.mutex.Lock()
+	defer r.reloader.mutex.Unlock()
+
+	if r.reloader.watching {
+		return
+	}
+	r.reloader.watching = true
+
+	config.WatchConfiguration(r.ReloadConfig)
+}
+
+// initConfigStatus records the initially loaded configuration in the reload status
+func (r *Resolver) initConfigStatus(conf *config.Configuration) {
+	r.reloader.mutex.Lock()
+	defer r.reloader.mutex.Unlock()
+
+	r.reloader.status.AppliedOn = time.Now()
+	r.reloader.status.Version = configVersion(conf)
+}
+
+// configVersion returns a short content hash identifying the given configuration, including its (revealed)
+// secret values, so that secret rotations are versioned as well
+func configVersion(conf *config.Configuration) string {
+	b, err := json.Marshal(conf)
+	if err != nil {
+		return "unknown"
+	}
+
+	h := sha256.New()
+	h.Write(b)
+	hashSecrets(h, reflect.ValueOf(conf))
+
+	return hex.EncodeToString(h.Sum(nil)[:6])
+}
+
+// hashSecrets writes the revealed values of all config.Secret values of v to h, in field order
+func hashSecrets(h hash.Hash, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			hashSecrets(h, v.Elem())
+		}
+	case reflect.Struct:
+		for i := range v.NumField() {
+			if v.Type().Field(i).IsExported() {
+				hashSecrets(h, v.Field(i))
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			hashSecrets(h, v.Index(i))
+		}
+	case reflect.Map:
+		// secrets are not defined within maps, whose iteration order is random
+	case reflect.String:
+		if secret, ok := v.Interface().(config.Secret); ok {
+			h.Write([]byte(secret.Reveal()))
+			h.Write([]byte{0})
+		}
+	}
+}
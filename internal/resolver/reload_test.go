@@ -0,0 +1,121 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jasonsites/gosk/config"
+)
+
+// Test_ReloadConfig verifies that valid configuration changes prepared by all subscribers are applied, and that
+// invalid or unprepared configuration changes are rejected (and counted separately), keeping the previous
+// configuration
+func Test_ReloadConfig(t *testing.T) {
+	conf, err := config.LoadConfiguration()
+	if err != nil {
+		t.Fatalf("configuration load error: %+v\n", err)
+	}
+
+	r := NewResolver(context.Background(), &Config{Config: conf})
+	initial := r.ConfigStatus()
+	if initial.Version == "" || initial.AppliedOn.IsZero() {
+		t.Errorf("expected initial config status, actual %+v", initial)
+	}
+
+	// failingLimit defines the default page limit which the failing subscriber can not apply
+	const failingLimit = 999
+
+	var applied int
+	var prev, next *config.Configuration
+	r.SubscribeConfig("test", func(p, n *config.Configuration) (func(), error) {
+		return func() {
+			applied++
+			prev, next = p, n
+		}, nil
+	})
+	r.SubscribeConfig("failing", func(p, n *config.Configuration) (func(), error) {
+		if n.HTTP.Router.Paging.DefaultLimit == failingLimit {
+			return nil, errors.New("subscriber error")
+		}
+		return func() {}, nil
+	})
+
+	expectStatus := func(t *testing.T, reloads, rejected uint64) ConfigStatus {
+		t.Helper()
+		status := r.ConfigStatus()
+		if status.Reloads != reloads || status.Rejected != rejected {
+			t.Errorf("expected %d applied and %d rejected reloads, actual %+v", reloads, rejected, status)
+		}
+		return status
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		changed := *conf
+		changed.HTTP.Router.Paging.DefaultLimit = conf.HTTP.Router.Paging.DefaultLimit + 1
+
+		r.ReloadConfig(&changed, nil)
+
+		if applied != 1 || prev != conf || next != &changed {
+			t.Fatalf("expected change applied with previous and next configuration, actual %d applies", applied)
+		}
+		if r.Config() != &changed {
+			t.Errorf("expected changed configuration to be applied")
+		}
+		if status := expectStatus(t, 1, 0); status.Version == initial.Version {
+			t.Errorf("expected a new version, actual '%s'", status.Version)
+		}
+	})
+
+	t.Run("apply_secret", func(t *testing.T) {
+		before := r.ConfigStatus().Version
+		rotated := *r.Config()
+		rotated.Postgres.Password = config.Secret(rotated.Postgres.Password.Reveal() + "-rotated")
+
+		r.ReloadConfig(&rotated, nil)
+
+		if status := expectStatus(t, 2, 0); status.Version == before {
+			t.Errorf("expected a new version for a rotated secret, actual '%s'", status.Version)
+		}
+	})
+
+	t.Run("reject_invalid", func(t *testing.T) {
+		current := r.Config()
+		invalid := *current
+		invalid.HTTP.Server.Port = 70000
+
+		r.ReloadConfig(&invalid, nil)
+
+		if applied != 2 || r.Config() != current {
+			t.Errorf("expected invalid configuration not to be applied")
+		}
+		expectStatus(t, 2, 1)
+	})
+
+	t.Run("reject_load_error", func(t *testing.T) {
+		current := r.Config()
+
+		r.ReloadConfig(&config.Configuration{}, errors.New("configuration unmarshal error"))
+
+		if applied != 2 || r.Config() != current {
+			t.Errorf("expected configuration load error to be rejected")
+		}
+		expectStatus(t, 2, 2)
+	})
+
+	t.Run("reject_subscriber", func(t *testing.T) {
+		current := r.Config()
+		version := r.ConfigStatus().Version
+		unprepared := *current
+		unprepared.HTTP.Router.Paging.DefaultLimit = failingLimit
+
+		r.ReloadConfig(&unprepared, nil)
+
+		if applied != 2 || r.Config() != current {
+			t.Errorf("expected configuration not prepared by all subscribers not to be applied")
+		}
+		if status := expectStatus(t, 2, 3); status.Version != version {
+			t.Errorf("expected version '%s', actual '%s'", version, status.Version)
+		}
+	})
+}
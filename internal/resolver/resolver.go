@@ -4,46 +4,80 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"sync"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jasonsites/gosk/config"
 	app "github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/database"
 	"github.com/jasonsites/gosk/internal/http/httpserver"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/jobs"
+	"github.com/jasonsites/gosk/internal/logger"
+	crud "github.com/jasonsites/gosk/internal/modules/common/crud"
 	"github.com/jasonsites/gosk/internal/modules/example"
+	"github.com/jasonsites/gosk/internal/scheduler"
 )
 
 type ResolverEntry string
 
 const (
-	Unset ResolverEntry = ""
-	HTTP  ResolverEntry = "http"
-	gRPC  ResolverEntry = "grpc"
+	Unset  ResolverEntry = ""
+	HTTP   ResolverEntry = "http"
+	GRPC   ResolverEntry = "grpc"
+	Worker ResolverEntry = "worker"
 )
 
 // Config defines the input to NewResolver
 type Config struct {
+	// Authenticator defines optional authentication middleware, setting the verified mw.ClientIdentity of
+	// authenticated requests (used to key rate limits)
+	Authenticator     func(http.Handler) http.Handler
 	Config            *config.Configuration
 	ExampleController example.ExampleController
 	ExampleRepo       example.ExampleRepository
 	ExampleService    example.ExampleService
-	HTTPServer        *httpserver.Server
-	Log               *slog.Logger
-	Metadata          *app.Metadata
-	PostgreSQLClient  *pgxpool.Pool
+	// ErrorReporter defines an optional error reporting sink for recovered panics
+	ErrorReporter      mw.ErrorReporter
+	HTTPServer         *httpserver.Server
+	Log                *slog.Logger
+	Metadata           *app.Metadata
+	PostgreSQLClient   *pgxpool.Pool
+	PostgreSQLReplicas []*pgxpool.Pool
+	RateLimitStore     mw.RateLimitStore
 }
 
 // Resolver provides a configurable app component graph
 type Resolver struct {
 	appContext          context.Context
+	authenticator       func(http.Handler) http.Handler
 	config              *config.Configuration
+	configMutex         sync.RWMutex
+	cors                *mw.CORS
+	crudControllers     map[string]crud.Controller
+	databaseRouter      *database.Router
+	errorReporter       mw.ErrorReporter
 	exampleController   example.ExampleController
 	exampleQueryHandler *example.ExampleQueryHandler
 	exampleRepo         example.ExampleRepository
 	exampleService      example.ExampleService
 	httpServer          *httpserver.Server
+	jobQueue            *jobs.Queue
 	log                 *slog.Logger
+	logLevel            *slog.LevelVar
 	metadata            *app.Metadata
+	migrator            *database.Migrator
 	postgreSQLClient    *pgxpool.Pool
+	postgreSQLReplicas  []*pgxpool.Pool
+	rateLimiter         *mw.RateLimiter
+	rateLimitStore      mw.RateLimitStore
+	recoverer           *mw.Recoverer
+	redactor            *logger.Redactor
+	reloader            configReloader
+	scheduler           *scheduler.Scheduler
+	seeder              *database.Seeder
+	worker              *jobs.Worker
 }
 
 // NewResolver returns a new Resolver instance
@@ -53,15 +87,23 @@ func NewResolver(ctx context.Context, c *Config) *Resolver {
 	}
 
 	r := &Resolver{
-		appContext:        ctx,
-		config:            c.Config,
-		exampleController: c.ExampleController,
-		exampleRepo:       c.ExampleRepo,
-		exampleService:    c.ExampleService,
-		httpServer:        c.HTTPServer,
-		log:               c.Log,
-		metadata:          c.Metadata,
-		postgreSQLClient:  c.PostgreSQLClient,
+		appContext:         ctx,
+		authenticator:      c.Authenticator,
+		config:             c.Config,
+		crudControllers:    make(map[string]crud.Controller),
+		exampleController:  c.ExampleController,
+		exampleRepo:        c.ExampleRepo,
+		exampleService:     c.ExampleService,
+		errorReporter:      c.ErrorReporter,
+		httpServer:         c.HTTPServer,
+		log:                c.Log,
+		metadata:           c.Metadata,
+		postgreSQLClient:   c.PostgreSQLClient,
+		postgreSQLReplicas: c.PostgreSQLReplicas,
+		rateLimitStore:     c.RateLimitStore,
+	}
+	if c.Config != nil {
+		r.initConfigStatus(c.Config)
 	}
 
 	return r
@@ -70,8 +112,17 @@ func NewResolver(ctx context.Context, c *Config) *Resolver {
 // Load resolves app components starting from the given entry node of the component graph
 func (r *Resolver) Load(entry ResolverEntry) {
 	switch entry {
-	case "http":
+	case HTTP:
+		// the scheduler (run by workers) is built at startup for task status health reporting
+		if r.Config().Scheduler.Enabled {
+			r.Scheduler()
+		}
 		r.HTTPServer()
+	case Worker:
+		r.Worker()
+		if r.Config().Scheduler.Enabled {
+			r.Scheduler()
+		}
 	default:
 		panic(fmt.Errorf("invalid resolver load entry point '%s'", entry))
 	}
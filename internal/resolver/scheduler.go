@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/jobs"
+	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/modules/example"
+	"github.com/jasonsites/gosk/internal/scheduler"
+)
+
+// Scheduler provides a singleton scheduler.Scheduler instance, registering all configured maintenance tasks
+func (r *Resolver) Scheduler() *scheduler.Scheduler {
+	if r.scheduler == nil {
+		c := r.Config()
+		tc := c.Scheduler.Tasks
+
+		log := r.Log().With(slog.String("tags", "scheduler"))
+		cLogger := &logger.CustomLogger{
+			Level: c.Logger.Level,
+			Log:   log,
+		}
+
+		var tasks []scheduler.Task
+		if tc.ExamplePurge.Schedule != "" {
+			tasks = append(tasks, example.ExamplePurgeTask(
+				r.DatabaseRouter(), cLogger, tc.ExamplePurge.Schedule, tc.ExamplePurge.Retention,
+			))
+		}
+		if tc.JobsPrune.Schedule != "" {
+			tasks = append(tasks, jobs.PruneTask(
+				r.PostgreSQLClient(), cLogger, tc.JobsPrune.Schedule, tc.JobsPrune.Retention,
+			))
+		}
+		if store, ok := r.RateLimitStore().(*mw.PostgresRateLimitStore); ok && tc.RateLimitPrune.Schedule != "" {
+			tasks = append(tasks, rateLimitPruneTask(store, cLogger, tc.RateLimitPrune.Schedule, tc.RateLimitPrune.Retention))
+		}
+
+		schedulerConfig := &scheduler.SchedulerConfig{
+			DBClient: r.PostgreSQLClient(),
+			Logger:   cLogger,
+			Tasks:    tasks,
+		}
+
+		s, err := scheduler.NewScheduler(schedulerConfig)
+		if err != nil {
+			err = fmt.Errorf("scheduler load error: %w", err)
+			slog.Error(err.Error())
+			panic(err)
+		}
+
+		r.scheduler = s
+	}
+
+	return r.scheduler
+}
+
+// taskStatus returns the (cached) public scheduled task status for health reporting, for schedulers built at startup.
+// Run errors and replica details are only reported by adminTaskStatus
+func (r *Resolver) taskStatus() any {
+	if r.scheduler == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(r.appContext, 2*time.Second)
+	defer cancel()
+
+	status, err := r.scheduler.CachedStatus(ctx)
+	if err != nil {
+		r.Log().With(slog.String("tags", "scheduler")).Error(fmt.Sprintf("task status error: %s", err))
+		return map[string]string{"error": "task status unavailable"}
+	}
+
+	summary := make([]scheduler.TaskSummary, 0, len(status))
+	for _, ts := range status {
+		summary = append(summary, ts.Summary())
+	}
+	return summary
+}
+
+// adminTaskStatus returns the (cached) scheduled task status, with run errors and replica details, for admin status
+// reporting, for schedulers built at startup
+func (r *Resolver) adminTaskStatus() any {
+	if r.scheduler == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(r.appContext, 2*time.Second)
+	defer cancel()
+
+	status, err := r.scheduler.CachedStatus(ctx)
+	if err != nil {
+		return map[string]string{"error": err.Error()}
+	}
+	return status
+}
+
+// rateLimitPruneTask returns a scheduled task deleting idle postgres rate limit buckets
+func rateLimitPruneTask(store *mw.PostgresRateLimitStore, log *logger.CustomLogger, schedule string, idle time.Duration) scheduler.Task {
+	return scheduler.Task{
+		Name:     "ratelimit.prune",
+		Schedule: schedule,
+		Run: func(ctx context.Context) error {
+			n, err := store.Prune(ctx, idle)
+			if err != nil {
+				return err
+			}
+
+			traceID := trace.GetTraceIDFromContext(ctx)
+			log.CreateContextLogger(traceID).Info(fmt.Sprintf("pruned %d idle rate limit buckets", n))
+
+			return nil
+		},
+	}
+}
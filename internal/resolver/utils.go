@@ -3,12 +3,20 @@ package resolver
 import (
 	"fmt"
 	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jasonsites/gosk/config"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
 	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/modules/example"
 )
 
-func logLevel(l string) slog.Leveler {
+func logLevel(l string) slog.Level {
 	switch l {
 	case logger.LevelDebug:
 		return slog.LevelDebug
@@ -23,15 +31,128 @@ func logLevel(l string) slog.Leveler {
 	}
 }
 
-// postgresDSN returns a data source name string from a given postgres configuration
+// postgresConfig returns a primary pool configuration from a given postgres configuration, setting the password
+// on the parsed connection config so that it never appears in the data source name
+func postgresConfig(c config.Postgres, appName string) (*pgxpool.Config, error) {
+	conf, err := pgxpool.ParseConfig(postgresDSN(c))
+	if err != nil {
+		return nil, err
+	}
+	conf.ConnConfig.Password = c.Password.Reveal()
+
+	return postgresPoolConfig(c, conf, appName), nil
+}
+
+// postgresReplicaConfig returns a replica pool configuration from a given postgres configuration and replica data
+// source name (URL or keyword/value), which defines its own connection parameters. The primary password is used
+// if the replica data source name has none, and the primary pool settings are applied
+func postgresReplicaConfig(c config.Postgres, dsn string, appName string) (*pgxpool.Config, error) {
+	conf, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+	if conf.ConnConfig.Password == "" {
+		conf.ConnConfig.Password = c.Password.Reveal()
+	}
+
+	return postgresPoolConfig(c, conf, appName), nil
+}
+
+// postgresPoolConfig applies the pool settings, application name and statement timeout of a given postgres
+// configuration to a given pool configuration
+func postgresPoolConfig(c config.Postgres, conf *pgxpool.Config, appName string) *pgxpool.Config {
+	conf.HealthCheckPeriod = c.Pool.HealthCheckPeriod
+	conf.MaxConnIdleTime = c.Pool.MaxConnIdleTime
+	conf.MaxConnLifetime = c.Pool.MaxConnLifetime
+	conf.MaxConns = c.Pool.MaxConns
+	conf.MinConns = c.Pool.MinConns
+
+	if c.ApplicationName != "" {
+		appName = c.ApplicationName
+	}
+	conf.ConnConfig.RuntimeParams["application_name"] = appName
+	if c.StatementTimeout > 0 {
+		conf.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10)
+	}
+
+	return conf
+}
+
+// postgresDSN returns a primary data source name string (without password) from a given postgres configuration
 func postgresDSN(c config.Postgres) string {
-	// TODO: sslmode
-	return fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		c.Host,
+	var dsn strings.Builder
+
+	fmt.Fprintf(&dsn, "host=%s port=%d user=%s dbname=%s sslmode=%s",
+		dsnValue(c.Host),
 		c.Port,
-		c.User,
-		c.Password,
-		c.Database,
+		dsnValue(c.User),
+		dsnValue(c.Database),
+		dsnValue(c.TLS.SSLMode),
 	)
+
+	if c.TLS.RootCertFile != "" {
+		fmt.Fprintf(&dsn, " sslrootcert=%s", dsnValue(c.TLS.RootCertFile))
+	}
+	if c.TLS.CertFile != "" {
+		fmt.Fprintf(&dsn, " sslcert=%s sslkey=%s", dsnValue(c.TLS.CertFile), dsnValue(c.TLS.KeyFile))
+	}
+
+	return dsn.String()
+}
+
+// bodyLimitRoutes returns middleware route body limits from the given configuration, with the example import route
+// limited to the maximum import size (unless configured as a body limit route)
+func bodyLimitRoutes(c *config.Configuration) []mw.BodyLimitRoute {
+	importPath := fmt.Sprintf("/%s/examples/import", c.HTTP.Router.Namespace)
+
+	routes := c.HTTP.Router.BodyLimit.Routes
+	result := make([]mw.BodyLimitRoute, 0, len(routes)+1)
+	result = append(result, mw.BodyLimitRoute{Method: http.MethodPost, Path: importPath, Limit: c.HTTP.Router.Import.MaxSize})
+	for _, route := range routes {
+		result = append(result, mw.BodyLimitRoute{
+			Method: route.Method,
+			Path:   route.Path,
+			Limit:  route.Limit,
+		})
+	}
+	return result
+}
+
+// dsnValue quotes a data source name keyword value
+func dsnValue(v string) string {
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return fmt.Sprintf("'%s'", v)
+}
+
+// redactionPolicy returns the logger redaction policy from the given configuration, with the redacted (tagged)
+// request body fields registered by each module
+func redactionPolicy(c *config.Configuration) *logger.RedactionPolicy {
+	fields := slices.Clone(c.Logger.Redact.Fields)
+	fields = append(fields, example.ExampleRedactedFields()...)
+	// gosk:redaction (generated module redacted fields are added above)
+
+	return &logger.RedactionPolicy{
+		Fields:  fields,
+		Headers: c.Logger.Redact.Headers,
+		Keys:    c.Logger.Redact.Keys,
+	}
+}
+
+// rateLimitQuota returns a middleware rate limit quota from the given limit and period
+func rateLimitQuota(limit uint, period time.Duration) mw.RateLimitQuota {
+	return mw.RateLimitQuota{Limit: limit, Period: period}
+}
+
+// rateLimitRoutes returns middleware route quotas from the given rate limit route configuration
+func rateLimitRoutes(routes []config.RateLimitRoute) []mw.RateLimitRoute {
+	result := make([]mw.RateLimitRoute, 0, len(routes))
+	for _, route := range routes {
+		result = append(result, mw.RateLimitRoute{
+			Method: route.Method,
+			Path:   route.Path,
+			Quota:  rateLimitQuota(route.Limit, route.Period),
+		})
+	}
+	return result
 }
@@ -0,0 +1,238 @@
+package resolver
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jasonsites/gosk/config"
+)
+
+// testPostgres returns a valid postgres configuration for pool configuration tests
+func testPostgres() config.Postgres {
+	return config.Postgres{
+		Database: "gosk",
+		Host:     "primary",
+		Password: config.Secret("s3cr3t"),
+		Pool: config.PostgresPool{
+			HealthCheckPeriod: time.Minute,
+			MaxConnIdleTime:   30 * time.Minute,
+			MaxConnLifetime:   time.Hour,
+			MaxConns:          10,
+			MinConns:          2,
+		},
+		Port: 5432,
+		TLS:  config.PostgresTLS{SSLMode: "disable"},
+		User: "postgres",
+	}
+}
+
+type PostgresConfigSetup struct {
+	Name        string
+	Description string
+	Postgres    func(c *config.Postgres)
+	DSN         string
+	Expected    PostgresConfigExpected
+}
+
+// PostgresConfigExpected defines the expected connection and runtime parameters of a pool configuration
+type PostgresConfigExpected struct {
+	Host             string
+	Port             uint16
+	User             string
+	Database         string
+	Password         string
+	ApplicationName  string
+	StatementTimeout string
+	TLS              bool
+	Error            bool
+}
+
+// Test_PostgresConfig verifies primary and replica pool configurations
+func Test_PostgresConfig(t *testing.T) {
+	tests := []PostgresConfigSetup{
+		{
+			Name:        "primary",
+			Description: "builds the primary configuration from connection parameters",
+			Expected: PostgresConfigExpected{
+				Host: "primary", Port: 5432, User: "postgres", Database: "gosk", Password: "s3cr3t", ApplicationName: "gosk",
+			},
+		},
+		{
+			Name:        "primary_options",
+			Description: "applies the application name, statement timeout and port",
+			Postgres: func(c *config.Postgres) {
+				c.ApplicationName = "gosk-api"
+				c.Port = 6432
+				c.StatementTimeout = 1500 * time.Millisecond
+			},
+			Expected: PostgresConfigExpected{
+				Host: "primary", Port: 6432, User: "postgres", Database: "gosk", Password: "s3cr3t",
+				ApplicationName: "gosk-api", StatementTimeout: "1500",
+			},
+		},
+		{
+			Name:        "primary_quoted",
+			Description: "quotes connection parameter values",
+			Postgres: func(c *config.Postgres) {
+				c.Database = "gosk db"
+				c.User = `o'brien\admin`
+			},
+			Expected: PostgresConfigExpected{
+				Host: "primary", Port: 5432, User: `o'brien\admin`, Database: "gosk db", Password: "s3cr3t", ApplicationName: "gosk",
+			},
+		},
+		{
+			Name:        "primary_tls",
+			Description: "applies the ssl mode",
+			Postgres: func(c *config.Postgres) {
+				c.TLS.SSLMode = "require"
+			},
+			Expected: PostgresConfigExpected{
+				Host: "primary", Port: 5432, User: "postgres", Database: "gosk", Password: "s3cr3t", ApplicationName: "gosk", TLS: true,
+			},
+		},
+		{
+			Name:        "primary_tls_missing_cert",
+			Description: "fails for unreadable tls certificate files",
+			Postgres: func(c *config.Postgres) {
+				c.TLS.SSLMode = "verify-full"
+				c.TLS.RootCertFile = "/nonexistent/root.crt"
+			},
+			Expected: PostgresConfigExpected{Error: true},
+		},
+		{
+			Name:        "replica_url",
+			Description: "builds a replica configuration from a URL, with the primary password",
+			DSN:         "postgres://reader@replica-1:6432/gosk_ro?sslmode=disable",
+			Expected: PostgresConfigExpected{
+				Host: "replica-1", Port: 6432, User: "reader", Database: "gosk_ro", Password: "s3cr3t", ApplicationName: "gosk",
+			},
+		},
+		{
+			Name:        "replica_keyword",
+			Description: "builds a replica configuration from keyword/value pairs, with its own password",
+			DSN:         "host=replica-2 port=5433 user=reader password=r3ad dbname=gosk sslmode=require",
+			Expected: PostgresConfigExpected{
+				Host: "replica-2", Port: 5433, User: "reader", Database: "gosk", Password: "r3ad", ApplicationName: "gosk", TLS: true,
+			},
+		},
+		{
+			Name:        "replica_options",
+			Description: "applies the primary application name and statement timeout to replicas",
+			Postgres: func(c *config.Postgres) {
+				c.ApplicationName = "gosk-api"
+				c.StatementTimeout = 2 * time.Second
+			},
+			DSN: "postgres://reader@replica-1/gosk?sslmode=disable",
+			Expected: PostgresConfigExpected{
+				Host: "replica-1", Port: 5432, User: "reader", Database: "gosk", Password: "s3cr3t",
+				ApplicationName: "gosk-api", StatementTimeout: "2000",
+			},
+		},
+		{
+			Name:        "replica_invalid",
+			Description: "fails for invalid replica data source names",
+			DSN:         "postgres://replica-1:port/gosk",
+			Expected:    PostgresConfigExpected{Error: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			c := testPostgres()
+			if tc.Postgres != nil {
+				tc.Postgres(&c)
+			}
+
+			conf, err := postgresConfig(c, "gosk")
+			if tc.DSN != "" {
+				conf, err = postgresReplicaConfig(c, tc.DSN, "gosk")
+			}
+
+			if tc.Expected.Error {
+				if err == nil {
+					t.Errorf("expected error, actual nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("postgres config error: %+v\n", err)
+			}
+
+			cc := conf.ConnConfig
+			if cc.Host != tc.Expected.Host || cc.Port != tc.Expected.Port {
+				t.Errorf("expected '%s:%d', actual '%s:%d'", tc.Expected.Host, tc.Expected.Port, cc.Host, cc.Port)
+			}
+			if cc.User != tc.Expected.User || cc.Database != tc.Expected.Database {
+				t.Errorf("expected '%s@%s', actual '%s@%s'", tc.Expected.User, tc.Expected.Database, cc.User, cc.Database)
+			}
+			if cc.Password != tc.Expected.Password {
+				t.Errorf("expected password '%s', actual '%s'", tc.Expected.Password, cc.Password)
+			}
+			if v := cc.RuntimeParams["application_name"]; v != tc.Expected.ApplicationName {
+				t.Errorf("expected application_name '%s', actual '%s'", tc.Expected.ApplicationName, v)
+			}
+			if v := cc.RuntimeParams["statement_timeout"]; v != tc.Expected.StatementTimeout {
+				t.Errorf("expected statement_timeout '%s', actual '%s'", tc.Expected.StatementTimeout, v)
+			}
+			if tls := cc.TLSConfig != nil; tls != tc.Expected.TLS {
+				t.Errorf("expected tls '%t', actual '%t'", tc.Expected.TLS, tls)
+			}
+			if conf.MaxConns != c.Pool.MaxConns || conf.MinConns != c.Pool.MinConns ||
+				conf.HealthCheckPeriod != c.Pool.HealthCheckPeriod || conf.MaxConnIdleTime != c.Pool.MaxConnIdleTime ||
+				conf.MaxConnLifetime != c.Pool.MaxConnLifetime {
+				t.Errorf("expected pool settings %+v, actual %+v", c.Pool, conf)
+			}
+		})
+	}
+}
+
+// Test_PostgresDSN verifies that the primary data source name never contains the password, and includes tls files
+func Test_PostgresDSN(t *testing.T) {
+	c := testPostgres()
+	c.TLS = config.PostgresTLS{
+		CertFile:     "/certs/client.crt",
+		KeyFile:      "/certs/client.key",
+		RootCertFile: "/certs/root.crt",
+		SSLMode:      "verify-full",
+	}
+
+	expected := "host='primary' port=5432 user='postgres' dbname='gosk' sslmode='verify-full'" +
+		" sslrootcert='/certs/root.crt' sslcert='/certs/client.crt' sslkey='/certs/client.key'"
+	if dsn := postgresDSN(c); dsn != expected {
+		t.Errorf("expected '%s', actual '%s'", expected, dsn)
+	}
+}
+
+// Test_DSNValue verifies data source name value quoting
+func Test_DSNValue(t *testing.T) {
+	tests := map[string]string{
+		"":              "''",
+		"gosk":          "'gosk'",
+		"gosk db":       "'gosk db'",
+		"o'brien":       `'o\'brien'`,
+		`C:\certs`:      `'C:\\certs'`,
+		`\'`:            `'\\\''`,
+		"x' host='evil": `'x\' host=\'evil'`,
+	}
+
+	for value, expected := range tests {
+		if actual := dsnValue(value); actual != expected {
+			t.Errorf("expected '%s', actual '%s'", expected, actual)
+		}
+	}
+}
+
+// Test_RedactionPolicy verifies that the redaction policy includes the configured and module registered fields
+func Test_RedactionPolicy(t *testing.T) {
+	c := &config.Configuration{}
+	c.Logger.Redact.Fields = []string{"data.attributes.password"}
+
+	fields := redactionPolicy(c).Fields
+	for _, field := range []string{"data.attributes.password", "data.attributes.description"} {
+		if !slices.Contains(fields, field) {
+			t.Errorf("expected redacted field '%s', actual %v", field, fields)
+		}
+	}
+}
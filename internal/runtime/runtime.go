@@ -31,7 +31,7 @@ type RunConfig struct {
 
 // Run creates a new Resolver with associated context group, then runs goroutines for initializing
 // the application and handling graceful shutdown
-func (rt *Runtime) Run(conf *RunConfig) *resolver.Resolver {
+func (rt *Runtime) Run(conf *RunConfig) (*resolver.Resolver, error) {
 	c, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
@@ -40,12 +40,26 @@ func (rt *Runtime) Run(conf *RunConfig) *resolver.Resolver {
 	slog.Info("initializing resolver")
 	r := resolver.NewResolver(ctx, rt.config)
 
+	// closed once the configured application has stopped running
+	stopped := make(chan struct{})
+
 	// load resolver app components and start the configured application
 	g.Go(func() error {
+		defer close(stopped)
+
+		r.WatchConfig()
+
+		if r.Config().Postgres.MigrateOnStartup {
+			slog.Info("running database migrations")
+			if err := r.Migrator().Up(ctx); err != nil {
+				return fmt.Errorf("database migration error: %w", err)
+			}
+		}
+
 		slog.Info("loading resolver app components")
 
 		switch conf.Entry {
-		case "http":
+		case resolver.HTTP:
 			{
 				r.Load(conf.Entry)
 
@@ -55,6 +69,26 @@ func (rt *Runtime) Run(conf *RunConfig) *resolver.Resolver {
 					return err
 				}
 			}
+		case resolver.Worker:
+			{
+				r.Load(conf.Entry)
+
+				wg, wctx := errgroup.WithContext(ctx)
+
+				slog.Info("starting job worker")
+				wg.Go(func() error { return r.Worker().Run(wctx) })
+
+				if r.Config().Scheduler.Enabled {
+					slog.Info("starting scheduler")
+					wg.Go(func() error { return r.Scheduler().Run(wctx) })
+				}
+
+				if err := wg.Wait(); err != nil {
+					return err
+				}
+			}
+		default:
+			return fmt.Errorf("unsupported run entry '%s'", conf.Entry)
 		}
 
 		return nil
@@ -67,7 +101,7 @@ func (rt *Runtime) Run(conf *RunConfig) *resolver.Resolver {
 		slog.Info("shutdown initiated")
 
 		switch conf.Entry {
-		case "http":
+		case resolver.HTTP:
 			{
 				server := r.HTTPServer()
 				if err := server.Server.Shutdown(context.Background()); err != nil {
@@ -75,10 +109,19 @@ func (rt *Runtime) Run(conf *RunConfig) *resolver.Resolver {
 				}
 				slog.Info("http server shut down")
 			}
+		case resolver.Worker:
+			{
+				// the worker and scheduler stop on context cancellation, then wait for in-flight jobs and tasks
+				<-stopped
+				slog.Info("job worker and scheduler shut down")
+			}
 		}
 
 		pool := r.PostgreSQLClient()
 		pool.Close()
+		for _, replica := range r.PostgreSQLReplicaClients() {
+			replica.Close()
+		}
 		slog.Info("db connection pool closed")
 
 		slog.Info("shutdown complete")
@@ -89,7 +132,8 @@ func (rt *Runtime) Run(conf *RunConfig) *resolver.Resolver {
 	if err := g.Wait(); err != nil {
 		err = fmt.Errorf("application run error: %w", err)
 		slog.Error(err.Error())
+		return r, err
 	}
 
-	return r
+	return r, nil
 }
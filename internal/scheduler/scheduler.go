@@ -0,0 +1,314 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/app"
+	"github.com/jasonsites/gosk/internal/database"
+	"github.com/jasonsites/gosk/internal/http/trace"
+	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/robfig/cron/v3"
+)
+
+// statusTTL defines the duration for which task status (e.g. for health reporting) is cached
+const statusTTL = 15 * time.Second
+
+const (
+	StatusFailed    = "failed"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+)
+
+// Task defines a periodic task
+type Task struct {
+	Name string `validate:"required"`
+	// Run runs a single scheduled run of the task
+	Run func(ctx context.Context) error `validate:"required"`
+	// Schedule defines a standard cron expression (e.g. "*/15 * * * *") or descriptor (e.g. "@daily")
+	Schedule string `validate:"required"`
+	// Timeout defines the maximum duration of a single run (default 1h)
+	Timeout time.Duration
+}
+
+// TaskStatus defines the schedule and last recorded run of a task (across all replicas)
+type TaskStatus struct {
+	LastError       *string    `json:"last_error,omitempty"`
+	LastFinishedOn  *time.Time `json:"last_finished_on"`
+	LastRunBy       *string    `json:"last_run_by"`
+	LastScheduledOn *time.Time `json:"last_scheduled_on"`
+	LastStartedOn   *time.Time `json:"last_started_on"`
+	LastStatus      *string    `json:"last_status"`
+	LastTraceID     *string    `json:"last_trace_id"`
+	Name            string     `json:"name"`
+	NextRunOn       time.Time  `json:"next_run_on"`
+	Schedule        string     `json:"schedule"`
+}
+
+// TaskSummary defines the public (e.g. health) status of a task, without run errors or replica details
+type TaskSummary struct {
+	LastFinishedOn  *time.Time `json:"last_finished_on"`
+	LastScheduledOn *time.Time `json:"last_scheduled_on"`
+	LastStartedOn   *time.Time `json:"last_started_on"`
+	LastStatus      *string    `json:"last_status"`
+	Name            string     `json:"name"`
+	NextRunOn       time.Time  `json:"next_run_on"`
+}
+
+// Summary returns the public status of the task
+func (ts TaskStatus) Summary() TaskSummary {
+	return TaskSummary{
+		LastFinishedOn:  ts.LastFinishedOn,
+		LastScheduledOn: ts.LastScheduledOn,
+		LastStartedOn:   ts.LastStartedOn,
+		LastStatus:      ts.LastStatus,
+		Name:            ts.Name,
+		NextRunOn:       ts.NextRunOn,
+	}
+}
+
+// SchedulerConfig defines the input to NewScheduler
+type SchedulerConfig struct {
+	DBClient *pgxpool.Pool        `validate:"required"`
+	Logger   *logger.CustomLogger `validate:"required"`
+	Tasks    []Task               `validate:"dive"`
+}
+
+// Scheduler runs tasks on their cron schedules, using postgres advisory locks to elect a single
+// replica (leader) for each scheduled run
+type Scheduler struct {
+	db     *pgxpool.Pool
+	id     string
+	logger *logger.CustomLogger
+	status *statusCache
+	tasks  []*scheduledTask
+}
+
+// statusCache defines the last queried task status
+type statusCache struct {
+	err       error
+	mutex     sync.Mutex
+	queriedOn time.Time
+	status    []TaskStatus
+}
+
+// scheduledTask defines a task with its parsed schedule
+type scheduledTask struct {
+	Task
+	schedule cron.Schedule
+}
+
+// NewScheduler returns a new Scheduler instance
+func NewScheduler(c *SchedulerConfig) (*Scheduler, error) {
+	if err := app.Validator.Validate.Struct(c); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*scheduledTask, 0, len(c.Tasks))
+	for _, t := range c.Tasks {
+		schedule, err := cron.ParseStandard(t.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule '%s' for task '%s': %w", t.Schedule, t.Name, err)
+		}
+		if t.Timeout == 0 {
+			t.Timeout = time.Hour
+		}
+		tasks = append(tasks, &scheduledTask{Task: t, schedule: schedule})
+	}
+
+	host, _ := os.Hostname()
+
+	scheduler := &Scheduler{
+		db:     c.DBClient,
+		id:     fmt.Sprintf("%s:%d", host, os.Getpid()),
+		logger: c.Logger,
+		status: &statusCache{},
+		tasks:  tasks,
+	}
+
+	return scheduler, nil
+}
+
+// Run runs all tasks on their schedules until the given context is done, then waits for running tasks to complete
+func (s *Scheduler) Run(ctx context.Context) error {
+	s.logger.Log.Info(fmt.Sprintf("scheduler %s started (%d tasks)", s.id, len(s.tasks)))
+
+	var wg sync.WaitGroup
+	for _, t := range s.tasks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(ctx, t)
+		}()
+	}
+
+	wg.Wait()
+	s.logger.Log.Info(fmt.Sprintf("scheduler %s stopped", s.id))
+
+	return nil
+}
+
+// Status returns the status of all tasks, as last recorded by any replica
+func (s *Scheduler) Status(ctx context.Context) ([]TaskStatus, error) {
+	now := time.Now()
+
+	status := make([]TaskStatus, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		ts := TaskStatus{
+			Name:      t.Name,
+			NextRunOn: t.schedule.Next(now),
+			Schedule:  t.Schedule,
+		}
+
+		err := s.db.QueryRow(ctx,
+			`SELECT last_scheduled_on, last_started_on, last_finished_on, last_status, last_error, last_trace_id, last_run_by
+			FROM scheduled_task WHERE name = $1`,
+			t.Name,
+		).Scan(
+			&ts.LastScheduledOn,
+			&ts.LastStartedOn,
+			&ts.LastFinishedOn,
+			&ts.LastStatus,
+			&ts.LastError,
+			&ts.LastTraceID,
+			&ts.LastRunBy,
+		)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, err
+		}
+
+		status = append(status, ts)
+	}
+
+	return status, nil
+}
+
+// CachedStatus returns the status of all tasks (see Status), querying at most once per statusTTL, so that frequent
+// callers (e.g. health probes) share a single query
+func (s *Scheduler) CachedStatus(ctx context.Context) ([]TaskStatus, error) {
+	c := s.status
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.queriedOn.IsZero() || time.Since(c.queriedOn) >= statusTTL {
+		c.status, c.err = s.Status(ctx)
+		c.queriedOn = time.Now()
+	}
+
+	return c.status, c.err
+}
+
+// loop runs the given task at each scheduled time until the given context is done
+func (s *Scheduler) loop(ctx context.Context, t *scheduledTask) {
+	for {
+		next := t.schedule.Next(time.Now())
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.execute(context.WithoutCancel(ctx), t, next); err != nil {
+			s.logger.Log.Error(fmt.Sprintf("task %s error: %s", t.Name, err.Error()))
+		}
+	}
+}
+
+// execute runs a single scheduled run of the task, if this replica wins the task lock and the run has
+// not already been claimed by another replica
+func (s *Scheduler) execute(ctx context.Context, t *scheduledTask, scheduled time.Time) error {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	id := database.AdvisoryLockID("scheduled_task:" + t.Name)
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", id).Scan(&locked); err != nil {
+		return fmt.Errorf("task lock error: %w", err)
+	}
+	if !locked {
+		s.logger.Log.Debug(fmt.Sprintf("task %s skipped (locked by another replica)", t.Name))
+		return nil
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", id); err != nil {
+			s.logger.Log.Error(fmt.Sprintf("task unlock error: %s", err.Error()))
+		}
+	}()
+
+	traceID := uuid.New().String()
+	ctx = trace.CreateOpContext(ctx, traceID)
+	log := s.logger.CreateContextLogger(traceID).With(slog.String("task", t.Name))
+
+	// claim the scheduled run, unless another replica (with a skewed clock) has already run it
+	tag, err := conn.Exec(ctx,
+		`INSERT INTO scheduled_task AS t (name, last_scheduled_on, last_started_on, last_status, last_trace_id, last_run_by)
+		VALUES ($1, $2, now(), $3, $4, $5)
+		ON CONFLICT (name) DO UPDATE SET
+			last_scheduled_on = EXCLUDED.last_scheduled_on,
+			last_started_on = EXCLUDED.last_started_on,
+			last_finished_on = NULL,
+			last_status = EXCLUDED.last_status,
+			last_error = NULL,
+			last_trace_id = EXCLUDED.last_trace_id,
+			last_run_by = EXCLUDED.last_run_by
+		WHERE t.last_scheduled_on < EXCLUDED.last_scheduled_on`,
+		t.Name, scheduled, StatusRunning, traceID, s.id,
+	)
+	if err != nil {
+		return fmt.Errorf("task claim error: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		log.Debug("task skipped (already run)")
+		return nil
+	}
+
+	log.Info("task started")
+	start := time.Now()
+
+	status, lastError := StatusSucceeded, (*string)(nil)
+	if err := s.call(ctx, t); err != nil {
+		msg := err.Error()
+		status, lastError = StatusFailed, &msg
+		log.Error(fmt.Sprintf("task failed: %s", msg), slog.Duration("duration", time.Since(start)))
+	} else {
+		log.Info("task succeeded", slog.Duration("duration", time.Since(start)))
+	}
+
+	if _, err := conn.Exec(ctx,
+		"UPDATE scheduled_task SET last_finished_on = now(), last_status = $2, last_error = $3 WHERE name = $1",
+		t.Name, status, lastError,
+	); err != nil {
+		return fmt.Errorf("task status error: %w", err)
+	}
+
+	return nil
+}
+
+// call runs the task with its timeout, recovering task panics as errors
+func (s *Scheduler) call(ctx context.Context, t *scheduledTask) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("task panic: %v", rec)
+		}
+	}()
+
+	return t.Run(ctx)
+}
@@ -0,0 +1,215 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/invopop/validation"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+)
+
+// indexPattern matches collection indexes and map keys within validator namespaces (e.g. items[0])
+var indexPattern = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// requestValidator reports field names by `json` struct tag (falling back to the go field name), so that validation
+// errors can be located within request bodies and query parameters. It is kept separate from app.Validator, whose
+// errors (e.g. of config and constructor checks) report go field names
+var requestValidator = newRequestValidator()
+
+// newRequestValidator returns a validator reporting field names by `json` struct tag
+func newRequestValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	return v
+}
+
+// Validator defines a type with custom validation (e.g. using invopop/validation rules)
+type Validator interface {
+	Validate() error
+}
+
+// Location locates an invalid field by its json field path (e.g. [data attributes title])
+type Location func(path []string) cerror.FieldError
+
+// Pointer locates invalid fields by JSON pointer, relative to the given root pointer (e.g. /data/attributes)
+func Pointer(root string) Location {
+	return func(path []string) cerror.FieldError {
+		return cerror.FieldError{Pointer: root + "/" + strings.Join(path, "/")}
+	}
+}
+
+// Parameter locates invalid fields by query parameter name, in bracket notation (e.g. page[limit])
+func Parameter(path []string) cerror.FieldError {
+	return cerror.FieldError{Parameter: ParameterName(path)}
+}
+
+// ParameterName returns the query parameter name of a field path, in bracket notation (e.g. page[limit])
+func ParameterName(path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	name := path[0]
+	for _, p := range path[1:] {
+		name += "[" + p + "]"
+	}
+	return name
+}
+
+// Struct validates s with its go-playground `validate` struct tags and then its Validate method (if implemented),
+// returning one field error per invalid field (tag errors take precedence)
+func Struct(s any, loc Location) cerror.FieldErrors {
+	errs := Errors(requestValidator.Struct(s), loc)
+	if v, ok := s.(Validator); ok {
+		errs = Merge(errs, Errors(v.Validate(), loc))
+	}
+	return errs
+}
+
+// Errors converts go-playground or invopop validation errors to located field errors. Other (non-nil) errors are
+// returned as a single field error without a location
+func Errors(err error, loc Location) cerror.FieldErrors {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		errs  cerror.FieldErrors
+		verrs validator.ValidationErrors
+		ierrs validation.Errors
+	)
+	switch {
+	case errors.As(err, &verrs):
+		for _, fe := range verrs {
+			ns := indexPattern.ReplaceAllString(trimRoot(fe.Namespace()), ".$1")
+			e := loc(strings.Split(ns, "."))
+			e.Code, e.Detail = describe(fe)
+			errs = append(errs, e)
+		}
+	case errors.As(err, &ierrs):
+		errs = invopopErrors(nil, ierrs, loc)
+	default:
+		errs = cerror.FieldErrors{{Detail: err.Error()}}
+	}
+
+	return errs
+}
+
+// trimRoot strips the validated struct type (the namespace root) from a go-playground namespace. The root of a
+// generic type includes its qualified type arguments (e.g. QueryData[example.SortEntry]), so dots within brackets
+// are skipped
+func trimRoot(ns string) string {
+	depth := 0
+	for i, r := range ns {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				return ns[i+1:]
+			}
+		}
+	}
+	return ns
+}
+
+// Merge returns the field errors of a, followed by those of b for fields not already invalid in a
+func Merge(a, b cerror.FieldErrors) cerror.FieldErrors {
+	for _, e := range b {
+		exists := slices.ContainsFunc(a, func(fe cerror.FieldError) bool {
+			return fe.Pointer == e.Pointer && fe.Parameter == e.Parameter && fe.Header == e.Header
+		})
+		if !exists {
+			a = append(a, e)
+		}
+	}
+	return a
+}
+
+// invopopErrors flattens (nested) invopop validation errors, sorted by field path
+func invopopErrors(path []string, ierrs validation.Errors, loc Location) cerror.FieldErrors {
+	keys := make([]string, 0, len(ierrs))
+	for key := range ierrs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var errs cerror.FieldErrors
+	for _, key := range keys {
+		fieldPath := append(slices.Clone(path), key)
+
+		var nested validation.Errors
+		if errors.As(ierrs[key], &nested) {
+			errs = append(errs, invopopErrors(fieldPath, nested, loc)...)
+			continue
+		}
+
+		e := loc(fieldPath)
+		e.Detail = ierrs[key].Error()
+		if ierr, ok := ierrs[key].(validation.Error); ok {
+			e.Code = ierr.Code()
+		}
+		errs = append(errs, e)
+	}
+
+	return errs
+}
+
+// describe returns the error code and a readable message for a go-playground field error (in the style of
+// invopop/validation codes and messages)
+func describe(fe validator.FieldError) (string, string) {
+	var (
+		param  = fe.Param()
+		length = fe.Kind() == reflect.String || fe.Kind() == reflect.Slice || fe.Kind() == reflect.Map
+	)
+
+	switch fe.Tag() {
+	case "required", "required_with", "required_without":
+		return "validation_required", "cannot be blank"
+	case "min", "gte":
+		if length {
+			return "validation_length_too_short", fmt.Sprintf("the length must be no less than %s", param)
+		}
+		return "validation_min_greater_equal_than_required", fmt.Sprintf("must be no less than %s", param)
+	case "max", "lte":
+		if length {
+			return "validation_length_too_long", fmt.Sprintf("the length must be no more than %s", param)
+		}
+		return "validation_max_less_equal_than_required", fmt.Sprintf("must be no greater than %s", param)
+	case "gt":
+		return "validation_min_greater_than_required", fmt.Sprintf("must be greater than %s", param)
+	case "lt":
+		return "validation_max_less_than_required", fmt.Sprintf("must be less than %s", param)
+	case "len":
+		if length {
+			return "validation_length_invalid", fmt.Sprintf("the length must be exactly %s", param)
+		}
+		return "validation_invalid", fmt.Sprintf("must be exactly %s", param)
+	case "oneof":
+		return "validation_in_invalid", fmt.Sprintf("must be one of [%s]", strings.Join(strings.Fields(param), ", "))
+	case "uuid", "uuid4":
+		return "validation_is_uuid", "must be a valid UUID"
+	case "email":
+		return "validation_is_email", "must be a valid email address"
+	case "url", "uri":
+		return "validation_is_url", "must be a valid URL"
+	default:
+		code := "validation_" + fe.Tag()
+		if param != "" {
+			return code, fmt.Sprintf("failed the '%s=%s' validation", fe.Tag(), param)
+		}
+		return code, fmt.Sprintf("failed the '%s' validation", fe.Tag())
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	fake "github.com/brianvoe/gofakeit/v6"
 	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/database"
 	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
 	"github.com/jasonsites/gosk/internal/modules/example"
 )
@@ -71,3 +72,21 @@ func ExampleEntityRecord(input *example.ExampleEntity, id *uuid.UUID) *example.E
 
 	return record
 }
+
+// ExampleSeedRecord returns the given entity as a seed record for loading with database.Seeder
+func ExampleSeedRecord(e *example.ExampleEntity) database.SeedRecord {
+	record := database.SeedRecord{
+		"created_context": e.CreatedContext,
+		"description":     e.Description,
+		"title":           e.Title,
+	}
+
+	if e.ID != uuid.Nil {
+		record["id"] = e.ID
+	}
+	if e.Status != "" {
+		record["status"] = string(e.Status)
+	}
+
+	return record
+}
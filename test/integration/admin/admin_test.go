@@ -0,0 +1,91 @@
+package admintest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/config"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+type AdminSetup struct {
+	Name        string
+	Description string
+	APIKey      string
+	Expected    int
+}
+
+// authenticate verifies API keys with a "valid-" (client) or "admin-" (admin) prefix, setting the verified client
+// identity
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		switch {
+		case strings.HasPrefix(key, "admin-"):
+			r = r.WithContext(mw.WithClientIdentity(r.Context(), mw.ClientIdentity{Admin: true, APIKey: key}))
+		case strings.HasPrefix(key, "valid-"):
+			r = r.WithContext(mw.WithClientIdentity(r.Context(), mw.ClientIdentity{APIKey: key}))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Test_AdminStatus verifies that the admin status route is only served to verified admin identities
+func Test_AdminStatus(t *testing.T) {
+	tests := []AdminSetup{
+		{
+			Name:        "unauthenticated",
+			Description: "fails (401) without a verified identity",
+			APIKey:      "unverified",
+			Expected:    http.StatusUnauthorized,
+		},
+		{
+			Name:        "client",
+			Description: "fails (403) with a verified identity without admin authorization",
+			APIKey:      "valid-client",
+			Expected:    http.StatusForbidden,
+		},
+		{
+			Name:        "admin",
+			Description: "succeeds (200) with a verified admin identity",
+			APIKey:      "admin-client",
+			Expected:    http.StatusOK,
+		},
+	}
+
+	conf, err := config.LoadConfiguration()
+	if err != nil {
+		t.Fatalf("configuration load error: %+v\n", err)
+	}
+	conf.HTTP.RateLimit.Enabled = false
+
+	r, err := utils.InitializeResolver(&resolver.Config{Authenticator: authenticate, Config: conf}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	handler := r.HTTPServer().Server.Handler
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			rd := &utils.RequestData{
+				Headers: map[string]string{"X-API-Key": tc.APIKey},
+				Method:  http.MethodGet,
+				Route:   "/domain/admin/status",
+			}
+			req, err := rd.SetRequestData(nil)
+			if err != nil {
+				t.Fatalf("http request error: %+v\n", err)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.Expected {
+				t.Errorf("expected '%d', actual '%d' (%s)", tc.Expected, rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package bodylimittest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+// createBody defines a JSON:API example create request body of 99 bytes
+const createBody = `{"data":{"type":"example","attributes":{"title":"Body Limit","description":"request body limits"}}}`
+
+type BodyLimitSetup struct {
+	Name        string
+	Description string
+	Default     int64
+	Routes      []config.BodyLimitRoute
+	// Stream defines whether the request body is sent without a Content-Length
+	Stream   bool
+	Expected utils.Expected
+}
+
+func Test_BodyLimit(t *testing.T) {
+	tests := []BodyLimitSetup{
+		{
+			Name:        "content_length",
+			Description: "fails (413) for request bodies with a Content-Length above the default limit",
+			Default:     64,
+			Expected:    utils.Expected{Code: http.StatusRequestEntityTooLarge},
+		},
+		{
+			Name:        "stream",
+			Description: "fails (413) for request bodies without a Content-Length read beyond the default limit",
+			Default:     64,
+			Stream:      true,
+			Expected:    utils.Expected{Code: http.StatusRequestEntityTooLarge},
+		},
+		{
+			Name:        "route",
+			Description: "fails (413) for request bodies above the route limit",
+			Default:     1 << 20,
+			Routes: []config.BodyLimitRoute{
+				{Method: http.MethodPost, Path: "/domain/examples", Limit: 64},
+			},
+			Expected: utils.Expected{Code: http.StatusRequestEntityTooLarge},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			conf, err := config.LoadConfiguration()
+			if err != nil {
+				t.Fatalf("configuration load error: %+v\n", err)
+			}
+			conf.HTTP.RateLimit.Enabled = false
+			conf.HTTP.Router.BodyLimit.Default = tc.Default
+			conf.HTTP.Router.BodyLimit.Routes = tc.Routes
+
+			r, err := utils.InitializeResolver(&resolver.Config{Config: conf}, resolver.HTTP)
+			if err != nil {
+				t.Fatalf("app initialization error: %+v\n", err)
+			}
+			handler := r.HTTPServer().Server.Handler
+
+			var body io.Reader = strings.NewReader(createBody)
+			if tc.Stream {
+				body = io.MultiReader(body)
+			}
+			rd := &utils.RequestData{Body: body, Method: http.MethodPost, Route: "/domain/examples"}
+			req, err := rd.SetRequestData(nil)
+			if err != nil {
+				t.Fatalf("http request error: %+v\n", err)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			if res.StatusCode != tc.Expected.Code {
+				t.Fatalf("expected '%d', actual '%d'", tc.Expected.Code, res.StatusCode)
+			}
+
+			var errBody jsonapi.ErrorResponse
+			if err := json.NewDecoder(res.Body).Decode(&errBody); err != nil {
+				t.Fatalf("response decode error: %+v\n", err)
+			}
+			if len(errBody.Errors) != 1 || errBody.Errors[0].Code != cerror.ErrorCode.PayloadTooLarge {
+				t.Errorf("expected '%s' error, actual '%+v'", cerror.ErrorCode.PayloadTooLarge, errBody.Errors)
+			}
+		})
+	}
+}
+
+func Test_BodyLimit_DebugLogging(t *testing.T) {
+	conf, err := config.LoadConfiguration()
+	if err != nil {
+		t.Fatalf("configuration load error: %+v\n", err)
+	}
+	conf.HTTP.RateLimit.Enabled = false
+	conf.Logger.Level = "debug"
+
+	r, err := utils.InitializeResolver(&resolver.Config{Config: conf}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	handler := r.HTTPServer().Server.Handler
+
+	// non-JSON and non-object request bodies are logged (not rejected by the request logger), and request bodies
+	// larger than the logged prefix are read by handlers in full
+	tests := []struct {
+		Name        string
+		Body        string
+		ContentType string
+		ErrorCode   string
+		Expected    utils.Expected
+	}{
+		{
+			Name:        "text",
+			Body:        "title=Body Limit&password=secret",
+			ContentType: "text/plain",
+			Expected:    utils.Expected{Code: http.StatusUnsupportedMediaType},
+		},
+		{
+			Name:        "array",
+			Body:        `[{"password":"secret"}]`,
+			ContentType: jsonapi.MediaType,
+			ErrorCode:   "validation_invalid_json",
+			Expected:    utils.Expected{Code: http.StatusBadRequest},
+		},
+		{
+			Name:        "truncated",
+			Body:        strings.Repeat(" ", 128<<10) + `{"data":{"type":"example","attributes":{"title":1}}}`,
+			ContentType: jsonapi.MediaType,
+			ErrorCode:   "validation_invalid_type",
+			Expected:    utils.Expected{Code: http.StatusBadRequest},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			rd := &utils.RequestData{
+				Body:    strings.NewReader(tc.Body),
+				Headers: map[string]string{"Content-Type": tc.ContentType},
+				Method:  http.MethodPost,
+				Route:   "/domain/examples",
+			}
+			req, err := rd.SetRequestData(&utils.RequestOptions{})
+			if err != nil {
+				t.Fatalf("http request error: %+v\n", err)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.Expected.Code {
+				t.Fatalf("expected '%d', actual '%d'", tc.Expected.Code, rec.Code)
+			}
+
+			if tc.ErrorCode != "" {
+				var errBody jsonapi.ErrorResponse
+				if err := json.NewDecoder(rec.Body).Decode(&errBody); err != nil {
+					t.Fatalf("response decode error: %+v\n", err)
+				}
+				if len(errBody.Errors) != 1 || errBody.Errors[0].Code != tc.ErrorCode {
+					t.Errorf("expected '%s' error, actual '%+v'", tc.ErrorCode, errBody.Errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package configtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/config"
+)
+
+type SecretSetup struct {
+	Name        string
+	Description string
+	Value       string
+	Env         map[string]string
+	Expected    SecretExpected
+}
+
+// SecretExpected defines the expected resolved secret, or the text contained by the resolution error
+type SecretExpected struct {
+	Secret string
+	Error  string
+}
+
+// Test_ResolveSecret verifies that secret references are resolved by their provider, and that all other values
+// (including escaped references) are resolved as literals
+func Test_ResolveSecret(t *testing.T) {
+	dir := t.TempDir()
+	secretFile := filepath.Join(dir, "db")
+	if err := os.WriteFile(secretFile, []byte("s3cr3t-file\n"), 0o600); err != nil {
+		t.Fatalf("secret file write error: %+v\n", err)
+	}
+
+	config.RegisterSecretProvider("vault", config.SecretProviderFunc(func(ctx context.Context, ref *url.URL) (string, error) {
+		return "vault:" + ref.Opaque, nil
+	}))
+
+	tests := []SecretSetup{
+		{
+			Name:        "literal",
+			Description: "resolves plain values as literals",
+			Value:       "s3cr3t",
+			Expected:    SecretExpected{Secret: "s3cr3t"},
+		},
+		{
+			Name:        "literal_scheme",
+			Description: "resolves scheme-prefixed values without braces as literals",
+			Value:       "env:GOSK_TEST_SECRET",
+			Env:         map[string]string{"GOSK_TEST_SECRET": "s3cr3t-env"},
+			Expected:    SecretExpected{Secret: "env:GOSK_TEST_SECRET"},
+		},
+		{
+			Name:        "literal_escaped",
+			Description: "resolves escaped references as literals",
+			Value:       "$${env:GOSK_TEST_SECRET}",
+			Env:         map[string]string{"GOSK_TEST_SECRET": "s3cr3t-env"},
+			Expected:    SecretExpected{Secret: "${env:GOSK_TEST_SECRET}"},
+		},
+		{
+			Name:        "env",
+			Description: "resolves environment references",
+			Value:       "${env:GOSK_TEST_SECRET}",
+			Env:         map[string]string{"GOSK_TEST_SECRET": "s3cr3t-env"},
+			Expected:    SecretExpected{Secret: "s3cr3t-env"},
+		},
+		{
+			Name:        "env_unset",
+			Description: "fails for unset environment references",
+			Value:       "${env:GOSK_TEST_UNSET}",
+			Expected:    SecretExpected{Error: `environment variable "GOSK_TEST_UNSET" not set`},
+		},
+		{
+			Name:        "file",
+			Description: "resolves file references, trimming the trailing newline",
+			Value:       "${file://" + secretFile + "}",
+			Expected:    SecretExpected{Secret: "s3cr3t-file"},
+		},
+		{
+			Name:        "file_missing",
+			Description: "fails for missing file references",
+			Value:       "${file://" + filepath.Join(dir, "missing") + "}",
+			Expected:    SecretExpected{Error: "file secret resolution error"},
+		},
+		{
+			Name:        "registered",
+			Description: "resolves references with registered providers",
+			Value:       "${vault:db/password}",
+			Expected:    SecretExpected{Secret: "vault:db/password"},
+		},
+		{
+			Name:        "unknown",
+			Description: "fails for references without a registered provider",
+			Value:       "${unknown:db}",
+			Expected:    SecretExpected{Error: `unknown secret provider "unknown"`},
+		},
+		{
+			Name:        "invalid",
+			Description: "fails for references without a scheme",
+			Value:       "${db}",
+			Expected:    SecretExpected{Error: "invalid secret reference"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			for k, v := range tc.Env {
+				t.Setenv(k, v)
+			}
+
+			secret, err := config.ResolveSecret(context.Background(), tc.Value)
+
+			if tc.Expected.Error != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.Expected.Error) {
+					t.Errorf("expected error '%s', actual '%v'", tc.Expected.Error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("secret resolution error: %+v\n", err)
+			}
+			if secret.Reveal() != tc.Expected.Secret {
+				t.Errorf("expected '%s', actual '%s'", tc.Expected.Secret, secret.Reveal())
+			}
+		})
+	}
+}
+
+// Test_Secret_Redaction verifies that secrets are redacted when printed, logged or marshaled
+func Test_Secret_Redaction(t *testing.T) {
+	type wrapper struct {
+		Password config.Secret
+	}
+	value := wrapper{Password: config.Secret("s3cr3t")}
+
+	var logged bytes.Buffer
+	slog.New(slog.NewJSONHandler(&logged, nil)).Info("secret", slog.Any("value", value.Password))
+	marshaled, err := json.Marshal(value)
+	if err != nil {
+		t.Fatalf("secret marshal error: %+v\n", err)
+	}
+
+	outputs := map[string]string{
+		"print":   fmt.Sprint(value.Password),
+		"print_v": fmt.Sprintf("%+v", value),
+		"print_#": fmt.Sprintf("%#v", value),
+		"log":     logged.String(),
+		"json":    string(marshaled),
+	}
+	for name, output := range outputs {
+		if strings.Contains(output, "s3cr3t") || !strings.Contains(output, config.Redacted) {
+			t.Errorf("expected %s output to be redacted, actual '%s'", name, output)
+		}
+	}
+
+	if config.Secret("").String() != "" {
+		t.Errorf("expected empty secret to print empty")
+	}
+}
@@ -0,0 +1,219 @@
+package crudtest
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/logger"
+	crud "github.com/jasonsites/gosk/internal/modules/common/crud"
+	query "github.com/jasonsites/gosk/internal/modules/common/models/query"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+// record defines a generic CRUD model over the example_entity table
+type record struct {
+	ID          uuid.UUID         `db:"id" json:"-"`
+	Title       string            `db:"title" json:"title" query:"sort,filter=ilike"`
+	Description *string           `db:"description" json:"description"`
+	Status      repo.RecordStatus `db:"status" json:"status" query:"filter"`
+	CreatedOn   time.Time         `db:"created_on" json:"created_on" query:"sort"`
+	ModifiedOn  time.Time         `db:"modified_on" json:"modified_on" query:"sort"`
+}
+
+// input defines a generic CRUD DTO over the example_entity table
+type input struct {
+	Description *string `db:"description" json:"description"`
+	Title       string  `db:"title" json:"title"`
+}
+
+func Test_CRUD(t *testing.T) {
+	r, err := utils.InitializeResolver(&resolver.Config{}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	if err := utils.Migrate(r); err != nil {
+		t.Fatalf("db migration error: %+v\n", err)
+	}
+	defer utils.Cleanup(r)
+
+	var (
+		entity = crud.EntityDefinition{ModifiedOn: "modified_on", Name: "example_entity", Type: "record"}
+		log    = &logger.CustomLogger{Level: logger.LevelError, Log: slog.Default()}
+	)
+
+	repository, err := crud.NewPGRepository[record, input](&crud.PGRepositoryConfig{
+		DBRouter: r.DatabaseRouter(),
+		Entity:   entity,
+		Logger:   log,
+	})
+	if err != nil {
+		t.Fatalf("repository initialization error: %+v\n", err)
+	}
+	service, err := crud.NewCRUDService(&crud.CRUDServiceConfig[record, input]{
+		Hooks: crud.Hooks[record, input]{
+			BeforeCreate: func(ctx context.Context, data *input) error {
+				if data.Title == "forbidden" {
+					return cerror.NewForbiddenError(nil, "forbidden title")
+				}
+				data.Title = strings.TrimSpace(data.Title)
+				return nil
+			},
+		},
+		Logger: log,
+		Repo:   repository,
+	})
+	if err != nil {
+		t.Fatalf("service initialization error: %+v\n", err)
+	}
+	queryHandler, err := crud.NewQueryHandler[record](&crud.QueryConfig{DefaultSort: "-created_on", PageLimit: 10})
+	if err != nil {
+		t.Fatalf("query handler initialization error: %+v\n", err)
+	}
+	ctrl, err := crud.NewCRUDController(&crud.CRUDControllerConfig[record, input]{
+		CSV:       true,
+		Entity:    entity,
+		Logger:    log,
+		Namespace: "test",
+		Path:      "records",
+		Query:     queryHandler,
+		Service:   service,
+	})
+	if err != nil {
+		t.Fatalf("controller initialization error: %+v\n", err)
+	}
+
+	mux := chi.NewRouter()
+	mux.Use(mw.ContentNegotiation(&mw.ContentNegotiationConfig{
+		Routes: []mw.MediaTypeRoute{{Method: http.MethodGet, Path: "/test/records", Produces: []string{"text/csv"}}},
+	}))
+	crud.Router(mux, "test", "records", ctrl)
+
+	serveWith := func(headers map[string]string, method, route, body string) *httptest.ResponseRecorder {
+		rd := &utils.RequestData{Headers: headers, Method: method, Route: route}
+		if body != "" {
+			rd.Body = strings.NewReader(body)
+		}
+		req, err := rd.SetRequestData(nil)
+		if err != nil {
+			t.Fatalf("http request error: %+v\n", err)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	serve := func(method, route, body string) *httptest.ResponseRecorder {
+		return serveWith(nil, method, route, body)
+	}
+
+	var ids []uuid.UUID
+	for _, title := range []string{"  Alpha Record ", "Beta Record", "Gamma"} {
+		rec := serve(http.MethodPost, "/test/records", fmt.Sprintf(`{"data":{"type":"record","attributes":{"title":%q}}}`, title))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("create: expected '%d', actual '%d' (%s)", http.StatusCreated, rec.Code, rec.Body.String())
+		}
+
+		body := struct {
+			Data jsonapi.ResponseResource `json:"data"`
+		}{}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("response decode error: %+v\n", err)
+		}
+		ids = append(ids, body.Data.ID)
+	}
+
+	t.Run("create hooks", func(t *testing.T) {
+		rec := serve(http.MethodGet, fmt.Sprintf("/test/records/%s", ids[0]), "")
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"title":"Alpha Record"`) {
+			t.Errorf("expected trimmed title, actual '%d' %s", rec.Code, rec.Body.String())
+		}
+
+		rec = serve(http.MethodPost, "/test/records", `{"data":{"type":"record","attributes":{"title":"forbidden"}}}`)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("expected '%d', actual '%d'", http.StatusForbidden, rec.Code)
+		}
+	})
+
+	t.Run("list filter and sort", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/test/records?filter[title]=record&filter[status]=active&sort=title", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected '%d', actual '%d' (%s)", http.StatusOK, rec.Code, rec.Body.String())
+		}
+
+		body := struct {
+			Meta struct {
+				Page query.PageMetadata `json:"page"`
+			} `json:"meta"`
+			Data []jsonapi.ResponseResource `json:"data"`
+		}{}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatalf("response decode error: %+v\n", err)
+		}
+		if body.Meta.Page.Total != 2 || len(body.Data) != 2 || body.Data[0].ID != ids[0] {
+			t.Errorf("expected 2 records sorted by title, actual total '%d' %+v", body.Meta.Page.Total, body.Data)
+		}
+	})
+
+	t.Run("list csv", func(t *testing.T) {
+		rec := serveWith(map[string]string{"Accept": "text/csv"}, http.MethodGet, "/test/records?filter[title]=record&sort=title", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected '%d', actual '%d' (%s)", http.StatusOK, rec.Code, rec.Body.String())
+		}
+		if mediaType := rec.Header().Get("Content-Type"); !strings.HasPrefix(mediaType, "text/csv") {
+			t.Errorf("expected media type 'text/csv', actual '%s'", mediaType)
+		}
+
+		rows, err := csv.NewReader(rec.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("csv decode error: %+v\n", err)
+		}
+		if len(rows) != 3 || rows[0][0] != "id" || rows[1][0] != ids[0].String() || rows[1][1] != "Alpha Record" {
+			t.Errorf("expected header and 2 records sorted by title, actual %v", rows)
+		}
+	})
+
+	t.Run("list invalid filter", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/test/records?filter[description]=x", "")
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("expected '%d', actual '%d'", http.StatusBadRequest, rec.Code)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		rec := serve(http.MethodPut, fmt.Sprintf("/test/records/%s", ids[2]), `{"data":{"type":"record","attributes":{"title":"Delta"}}}`)
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"title":"Delta"`) {
+			t.Errorf("expected updated title, actual '%d' %s", rec.Code, rec.Body.String())
+		}
+
+		rec = serve(http.MethodPut, fmt.Sprintf("/test/records/%s", uuid.New()), `{"data":{"type":"record","attributes":{"title":"Delta"}}}`)
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected '%d', actual '%d'", http.StatusNotFound, rec.Code)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		rec := serve(http.MethodDelete, fmt.Sprintf("/test/records/%s", ids[1]), "")
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("expected '%d', actual '%d'", http.StatusNoContent, rec.Code)
+		}
+
+		rec = serve(http.MethodGet, fmt.Sprintf("/test/records/%s", ids[1]), "")
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("expected '%d', actual '%d'", http.StatusNotFound, rec.Code)
+		}
+	})
+}
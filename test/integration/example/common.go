@@ -2,13 +2,15 @@ package exampletest
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/database"
 	"github.com/jasonsites/gosk/internal/modules/example"
 	"github.com/jasonsites/gosk/internal/resolver"
+	fx "github.com/jasonsites/gosk/test/fixtures"
 	utils "github.com/jasonsites/gosk/test/testutils"
 )
 
@@ -18,6 +20,7 @@ type Suite struct {
 	Method      string
 	Resolver    *resolver.Resolver
 	RoutePrefix string
+	Seeder      *database.Seeder
 }
 
 func (s *Suite) SetupSuite(tb testing.TB) func(tb testing.TB) {
@@ -27,11 +30,16 @@ func (s *Suite) SetupSuite(tb testing.TB) func(tb testing.TB) {
 		tb.Fatalf("app initialization error: %+v\n", err)
 	}
 
+	if err := utils.Migrate(resolver); err != nil {
+		tb.Fatalf("db migration error: %+v\n", err)
+	}
+
 	s.DB = resolver.PostgreSQLClient()
 	s.Handler = resolver.HTTPServer().Server.Handler
 	s.Method = http.MethodPost
 	s.Resolver = resolver
 	s.RoutePrefix = "/domain/examples"
+	s.Seeder = resolver.Seeder()
 
 	return func(tb testing.TB) {
 		// teardown for test table
@@ -47,34 +55,41 @@ func (s *Suite) SetupTest(tb testing.TB) func(tb testing.TB) {
 }
 
 // insertExampleRecord inserts a db record for use in test setup
-func insertExampleRecord(e *example.ExampleEntity, db *pgxpool.Pool) (*example.ExampleEntity, error) {
-	var (
-		statement    = "INSERT INTO %s %s VALUES %s RETURNING id"
-		name         = "example_entity"
-		insertFields = "(title,description,created_context)"
-		values       = "($1,$2,$3)"
-		query        = fmt.Sprintf(statement, name, insertFields, values)
-	)
-
-	var (
-		title          = e.Title
-		description    = e.Description.String
-		createdContext = e.CreatedContext
-	)
+func insertExampleRecord(e *example.ExampleEntity, seeder *database.Seeder) (*example.ExampleEntity, error) {
+	entity := *e
+	if entity.ID == uuid.Nil {
+		entity.ID = uuid.New()
+	}
 
-	// create new entity for db row scan and execute query
-	entity := &example.ExampleEntity{}
-	if err := db.QueryRow(
-		context.Background(),
-		query,
-		title,
-		description,
-		createdContext,
-	).Scan(
-		&entity.ID,
-	); err != nil {
+	set := database.SeedSet{
+		Records: []database.SeedRecord{fx.ExampleSeedRecord(&entity)},
+		Table:   example.ExampleSeedTable().Name,
+	}
+	if err := seeder.Load(context.Background(), &database.SeedOptions{}, set); err != nil {
 		return nil, err
 	}
 
-	return entity, nil
+	return &entity, nil
+}
+
+// insertTagRecord inserts a tag db record (related to the given examples) for use in test setup
+func insertTagRecord(name string, seeder *database.Seeder, exampleIDs ...uuid.UUID) (uuid.UUID, error) {
+	id := uuid.New()
+
+	sets := []database.SeedSet{{
+		Records: []database.SeedRecord{{"id": id, "name": name}},
+		Table:   example.TagSeedTable().Name,
+	}}
+	if len(exampleIDs) > 0 {
+		join := database.SeedSet{Table: example.ExampleTagSeedTable().Name}
+		for _, exampleID := range exampleIDs {
+			join.Records = append(join.Records, database.SeedRecord{"example_id": exampleID, "tag_id": id})
+		}
+		sets = append(sets, join)
+	}
+	if err := seeder.Load(context.Background(), &database.SeedOptions{}, sets...); err != nil {
+		return uuid.Nil, err
+	}
+
+	return id, nil
 }
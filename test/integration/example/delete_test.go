@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/google/uuid"
 	fx "github.com/jasonsites/gosk/test/fixtures"
 	utils "github.com/jasonsites/gosk/test/testutils"
 )
@@ -13,7 +14,9 @@ import (
 type DeleteSetup struct {
 	Name        string
 	Description string
-	Expected    utils.Expected
+	// Missing deletes a resource which does not exist
+	Missing  bool
+	Expected utils.Expected
 }
 
 func Test_Example_Delete(t *testing.T) {
@@ -27,6 +30,12 @@ func Test_Example_Delete(t *testing.T) {
 			Description: "succeeds (204)",
 			Expected:    utils.Expected{Code: http.StatusNoContent},
 		},
+		{
+			Name:        "not_found",
+			Description: "fails (404) when the resource does not exist",
+			Missing:     true,
+			Expected:    utils.Expected{Code: http.StatusNotFound},
+		},
 	}
 
 	for _, tc := range tests {
@@ -38,14 +47,19 @@ func Test_Example_Delete(t *testing.T) {
 			defer teardownTest(t)
 
 			entity := fx.ExampleEntityRecord(nil, nil)
-			record, err := insertExampleRecord(entity, s.DB)
+			record, err := insertExampleRecord(entity, s.Seeder)
 			if err != nil {
 				t.Fatalf("db insert error: %+v\n", err)
 			}
 
+			id := record.ID
+			if tc.Missing {
+				id = uuid.New()
+			}
+
 			rd := &utils.RequestData{
 				Method: http.MethodDelete,
-				Route:  fmt.Sprintf("%s/%s", s.RoutePrefix, record.ID.String()),
+				Route:  fmt.Sprintf("%s/%s", s.RoutePrefix, id.String()),
 			}
 
 			req, err := rd.SetRequestData(nil)
@@ -0,0 +1,111 @@
+package exampletest
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/internal/http/export"
+	"github.com/jasonsites/gosk/internal/modules/example"
+	fx "github.com/jasonsites/gosk/test/fixtures"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+func Test_Example_Export(t *testing.T) {
+	s := Suite{}
+	teardownSuite := s.SetupSuite(t)
+	defer teardownSuite(t)
+
+	teardownTest := s.SetupTest(t)
+	defer teardownTest(t)
+
+	titles := []string{"Export Alpha", "Export Bravo", "Export Charlie"}
+	for _, title := range titles {
+		entity := fx.ExampleEntityRecord(&example.ExampleEntity{Title: title}, nil)
+		if _, err := insertExampleRecord(entity, s.Seeder); err != nil {
+			t.Fatalf("db insert error: %+v\n", err)
+		}
+	}
+
+	serve := func(t *testing.T, accept, query string) *http.Response {
+		rd := &utils.RequestData{
+			Method:  http.MethodGet,
+			Route:   s.RoutePrefix + "?sort=title&filter[title]=export" + query,
+			Headers: map[string]string{"Accept": accept},
+		}
+
+		req, err := rd.SetRequestData(nil)
+		if err != nil {
+			t.Fatalf("http request error: %+v\n", err)
+		}
+
+		rec := httptest.NewRecorder()
+		s.Handler.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		res := serve(t, export.MediaTypeCSV, "&page[limit]=1")
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("expected '%d', actual '%d'", http.StatusOK, res.StatusCode)
+		}
+		if mediaType := res.Header.Get("Content-Type"); !strings.HasPrefix(mediaType, export.MediaTypeCSV) {
+			t.Errorf("expected media type '%s', actual '%s'", export.MediaTypeCSV, mediaType)
+		}
+
+		records, err := csv.NewReader(res.Body).ReadAll()
+		if err != nil {
+			t.Fatalf("csv decode error: %+v\n", err)
+		}
+		if expected := append([]string{"id"}, example.ExportFields...); !slices.Equal(records[0], expected) {
+			t.Errorf("expected header row %v, actual %v", expected, records[0])
+		}
+		// paging is ignored by exports
+		if len(records) != len(titles)+1 {
+			t.Fatalf("expected %d rows, actual %d", len(titles), len(records)-1)
+		}
+		for i, title := range titles {
+			if records[i+1][1] != title {
+				t.Errorf("expected title '%s', actual '%s'", title, records[i+1][1])
+			}
+		}
+	})
+
+	t.Run("ndjson_fields", func(t *testing.T) {
+		res := serve(t, export.MediaTypeNDJSON, "&fields[example]=title")
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("expected '%d', actual '%d'", http.StatusOK, res.StatusCode)
+		}
+
+		var rows []map[string]any
+		scanner := bufio.NewScanner(res.Body)
+		for scanner.Scan() {
+			row := map[string]any{}
+			if err := json.Unmarshal(scanner.Bytes(), &row); err != nil {
+				t.Fatalf("ndjson decode error: %+v\n", err)
+			}
+			rows = append(rows, row)
+		}
+
+		if len(rows) != len(titles) {
+			t.Fatalf("expected %d rows, actual %d", len(titles), len(rows))
+		}
+		for i, row := range rows {
+			if len(row) != 2 || row["id"] == nil || row["title"] != titles[i] {
+				t.Errorf("expected id and title '%s' only, actual %+v", titles[i], row)
+			}
+		}
+	})
+
+	t.Run("unsupported_field", func(t *testing.T) {
+		res := serve(t, export.MediaTypeCSV, "&fields[example]=secret")
+		if res.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected '%d', actual '%d'", http.StatusBadRequest, res.StatusCode)
+		}
+	})
+}
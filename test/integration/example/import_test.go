@@ -0,0 +1,209 @@
+package exampletest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jasonsites/gosk/internal/http/export"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/jobs"
+	"github.com/jasonsites/gosk/internal/modules/example"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+// importCSV defines a CSV import body with 3 valid rows and 1 invalid row (row 2, title too short)
+const importCSV = "title,description\nImport Alpha,first row\nx,\nImport Bravo,\nImport Charlie,third row\n"
+
+// importResponse defines an example import response body
+type importResponse struct {
+	Data struct {
+		ID         string                   `json:"id"`
+		Attributes example.ImportAttributes `json:"attributes"`
+		Links      *jsonapi.Links           `json:"links"`
+	} `json:"data"`
+}
+
+func Test_Example_Import(t *testing.T) {
+	s := Suite{}
+	teardownSuite := s.SetupSuite(t)
+	defer teardownSuite(t)
+
+	serve := func(t *testing.T, mediaType, query string, body io.Reader) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, s.RoutePrefix+"/import"+query, body)
+		req.Header.Set("Content-Type", mediaType)
+
+		rec := httptest.NewRecorder()
+		s.Handler.ServeHTTP(rec, req)
+		return rec.Result()
+	}
+
+	count := func(t *testing.T) int {
+		var n int
+		if err := s.DB.QueryRow(context.Background(), "SELECT COUNT(*) FROM example_entity").Scan(&n); err != nil {
+			t.Fatalf("db count error: %+v\n", err)
+		}
+		return n
+	}
+
+	t.Run("partial", func(t *testing.T) {
+		teardownTest := s.SetupTest(t)
+		defer teardownTest(t)
+
+		res := serve(t, export.MediaTypeCSV, "?mode=partial", strings.NewReader(importCSV))
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("expected '%d', actual '%d'", http.StatusOK, res.StatusCode)
+		}
+
+		body := &importResponse{}
+		if err := json.NewDecoder(res.Body).Decode(body); err != nil {
+			t.Fatalf("response decode error: %+v\n", err)
+		}
+		report := body.Data.Attributes.Report
+		if report == nil || report.Total != 4 || report.Imported != 3 || report.Failed != 1 {
+			t.Fatalf("expected 3 of 4 rows imported, actual %+v", report)
+		}
+		if e := report.Errors[0]; e.Row != 2 || e.Field != "title" {
+			t.Errorf("expected row 2 title error, actual %+v", e)
+		}
+		if requestID := res.Header.Get("X-Request-Id"); body.Data.ID != requestID {
+			t.Errorf("expected report id '%s' (request trace id), actual '%s'", requestID, body.Data.ID)
+		}
+		if n := count(t); n != 3 {
+			t.Errorf("expected 3 examples, actual %d", n)
+		}
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		teardownTest := s.SetupTest(t)
+		defer teardownTest(t)
+
+		res := serve(t, export.MediaTypeCSV, "", strings.NewReader(importCSV))
+		if res.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected '%d', actual '%d'", http.StatusBadRequest, res.StatusCode)
+		}
+
+		body := &jsonapi.ErrorResponse{}
+		if err := json.NewDecoder(res.Body).Decode(body); err != nil {
+			t.Fatalf("response decode error: %+v\n", err)
+		}
+		if len(body.Errors) != 1 || body.Errors[0].Source == nil || body.Errors[0].Source.Pointer != "/2/title" {
+			t.Errorf("expected a single error located by '/2/title', actual %+v", body.Errors)
+		}
+		if n := count(t); n != 0 {
+			t.Errorf("expected no examples, actual %d", n)
+		}
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		teardownTest := s.SetupTest(t)
+		defer teardownTest(t)
+
+		ndjson := `{"title":"Import Alpha"}` + "\n" + `{"title":"Import Bravo","description":"second row"}` + "\n"
+		res := serve(t, export.MediaTypeNDJSON, "", strings.NewReader(ndjson))
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("expected '%d', actual '%d'", http.StatusOK, res.StatusCode)
+		}
+		if n := count(t); n != 2 {
+			t.Errorf("expected 2 examples, actual %d", n)
+		}
+	})
+
+	t.Run("async", func(t *testing.T) {
+		teardownTest := s.SetupTest(t)
+		defer teardownTest(t)
+
+		// request bodies without a Content-Length are imported asynchronously
+		res := serve(t, export.MediaTypeCSV, "?mode=partial", io.MultiReader(strings.NewReader(importCSV)))
+		if res.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected '%d', actual '%d'", http.StatusAccepted, res.StatusCode)
+		}
+		location := res.Header.Get("Location")
+		if location == "" {
+			t.Fatal("expected Location header")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		go s.Resolver.Worker().Run(ctx)
+
+		body := &importResponse{}
+		for ctx.Err() == nil {
+			rd := &utils.RequestData{Method: http.MethodGet, Route: location}
+			req, err := rd.SetRequestData(nil)
+			if err != nil {
+				t.Fatalf("http request error: %+v\n", err)
+			}
+			rec := httptest.NewRecorder()
+			s.Handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected '%d', actual '%d'", http.StatusOK, rec.Code)
+			}
+			if err := json.NewDecoder(rec.Body).Decode(body); err != nil {
+				t.Fatalf("response decode error: %+v\n", err)
+			}
+			if body.Data.Attributes.Status == jobs.StatusSucceeded || body.Data.Attributes.Status == jobs.StatusDead {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if report := body.Data.Attributes.Report; body.Data.Attributes.Status != jobs.StatusSucceeded ||
+			report == nil || report.Imported != 3 {
+			t.Fatalf("expected succeeded import of 3 rows, actual %+v", body.Data.Attributes)
+		}
+		if n := count(t); n != 3 {
+			t.Errorf("expected 3 examples, actual %d", n)
+		}
+	})
+
+	t.Run("async_chunks", func(t *testing.T) {
+		teardownTest := s.SetupTest(t)
+		defer teardownTest(t)
+
+		// import files larger than a chunk are stored in multiple chunks, and read back in sequence
+		var csv strings.Builder
+		csv.WriteString("title,description\n")
+		rows := 0
+		for ; csv.Len() <= 1<<20; rows++ {
+			fmt.Fprintf(&csv, "Import %06d,chunked import row\n", rows)
+		}
+
+		res := serve(t, export.MediaTypeCSV, "", io.MultiReader(strings.NewReader(csv.String())))
+		if res.StatusCode != http.StatusAccepted {
+			t.Fatalf("expected '%d', actual '%d'", http.StatusAccepted, res.StatusCode)
+		}
+
+		var chunks int
+		if err := s.DB.QueryRow(context.Background(), "SELECT COUNT(*) FROM example_import_chunk").Scan(&chunks); err != nil {
+			t.Fatalf("db count error: %+v\n", err)
+		}
+		if chunks != 2 {
+			t.Errorf("expected 2 stored chunks, actual %d", chunks)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		go s.Resolver.Worker().Run(ctx)
+
+		for ctx.Err() == nil && count(t) != rows {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if n := count(t); n != rows {
+			t.Errorf("expected %d examples, actual %d", rows, n)
+		}
+	})
+
+	t.Run("unsupported_media_type", func(t *testing.T) {
+		res := serve(t, "text/plain", "", strings.NewReader("title\nImport Alpha\n"))
+		if res.StatusCode != http.StatusUnsupportedMediaType {
+			t.Errorf("expected '%d', actual '%d'", http.StatusUnsupportedMediaType, res.StatusCode)
+		}
+	})
+}
@@ -1,10 +1,15 @@
 package exampletest
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	query "github.com/jasonsites/gosk/internal/modules/common/models/query"
+	"github.com/jasonsites/gosk/internal/modules/example"
+	fx "github.com/jasonsites/gosk/test/fixtures"
 	utils "github.com/jasonsites/gosk/test/testutils"
 )
 
@@ -61,3 +66,57 @@ func Test_Example_List(t *testing.T) {
 		})
 	}
 }
+
+func Test_Example_List_Search(t *testing.T) {
+	s := Suite{}
+	teardownSuite := s.SetupSuite(t)
+	defer teardownSuite(t)
+
+	teardownTest := s.SetupTest(t)
+	defer teardownTest(t)
+
+	for _, title := range []string{"Lighthouse Keeper", "Harbor Pilot", "Lighthouse Engineer"} {
+		entity := fx.ExampleEntityRecord(&example.ExampleEntity{Title: title}, nil)
+		if _, err := insertExampleRecord(entity, s.Seeder); err != nil {
+			t.Fatalf("db insert error: %+v\n", err)
+		}
+	}
+
+	rd := &utils.RequestData{
+		Method: http.MethodGet,
+		Route:  s.RoutePrefix + "?q=lighthouse",
+	}
+
+	req, err := rd.SetRequestData(nil)
+	if err != nil {
+		t.Fatalf("http request error: %+v\n", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, req)
+
+	res := rec.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected '%d', actual '%d'", http.StatusOK, res.StatusCode)
+	}
+
+	body := struct {
+		Meta struct {
+			Page  query.PageMetadata `json:"page"`
+			Query string             `json:"q"`
+		} `json:"meta"`
+		Data []jsonapi.ResponseResource `json:"data"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("response decode error: %+v\n", err)
+	}
+
+	if body.Meta.Page.Total != 2 || len(body.Data) != 2 {
+		t.Errorf("expected 2 search results, actual total '%d' (%d resources)", body.Meta.Page.Total, len(body.Data))
+	}
+	for _, resource := range body.Data {
+		if resource.Meta == nil || resource.Meta.Search == nil || resource.Meta.Search.Highlights["title"] == "" {
+			t.Errorf("expected title highlight in resource meta, actual %+v", resource.Meta)
+		}
+	}
+}
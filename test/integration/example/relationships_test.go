@@ -0,0 +1,227 @@
+package exampletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	fx "github.com/jasonsites/gosk/test/fixtures"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+func Test_Example_Include(t *testing.T) {
+	s := Suite{}
+	teardownSuite := s.SetupSuite(t)
+	defer teardownSuite(t)
+
+	teardownTest := s.SetupTest(t)
+	defer teardownTest(t)
+
+	var ids []uuid.UUID
+	for range 2 {
+		record, err := insertExampleRecord(fx.ExampleEntityRecord(nil, nil), s.Seeder)
+		if err != nil {
+			t.Fatalf("db insert error: %+v\n", err)
+		}
+		ids = append(ids, record.ID)
+	}
+	// a single tag related to both examples is included once
+	tagID, err := insertTagRecord("shared", s.Seeder, ids...)
+	if err != nil {
+		t.Fatalf("db insert error: %+v\n", err)
+	}
+
+	rd := &utils.RequestData{
+		Method: http.MethodGet,
+		Route:  s.RoutePrefix + "?include=tags",
+	}
+
+	req, err := rd.SetRequestData(nil)
+	if err != nil {
+		t.Fatalf("http request error: %+v\n", err)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, req)
+
+	res := rec.Result()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected '%d', actual '%d'", http.StatusOK, res.StatusCode)
+	}
+
+	body := struct {
+		Data     []jsonapi.ResponseResource `json:"data"`
+		Included []jsonapi.ResponseResource `json:"included"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		t.Fatalf("response decode error: %+v\n", err)
+	}
+
+	if len(body.Included) != 1 || body.Included[0].ID != tagID {
+		t.Errorf("expected single included tag '%s', actual %+v", tagID, body.Included)
+	}
+	for _, resource := range body.Data {
+		var linkage []jsonapi.ResourceIdentifier
+		if err := json.Unmarshal(resource.Relationships["tags"].Data, &linkage); err != nil {
+			t.Fatalf("relationship linkage decode error: %+v\n", err)
+		}
+		if len(linkage) != 1 || linkage[0].ID != tagID {
+			t.Errorf("expected tag linkage '%s', actual %+v", tagID, linkage)
+		}
+	}
+}
+
+type IncludeSetup struct {
+	Name        string
+	Description string
+	Include     string
+	Expected    utils.Expected
+}
+
+func Test_Example_Include_Invalid(t *testing.T) {
+	s := Suite{}
+	teardownSuite := s.SetupSuite(t)
+	defer teardownSuite(t)
+
+	tests := []IncludeSetup{
+		{
+			Name:        "unsupported",
+			Description: "fails (400) with unsupported include path",
+			Include:     "author",
+			Expected:    utils.Expected{Code: http.StatusBadRequest},
+		},
+		{
+			Name:        "depth",
+			Description: "fails (400) with include path exceeding the maximum depth",
+			Include:     "tags.a.b.c",
+			Expected:    utils.Expected{Code: http.StatusBadRequest},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Parallel()
+
+			rd := &utils.RequestData{
+				Method: http.MethodGet,
+				Route:  fmt.Sprintf("%s?include=%s", s.RoutePrefix, tc.Include),
+			}
+
+			req, err := rd.SetRequestData(nil)
+			if err != nil {
+				t.Fatalf("http request error: %+v\n", err)
+			}
+
+			rec := httptest.NewRecorder()
+			s.Handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			if res.StatusCode != tc.Expected.Code {
+				t.Errorf("expected '%d', actual '%d'", tc.Expected.Code, res.StatusCode)
+			}
+		})
+	}
+}
+
+func Test_Example_Relationships_Tags(t *testing.T) {
+	s := Suite{}
+	teardownSuite := s.SetupSuite(t)
+	defer teardownSuite(t)
+
+	teardownTest := s.SetupTest(t)
+	defer teardownTest(t)
+
+	record, err := insertExampleRecord(fx.ExampleEntityRecord(nil, nil), s.Seeder)
+	if err != nil {
+		t.Fatalf("db insert error: %+v\n", err)
+	}
+	first, err := insertTagRecord("first", s.Seeder)
+	if err != nil {
+		t.Fatalf("db insert error: %+v\n", err)
+	}
+	second, err := insertTagRecord("second", s.Seeder)
+	if err != nil {
+		t.Fatalf("db insert error: %+v\n", err)
+	}
+
+	route := fmt.Sprintf("%s/%s/relationships/tags", s.RoutePrefix, record.ID)
+	linkage := func(ids ...uuid.UUID) string {
+		data := make([]string, 0, len(ids))
+		for _, id := range ids {
+			data = append(data, fmt.Sprintf(`{"type":"tag","id":"%s"}`, id))
+		}
+		return fmt.Sprintf(`{"data":[%s]}`, strings.Join(data, ","))
+	}
+
+	steps := []struct {
+		Method   string
+		Body     string
+		Code     int
+		Expected []uuid.UUID
+	}{
+		{Method: http.MethodPost, Body: linkage(first, second), Code: http.StatusNoContent, Expected: []uuid.UUID{first, second}},
+		{Method: http.MethodDelete, Body: linkage(first), Code: http.StatusNoContent, Expected: []uuid.UUID{second}},
+		{Method: http.MethodPatch, Body: linkage(first), Code: http.StatusNoContent, Expected: []uuid.UUID{first}},
+		{Method: http.MethodPost, Body: linkage(uuid.New()), Code: http.StatusNotFound, Expected: []uuid.UUID{first}},
+		{Method: http.MethodPatch, Body: linkage(), Code: http.StatusNoContent, Expected: []uuid.UUID{}},
+	}
+
+	for i, step := range steps {
+		rd := &utils.RequestData{
+			Body:   strings.NewReader(step.Body),
+			Method: step.Method,
+			Route:  route,
+		}
+
+		req, err := rd.SetRequestData(nil)
+		if err != nil {
+			t.Fatalf("http request error: %+v\n", err)
+		}
+
+		rec := httptest.NewRecorder()
+		s.Handler.ServeHTTP(rec, req)
+
+		if rec.Code != step.Code {
+			t.Fatalf("step %d (%s): expected '%d', actual '%d'", i, step.Method, step.Code, rec.Code)
+		}
+
+		rd = &utils.RequestData{Method: http.MethodGet, Route: route}
+		req, err = rd.SetRequestData(nil)
+		if err != nil {
+			t.Fatalf("http request error: %+v\n", err)
+		}
+
+		rec = httptest.NewRecorder()
+		s.Handler.ServeHTTP(rec, req)
+
+		body := struct {
+			Data []jsonapi.ResourceIdentifier `json:"data"`
+		}{}
+		if err := json.NewDecoder(rec.Result().Body).Decode(&body); err != nil {
+			t.Fatalf("response decode error: %+v\n", err)
+		}
+
+		actual := make([]uuid.UUID, 0, len(body.Data))
+		for _, rid := range body.Data {
+			actual = append(actual, rid.ID)
+		}
+		if len(actual) != len(step.Expected) {
+			t.Fatalf("step %d (%s): expected linkage %v, actual %v", i, step.Method, step.Expected, actual)
+		}
+		for _, id := range step.Expected {
+			found := false
+			for _, a := range actual {
+				found = found || a == id
+			}
+			if !found {
+				t.Errorf("step %d (%s): expected linkage %v, actual %v", i, step.Method, step.Expected, actual)
+			}
+		}
+	}
+}
@@ -41,7 +41,7 @@ func Test_Example_Update(t *testing.T) {
 			defer teardownTest(t)
 
 			entity := fx.ExampleEntityRecord(nil, nil)
-			record, err := insertExampleRecord(entity, s.DB)
+			record, err := insertExampleRecord(entity, s.Seeder)
 			if err != nil {
 				t.Fatalf("db insert error: %+v\n", err)
 			}
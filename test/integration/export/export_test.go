@@ -0,0 +1,69 @@
+package exporttest
+
+import (
+	"encoding/csv"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jasonsites/gosk/internal/http/export"
+)
+
+type CellSetup struct {
+	Name        string
+	Description string
+	Value       any
+	Expected    string
+}
+
+// Test_Export_Cell verifies that CSV cells which would be evaluated as formulas by spreadsheet applications are
+// prefixed with a single quote
+func Test_Export_Cell(t *testing.T) {
+	text := func(s string) *string { return &s }
+
+	tests := []CellSetup{
+		{Name: "text", Description: "does not prefix plain text", Value: "Example", Expected: "Example"},
+		{Name: "equals", Description: "prefixes text starting with =", Value: "=HYPERLINK(\"x\")", Expected: "'=HYPERLINK(\"x\")"},
+		{Name: "plus", Description: "prefixes text starting with +", Value: "+1+1", Expected: "'+1+1"},
+		{Name: "minus", Description: "prefixes text starting with -", Value: "-1+1", Expected: "'-1+1"},
+		{Name: "at", Description: "prefixes text starting with @", Value: "@SUM(A1)", Expected: "'@SUM(A1)"},
+		{Name: "tab", Description: "prefixes text starting with a tab", Value: "\t=1", Expected: "'\t=1"},
+		{Name: "carriage_return", Description: "prefixes text starting with a carriage return", Value: "\r=1", Expected: "'\r=1"},
+		{Name: "pointer", Description: "prefixes text pointers", Value: text("=1"), Expected: "'=1"},
+		{Name: "number", Description: "does not prefix negative numbers", Value: -42, Expected: "-42"},
+		{Name: "nil", Description: "formats nil values as empty text", Value: nil, Expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			if actual := export.Cell(tc.Value); actual != tc.Expected {
+				t.Errorf("expected '%q', actual '%q'", tc.Expected, actual)
+			}
+		})
+	}
+}
+
+// Test_Export_Writer verifies that CSV rows are written with formula cells prefixed
+func Test_Export_Writer(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w, err := export.NewWriter(rec, export.MediaTypeCSV, []string{"title", "count"})
+	if err != nil {
+		t.Fatalf("export writer error: %+v\n", err)
+	}
+	if err := w.Write([]any{"=cmd|' /C calc'!A0", -1}); err != nil {
+		t.Fatalf("export write error: %+v\n", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("export close error: %+v\n", err)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("csv decode error: %+v\n", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected '2' records, actual '%d'", len(records))
+	}
+	if records[1][0] != "'=cmd|' /C calc'!A0" || records[1][1] != "-1" {
+		t.Errorf("expected formula cell to be prefixed, actual '%v'", records[1])
+	}
+}
@@ -0,0 +1,149 @@
+package generatortest
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jasonsites/gosk/internal/generator"
+)
+
+// copyRepo copies the repository source tree (excluding version control) to a temporary directory
+func copyRepo(tb testing.TB) string {
+	src, err := filepath.Abs("../../..")
+	if err != nil {
+		tb.Fatalf("repository path error: %+v\n", err)
+	}
+	dest := tb.TempDir()
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dest, rel), 0o755)
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dest, rel), b, 0o644)
+	})
+	if err != nil {
+		tb.Fatalf("repository copy error: %+v\n", err)
+	}
+
+	return dest
+}
+
+func Test_Generator_Module(t *testing.T) {
+	root := copyRepo(t)
+
+	gen, err := generator.NewGenerator(&generator.GeneratorConfig{
+		Now:  func() time.Time { return time.Unix(4102444800, 0) },
+		Root: root,
+	})
+	if err != nil {
+		t.Fatalf("generator initialization error: %+v\n", err)
+	}
+
+	files, err := gen.Module("blog-post", nil)
+	if err != nil {
+		t.Fatalf("module generation error: %+v\n", err)
+	}
+
+	for _, f := range []string{
+		"internal/modules/blogpost/repo.interfaces.go",
+		"internal/resolver/blogpost.go",
+		"database/migrations/4102444800_blog-post.up.sql",
+		"test/integration/blogpost/crud_test.go",
+		"internal/http/httpserver/router.go",
+		"internal/resolver/utils.go",
+	} {
+		if !strings.Contains(strings.Join(files, "\n"), f) {
+			t.Errorf("expected generated file '%s', actual %v", f, files)
+		}
+	}
+
+	router, err := os.ReadFile(filepath.Join(root, "internal/http/httpserver/router.go"))
+	if err != nil {
+		t.Fatalf("router read error: %+v\n", err)
+	}
+	if !strings.Contains(string(router), "blogpost.BlogPostRouter(r, ns, c.BlogPostController)") {
+		t.Errorf("expected module routes to be registered")
+	}
+	if !strings.Contains(string(router), "ops = append(ops, c.BlogPostController.Operations()...)") {
+		t.Errorf("expected module operations to be registered")
+	}
+
+	utils, err := os.ReadFile(filepath.Join(root, "internal/resolver/utils.go"))
+	if err != nil {
+		t.Fatalf("resolver utils read error: %+v\n", err)
+	}
+	if !strings.Contains(string(utils), "fields = append(fields, blogpost.BlogPostRedactedFields()...)") {
+		t.Errorf("expected module redacted fields to be registered")
+	}
+
+	t.Run("existing module", func(t *testing.T) {
+		if _, err := gen.Module("blog_post", nil); err == nil {
+			t.Errorf("expected error for existing module")
+		}
+	})
+
+	t.Run("existing files", func(t *testing.T) {
+		for _, name := range []string{"utils", "crud", "jobs"} {
+			resolverFile := filepath.Join(root, "internal/resolver", name+".go")
+			before, err := os.ReadFile(resolverFile)
+			if err != nil {
+				t.Fatalf("resolver read error: %+v\n", err)
+			}
+
+			if _, err := gen.Module(name, nil); err == nil {
+				t.Errorf("expected error for module '%s' conflicting with existing files", name)
+			}
+
+			after, err := os.ReadFile(resolverFile)
+			if err != nil {
+				t.Fatalf("resolver read error: %+v\n", err)
+			}
+			if string(before) != string(after) {
+				t.Errorf("expected '%s' to be unchanged", resolverFile)
+			}
+			if _, err := os.Stat(filepath.Join(root, "internal/modules", name)); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("expected module '%s' not to be written", name)
+			}
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		if _, err := gen.Module("Blog Post", nil); err == nil {
+			t.Errorf("expected error for invalid module name")
+		}
+	})
+
+	t.Run("build", func(t *testing.T) {
+		if testing.Short() {
+			t.Skip("skipping generated module build in short mode")
+		}
+
+		for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}} {
+			cmd := exec.Command("go", args...)
+			cmd.Dir = root
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("go %s error: %+v\n%s", args[0], err, out)
+			}
+		}
+	})
+}
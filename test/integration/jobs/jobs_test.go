@@ -0,0 +1,189 @@
+package jobstest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/jasonsites/gosk/internal/jobs"
+	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+func Test_Jobs(t *testing.T) {
+	r, err := utils.InitializeResolver(&resolver.Config{}, resolver.Worker)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	if err := utils.Migrate(r); err != nil {
+		t.Fatalf("db migration error: %+v\n", err)
+	}
+	defer utils.Cleanup(r)
+
+	ctx := context.Background()
+	queue := r.JobQueue()
+
+	t.Run("unique", func(t *testing.T) {
+		opts := &jobs.EnqueueOptions{RunAt: time.Now().Add(time.Hour), UniqueKey: "unique"}
+
+		first, err := queue.Enqueue(ctx, "test.unique", map[string]any{"n": 1}, opts)
+		if err != nil {
+			t.Fatalf("enqueue error: %+v\n", err)
+		}
+		second, err := queue.Enqueue(ctx, "test.unique", map[string]any{"n": 2}, opts)
+		if err != nil {
+			t.Fatalf("enqueue error: %+v\n", err)
+		}
+
+		if first.ID != second.ID {
+			t.Errorf("expected duplicate enqueue to return job %s, got %s", first.ID, second.ID)
+		}
+	})
+
+	t.Run("run", func(t *testing.T) {
+		worker, err := jobs.NewWorker(&jobs.WorkerConfig{
+			Backoff:      func(int) time.Duration { return 0 },
+			Concurrency:  2,
+			DBClient:     r.PostgreSQLClient(),
+			Logger:       &logger.CustomLogger{Level: logger.LevelError, Log: slog.Default()},
+			PollInterval: 10 * time.Millisecond,
+			Queues:       []string{jobs.DefaultQueue},
+			Timeout:      time.Minute,
+		})
+		if err != nil {
+			t.Fatalf("worker initialization error: %+v\n", err)
+		}
+		worker.Register("test.succeed", func(ctx context.Context, job *jobs.Job) error {
+			return nil
+		})
+		worker.Register("test.fail", func(ctx context.Context, job *jobs.Job) error {
+			return errors.New("failed")
+		})
+		worker.Register("test.result", func(ctx context.Context, job *jobs.Job) error {
+			jobs.SetResult(ctx, map[string]any{"n": 1})
+			return nil
+		})
+
+		succeed, err := queue.Enqueue(ctx, "test.succeed", nil, nil)
+		if err != nil {
+			t.Fatalf("enqueue error: %+v\n", err)
+		}
+		fail, err := queue.Enqueue(ctx, "test.fail", nil, &jobs.EnqueueOptions{MaxAttempts: 2})
+		if err != nil {
+			t.Fatalf("enqueue error: %+v\n", err)
+		}
+		result, err := queue.Enqueue(ctx, "test.result", nil, nil)
+		if err != nil {
+			t.Fatalf("enqueue error: %+v\n", err)
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		go worker.Run(runCtx)
+
+		expected := map[string]jobs.Status{
+			succeed.ID.String(): jobs.StatusSucceeded,
+			fail.ID.String():    jobs.StatusDead,
+			result.ID.String():  jobs.StatusSucceeded,
+		}
+		for id, status := range expected {
+			var (
+				actual   jobs.Status
+				attempts int
+			)
+			for runCtx.Err() == nil {
+				row := r.PostgreSQLClient().QueryRow(ctx, "SELECT status, attempts FROM jobs WHERE id = $1", id)
+				if err := row.Scan(&actual, &attempts); err != nil {
+					t.Fatalf("job query error: %+v\n", err)
+				}
+				if actual == status {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			if actual != status {
+				t.Errorf("expected job %s status '%s', got '%s'", id, status, actual)
+			}
+			if status == jobs.StatusDead && attempts != 2 {
+				t.Errorf("expected dead job %s after 2 attempts, got %d", id, attempts)
+			}
+		}
+
+		job, err := queue.Get(ctx, result.ID)
+		if err != nil {
+			t.Fatalf("job get error: %+v\n", err)
+		}
+		var data map[string]int
+		if err := json.Unmarshal(job.Result, &data); err != nil || data["n"] != 1 {
+			t.Errorf("expected job %s result n 1, got '%s'", result.ID, job.Result)
+		}
+	})
+	t.Run("reclaimed", func(t *testing.T) {
+		worker, err := jobs.NewWorker(&jobs.WorkerConfig{
+			Concurrency:  1,
+			DBClient:     r.PostgreSQLClient(),
+			Logger:       &logger.CustomLogger{Level: logger.LevelError, Log: slog.Default()},
+			PollInterval: 10 * time.Millisecond,
+			Queues:       []string{"reclaimed"},
+			Timeout:      time.Minute,
+		})
+		if err != nil {
+			t.Fatalf("worker initialization error: %+v\n", err)
+		}
+
+		// the handler simulates the job being rescued as abandoned and claimed by another worker while running
+		ran := make(chan struct{})
+		worker.Register("test.reclaimed", func(ctx context.Context, job *jobs.Job) error {
+			defer close(ran)
+			_, err := r.PostgreSQLClient().Exec(ctx,
+				"UPDATE jobs SET locked_by = 'other-worker', attempts = attempts + 1, locked_on = now() WHERE id = $1",
+				job.ID,
+			)
+			if err != nil {
+				return err
+			}
+			return errors.New("failed")
+		})
+
+		job, err := queue.Enqueue(ctx, "test.reclaimed", nil, &jobs.EnqueueOptions{MaxAttempts: 5, Queue: "reclaimed"})
+		if err != nil {
+			t.Fatalf("enqueue error: %+v\n", err)
+		}
+
+		runCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			worker.Run(runCtx)
+		}()
+
+		select {
+		case <-ran:
+		case <-runCtx.Done():
+			t.Fatalf("expected job %s to run", job.ID)
+		}
+		// Run returns once the in-flight job outcome has been recorded (or discarded)
+		cancel()
+		<-done
+
+		var (
+			status   jobs.Status
+			lockedBy *string
+			lastErr  *string
+		)
+		row := r.PostgreSQLClient().QueryRow(ctx, "SELECT status, locked_by, last_error FROM jobs WHERE id = $1", job.ID)
+		if err := row.Scan(&status, &lockedBy, &lastErr); err != nil {
+			t.Fatalf("job query error: %+v\n", err)
+		}
+		if status != jobs.StatusRunning || lockedBy == nil || *lockedBy != "other-worker" || lastErr != nil {
+			t.Errorf("expected reclaimed job %s to remain running for the other worker, got status '%s', locked by %v, last error %v",
+				job.ID, status, lockedBy, lastErr)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package migratetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jasonsites/gosk/internal/database"
+	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+// schema defines the schema isolating test migrations (and their schema_migrations table) from the app schema
+const schema = "migrate_test"
+
+// migrations defines three test migrations, of which the last fails when failing is set
+func migrations(failing bool) fstest.MapFS {
+	third := "CREATE TABLE widget_tag (widget_id int);"
+	if failing {
+		third = "CREATE TABLE widget_tag (widget_id int REFERENCES missing (id));"
+	}
+	return fstest.MapFS{
+		"migrations/1_widget.up.sql":        {Data: []byte("CREATE TABLE widget (id int PRIMARY KEY);")},
+		"migrations/1_widget.down.sql":      {Data: []byte("DROP TABLE widget;")},
+		"migrations/2_widget-name.up.sql":   {Data: []byte("ALTER TABLE widget ADD COLUMN name text;")},
+		"migrations/2_widget-name.down.sql": {Data: []byte("ALTER TABLE widget DROP COLUMN name;")},
+		"migrations/3_widget-tag.up.sql":    {Data: []byte(third)},
+		"migrations/3_widget-tag.down.sql":  {Data: []byte("DROP TABLE widget_tag;")},
+		"migrations/README.md":              {Data: []byte("ignored")},
+	}
+}
+
+func Test_Migrator(t *testing.T) {
+	r, err := utils.InitializeResolver(&resolver.Config{}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	ctx := context.Background()
+
+	if _, err := r.PostgreSQLClient().Exec(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %[1]s CASCADE; CREATE SCHEMA %[1]s", schema)); err != nil {
+		t.Fatalf("schema create error: %+v\n", err)
+	}
+	defer r.PostgreSQLClient().Exec(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema))
+
+	conf := r.PostgreSQLClient().Config().Copy()
+	conf.ConnConfig.RuntimeParams["search_path"] = schema
+	db, err := pgxpool.NewWithConfig(ctx, conf)
+	if err != nil {
+		t.Fatalf("db pool error: %+v\n", err)
+	}
+	defer db.Close()
+
+	migrator := func(t *testing.T, failing bool) *database.Migrator {
+		m, err := database.NewMigrator(&database.MigratorConfig{
+			DBClient: db,
+			FS:       migrations(failing),
+			Logger:   &logger.CustomLogger{Level: logger.LevelError, Log: slog.Default()},
+			Path:     "migrations",
+		})
+		if err != nil {
+			t.Fatalf("migrator initialization error: %+v\n", err)
+		}
+		return m
+	}
+	expectVersion := func(t *testing.T, m *database.Migrator, expected int64, expectedDirty bool) {
+		t.Helper()
+		version, dirty, err := m.Version(ctx)
+		if err != nil {
+			t.Fatalf("version error: %+v\n", err)
+		}
+		if version != expected || dirty != expectedDirty {
+			t.Errorf("expected version %d (dirty %t), actual %d (dirty %t)", expected, expectedDirty, version, dirty)
+		}
+	}
+	tableExists := func(t *testing.T, table string) bool {
+		t.Helper()
+		var exists bool
+		if err := db.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", schema+"."+table).Scan(&exists); err != nil {
+			t.Fatalf("table query error: %+v\n", err)
+		}
+		return exists
+	}
+
+	t.Run("up", func(t *testing.T) {
+		m := migrator(t, false)
+		expectVersion(t, m, database.NilVersion, false)
+
+		if err := m.Up(ctx); err != nil {
+			t.Fatalf("up error: %+v\n", err)
+		}
+		expectVersion(t, m, 3, false)
+		if !tableExists(t, "widget") || !tableExists(t, "widget_tag") {
+			t.Errorf("expected widget and widget_tag tables")
+		}
+
+		// up is a no-op once all migrations are applied
+		if err := m.Up(ctx); err != nil {
+			t.Fatalf("repeated up error: %+v\n", err)
+		}
+		expectVersion(t, m, 3, false)
+	})
+
+	t.Run("steps", func(t *testing.T) {
+		m := migrator(t, false)
+
+		if err := m.Steps(ctx, -2); err != nil {
+			t.Fatalf("steps down error: %+v\n", err)
+		}
+		expectVersion(t, m, 1, false)
+		if !tableExists(t, "widget") || tableExists(t, "widget_tag") {
+			t.Errorf("expected widget table only")
+		}
+
+		if err := m.Steps(ctx, 1); err != nil {
+			t.Fatalf("steps up error: %+v\n", err)
+		}
+		expectVersion(t, m, 2, false)
+	})
+
+	t.Run("down", func(t *testing.T) {
+		m := migrator(t, false)
+
+		if err := m.Down(ctx); err != nil {
+			t.Fatalf("down error: %+v\n", err)
+		}
+		expectVersion(t, m, database.NilVersion, false)
+		if tableExists(t, "widget") {
+			t.Errorf("expected widget table to be dropped")
+		}
+	})
+
+	t.Run("dirty", func(t *testing.T) {
+		m := migrator(t, true)
+
+		if err := m.Up(ctx); err == nil {
+			t.Fatal("expected failing migration error")
+		}
+		expectVersion(t, m, 3, true)
+
+		if err := m.Up(ctx); !errors.Is(err, database.ErrDirty) {
+			t.Errorf("expected dirty error, actual %+v", err)
+		}
+
+		if err := m.Force(ctx, 2); err != nil {
+			t.Fatalf("force error: %+v\n", err)
+		}
+		expectVersion(t, m, 2, false)
+
+		if err := m.Down(ctx); err != nil {
+			t.Fatalf("down error: %+v\n", err)
+		}
+	})
+
+	t.Run("advisory_lock", func(t *testing.T) {
+		m := migrator(t, false)
+
+		// hold the migration lock (as another replica running migrations would)
+		conn, err := db.Acquire(ctx)
+		if err != nil {
+			t.Fatalf("db acquire error: %+v\n", err)
+		}
+		defer conn.Release()
+		id := database.AdvisoryLockID("schema_migrations:" + conn.Conn().Config().Database)
+		if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock($1)", id); err != nil {
+			t.Fatalf("lock error: %+v\n", err)
+		}
+
+		done := make(chan error, 1)
+		go func() { done <- m.Up(ctx) }()
+
+		select {
+		case err := <-done:
+			t.Fatalf("expected up to wait for the migration lock, returned %+v", err)
+		case <-time.After(300 * time.Millisecond):
+		}
+		if tableExists(t, "widget") {
+			t.Errorf("expected no migrations applied while locked")
+		}
+
+		if _, err := conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", id); err != nil {
+			t.Fatalf("unlock error: %+v\n", err)
+		}
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("up error: %+v\n", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected up to run once the migration lock is released")
+		}
+		expectVersion(t, m, 3, false)
+	})
+}
+
+func Test_CreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Unix(1761462000, 0)
+
+	files, err := database.CreateMigration(dir, "add-widget", now)
+	if err != nil {
+		t.Fatalf("create migration error: %+v\n", err)
+	}
+	expected := []string{
+		filepath.Join(dir, "1761462000_add-widget.up.sql"),
+		filepath.Join(dir, "1761462000_add-widget.down.sql"),
+	}
+	if len(files) != 2 || files[0] != expected[0] || files[1] != expected[1] {
+		t.Fatalf("expected files %v, actual %v", expected, files)
+	}
+	for _, file := range files {
+		if _, err := os.Stat(file); err != nil {
+			t.Errorf("expected file '%s', actual %+v", file, err)
+		}
+	}
+
+	if _, err := database.CreateMigration(dir, "add-widget", now); err == nil {
+		t.Errorf("expected existing migration files not to be overwritten")
+	}
+	if _, err := database.CreateMigration(dir, "../escape", now); err == nil {
+		t.Errorf("expected invalid migration name error")
+	}
+}
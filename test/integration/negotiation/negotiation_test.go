@@ -0,0 +1,201 @@
+package negotiationtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+const (
+	extension = "https://jsonapi.org/ext/atomic"
+	profile   = "https://example.com/profiles/timestamps"
+	// invalid defines a JSON:API request body which fails validation (without reaching the database)
+	invalid = `{"data":{"type":"example","attributes":{"title":"a"}}}`
+)
+
+type NegotiationSetup struct {
+	Name        string
+	Description string
+	Method      string
+	Route       string
+	Accept      string
+	ContentType string
+	Body        string
+	Expected    NegotiationExpected
+}
+
+// NegotiationExpected defines the expected response status, media type, (first) error code and error source header
+type NegotiationExpected struct {
+	Code      int
+	MediaType string
+	ErrorCode string
+	Header    string
+}
+
+// Test_ContentNegotiation verifies JSON:API media type negotiation: 415 Unsupported Media Type for unsupported
+// request media types, 406 Not Acceptable for unacceptable Accept headers, and the negotiated response media type
+// (with supported ext and profile parameters)
+func Test_ContentNegotiation(t *testing.T) {
+	tests := []NegotiationSetup{
+		{
+			Name:        "jsonapi",
+			Description: "responds with the JSON:API media type",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Accept:      jsonapi.MediaType,
+			ContentType: jsonapi.MediaType,
+			Body:        invalid,
+			Expected:    NegotiationExpected{http.StatusBadRequest, jsonapi.MediaType, "validation_length_too_short", ""},
+		},
+		{
+			Name:        "accept_any",
+			Description: "responds with the JSON:API media type when any media type is accepted",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			ContentType: jsonapi.MediaType,
+			Body:        invalid,
+			Expected:    NegotiationExpected{http.StatusBadRequest, jsonapi.MediaType, "validation_length_too_short", ""},
+		},
+		{
+			Name:        "json",
+			Description: "responds with plain JSON when only plain JSON is accepted",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Accept:      "application/json",
+			ContentType: "application/json",
+			Body:        invalid,
+			Expected:    NegotiationExpected{http.StatusBadRequest, "application/json", "validation_length_too_short", ""},
+		},
+		{
+			Name:        "ext_profile",
+			Description: "responds with supported extensions and profiles, ignoring unrecognized profiles",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Accept:      `application/vnd.api+json; ext="` + extension + `"; profile="` + profile + ` https://example.com/unknown"`,
+			ContentType: `application/vnd.api+json; ext="` + extension + `"`,
+			Body:        invalid,
+			Expected: NegotiationExpected{http.StatusBadRequest,
+				`application/vnd.api+json; ext="` + extension + `"; profile="` + profile + `"`, "validation_length_too_short", ""},
+		},
+		{
+			Name:        "unsupported_content_type",
+			Description: "rejects request bodies of unsupported media types",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			ContentType: "text/plain",
+			Body:        invalid,
+			Expected:    NegotiationExpected{http.StatusUnsupportedMediaType, jsonapi.MediaType, "unsupported_media_type", "Content-Type"},
+		},
+		{
+			Name:        "unsupported_content_type_param",
+			Description: "rejects JSON:API request bodies with media type parameters other than ext and profile",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			ContentType: jsonapi.MediaType + "; charset=utf-8",
+			Body:        invalid,
+			Expected:    NegotiationExpected{http.StatusUnsupportedMediaType, jsonapi.MediaType, "unsupported_media_type", "Content-Type"},
+		},
+		{
+			Name:        "unsupported_content_type_ext",
+			Description: "rejects JSON:API request bodies with unsupported extensions",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			ContentType: `application/vnd.api+json; ext="https://example.com/unknown"`,
+			Body:        invalid,
+			Expected:    NegotiationExpected{http.StatusUnsupportedMediaType, jsonapi.MediaType, "unsupported_media_type", "Content-Type"},
+		},
+		{
+			Name:        "not_acceptable",
+			Description: "rejects Accept headers without an available media type",
+			Method:      http.MethodGet,
+			Route:       "/domain/examples",
+			Accept:      "text/html",
+			Expected:    NegotiationExpected{http.StatusNotAcceptable, jsonapi.MediaType, "not_acceptable", "Accept"},
+		},
+		{
+			Name:        "not_acceptable_param",
+			Description: "rejects Accept headers in which every JSON:API media type has unsupported parameters",
+			Method:      http.MethodGet,
+			Route:       "/domain/examples",
+			Accept:      jsonapi.MediaType + "; charset=utf-8, */*;q=0.1",
+			Expected:    NegotiationExpected{http.StatusNotAcceptable, jsonapi.MediaType, "not_acceptable", "Accept"},
+		},
+		{
+			Name:        "not_acceptable_ext",
+			Description: "rejects Accept headers in which every JSON:API media type has unsupported extensions",
+			Method:      http.MethodGet,
+			Route:       "/domain/examples",
+			Accept:      `application/vnd.api+json; ext="https://example.com/unknown"`,
+			Expected:    NegotiationExpected{http.StatusNotAcceptable, jsonapi.MediaType, "not_acceptable", "Accept"},
+		},
+		{
+			Name:        "health",
+			Description: "skips negotiation for healthcheck",
+			Method:      http.MethodGet,
+			Route:       "/domain/health",
+			Accept:      "text/html",
+			Expected:    NegotiationExpected{http.StatusOK, jsonapi.MediaType, "", ""},
+		},
+	}
+
+	conf, err := config.LoadConfiguration()
+	if err != nil {
+		t.Fatalf("configuration load error: %+v\n", err)
+	}
+	conf.HTTP.RateLimit.Enabled = false
+	conf.HTTP.Router.JSONAPI.Extensions = []string{extension}
+	conf.HTTP.Router.JSONAPI.Profiles = []string{profile}
+
+	r, err := utils.InitializeResolver(&resolver.Config{Config: conf}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	handler := r.HTTPServer().Server.Handler
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.Method, tc.Route, strings.NewReader(tc.Body))
+			if tc.Accept != "" {
+				req.Header.Set("Accept", tc.Accept)
+			}
+			if tc.ContentType != "" {
+				req.Header.Set("Content-Type", tc.ContentType)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.Expected.Code {
+				t.Fatalf("expected '%d', actual '%d' (%s)", tc.Expected.Code, rec.Code, rec.Body.String())
+			}
+			if mediaType := rec.Header().Get("Content-Type"); mediaType != tc.Expected.MediaType {
+				t.Errorf("expected media type '%s', actual '%s'", tc.Expected.MediaType, mediaType)
+			}
+			if tc.Expected.ErrorCode == "" {
+				return
+			}
+
+			body := &jsonapi.ErrorResponse{}
+			if err := json.NewDecoder(rec.Body).Decode(body); err != nil {
+				t.Fatalf("response decode error: %+v\n", err)
+			}
+			if len(body.Errors) == 0 || body.Errors[0].Code != tc.Expected.ErrorCode {
+				t.Fatalf("expected error code '%s', actual %+v", tc.Expected.ErrorCode, body.Errors)
+			}
+			if tc.Expected.Header != "" {
+				if source := body.Errors[0].Source; source == nil || source.Header != tc.Expected.Header {
+					t.Errorf("expected error source header '%s', actual %+v", tc.Expected.Header, source)
+				}
+				if vary := rec.Header().Values("Vary"); !strings.Contains(strings.Join(vary, ","), "Accept") {
+					t.Errorf("expected Vary header to include 'Accept', actual %v", vary)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package openapitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/http/openapi"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+// Test_OpenAPI_Drift fails when registered routes and the served OpenAPI document drift apart, i.e. when a route
+// is added without describing its operation (see routeOperations), or an operation is described without a route
+func Test_OpenAPI_Drift(t *testing.T) {
+	conf, err := config.LoadConfiguration()
+	if err != nil {
+		t.Fatalf("configuration load error: %+v\n", err)
+	}
+	conf.HTTP.RateLimit.Enabled = false
+	conf.HTTP.Router.OpenAPI.Enabled = true
+	conf.HTTP.Router.OpenAPI.Viewer = true
+	conf.HTTP.Router.OpenAPI.ViewerIntegrity = "sha384-test"
+
+	r, err := utils.InitializeResolver(&resolver.Config{Config: conf}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	handler := r.HTTPServer().Server.Handler
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/domain/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected '%d', actual '%d' (%s)", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	doc := &openapi.Document{}
+	if err := json.NewDecoder(rec.Body).Decode(doc); err != nil {
+		t.Fatalf("document decode error: %+v\n", err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("expected openapi version '%s', actual '%s'", openapi.Version, doc.OpenAPI)
+	}
+
+	routes := map[string]bool{}
+	err = chi.Walk(handler.(chi.Routes), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if !strings.Contains(route, "*") {
+			routes[strings.ToLower(method)+" "+openapi.NormalizePattern(route)] = true
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("route walk error: %+v\n", err)
+	}
+
+	documented := map[string]bool{}
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			key := method + " " + path
+			documented[key] = true
+			if op.Undocumented {
+				t.Errorf("route '%s' is registered without a described operation", key)
+			}
+			if !routes[key] {
+				t.Errorf("operation '%s' is described without a registered route", key)
+			}
+		}
+	}
+	for key := range routes {
+		if !documented[key] {
+			t.Errorf("route '%s' is missing from the document", key)
+		}
+	}
+
+	t.Run("dto constraints", func(t *testing.T) {
+		dto, ok := doc.Components.Schemas["ExampleDTORequest"]
+		if !ok {
+			t.Fatalf("expected ExampleDTORequest component schema")
+		}
+		if !slices.Contains(dto.Required, "title") {
+			t.Errorf("expected required title, actual %v", dto.Required)
+		}
+		if title := dto.Properties["title"]; title == nil || title.MaxLength == nil || *title.MaxLength != 255 {
+			t.Errorf("expected title maxLength 255, actual %+v", title)
+		}
+	})
+
+	t.Run("list parameters", func(t *testing.T) {
+		list := doc.Paths["/domain/examples"]["get"]
+		if list == nil {
+			t.Fatalf("expected example list operation")
+		}
+		var names []string
+		for _, p := range list.Parameters {
+			names = append(names, p.Name)
+		}
+		for _, name := range []string{"sort", "page[limit]", "page[offset]", "filter[title]", "q", "include"} {
+			if !slices.Contains(names, name) {
+				t.Errorf("expected list parameter '%s', actual %v", name, names)
+			}
+		}
+	})
+
+	t.Run("viewer", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/domain/docs", nil))
+		if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "/domain/openapi.json") {
+			t.Errorf("expected viewer page, actual '%d'", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,248 @@
+package ratelimittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+type RateLimitSetup struct {
+	Name        string
+	Description string
+	Expected    []utils.Expected
+	Headers     map[string]string
+	// Isolated defines whether the quota of a different client (IP address) is exhausted first
+	Isolated bool
+	// RemoteAddr defines the peer address of all requests (default 192.0.2.1:1234)
+	RemoteAddr string
+	// RequestHeaders defines the headers of the i-th request (e.g. a different API key per request)
+	RequestHeaders func(i int) map[string]string
+	Routes         []config.RateLimitRoute
+	TrustedProxies []string
+}
+
+// authenticate verifies API keys and bearer tokens with a "valid-" prefix, setting the verified client identity
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var id mw.ClientIdentity
+		if key := r.Header.Get("X-API-Key"); strings.HasPrefix(key, "valid-") {
+			id.APIKey = key
+		}
+		if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && strings.HasPrefix(token, "valid-") {
+			id.Subject = strings.TrimPrefix(token, "valid-")
+		}
+		if id != (mw.ClientIdentity{}) {
+			r = r.WithContext(mw.WithClientIdentity(r.Context(), id))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func Test_RateLimit(t *testing.T) {
+	tests := []RateLimitSetup{
+		{
+			Name:        "default",
+			Description: "fails (429) after the default quota is exhausted",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+				{Code: http.StatusTooManyRequests},
+			},
+		},
+		{
+			Name:        "route",
+			Description: "fails (429) after the route quota is exhausted",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusTooManyRequests},
+			},
+			Routes: []config.RateLimitRoute{
+				{Method: http.MethodGet, Path: "/domain/", Limit: 1, Period: time.Minute},
+			},
+		},
+		{
+			Name:        "client_ip",
+			Description: "succeeds (200) with quota keyed by client ip address",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+			},
+			Isolated: true,
+		},
+		{
+			Name:        "unverified_key",
+			Description: "fails (429) after the quota is exhausted, with a different unverified api key per request",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+				{Code: http.StatusTooManyRequests},
+			},
+			RequestHeaders: func(i int) map[string]string {
+				return map[string]string{"X-API-Key": fmt.Sprintf("random-key-%d", i)}
+			},
+		},
+		{
+			Name:        "verified_key",
+			Description: "succeeds (200) with quota keyed by verified api key",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+			},
+			RequestHeaders: func(i int) map[string]string {
+				return map[string]string{"X-API-Key": fmt.Sprintf("valid-key-%d", i)}
+			},
+		},
+		{
+			Name:        "verified_subject",
+			Description: "succeeds (200) with quota keyed by verified jwt subject",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+			},
+			RequestHeaders: func(i int) map[string]string {
+				return map[string]string{"Authorization": fmt.Sprintf("Bearer valid-user-%d", i)}
+			},
+		},
+		{
+			Name:        "verified_key_shared",
+			Description: "fails (429) after the quota of a verified api key is exhausted",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+				{Code: http.StatusTooManyRequests},
+			},
+			RequestHeaders: func(i int) map[string]string {
+				return map[string]string{"X-API-Key": "valid-key"}
+			},
+		},
+		{
+			Name:        "forwarded_trusted",
+			Description: "succeeds (200) with quota keyed by the forwarded client ip of trusted proxies",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+			},
+			RemoteAddr: "10.0.0.2:1234",
+			RequestHeaders: func(i int) map[string]string {
+				return map[string]string{"X-Forwarded-For": fmt.Sprintf("198.51.100.%d, 10.0.0.1", i+1)}
+			},
+			TrustedProxies: []string{"10.0.0.0/8"},
+		},
+		{
+			Name:        "forwarded_untrusted",
+			Description: "fails (429) after the quota is exhausted, ignoring forwarded client ips of untrusted peers",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+				{Code: http.StatusTooManyRequests},
+			},
+			RemoteAddr: "10.0.0.2:1234",
+			RequestHeaders: func(i int) map[string]string {
+				return map[string]string{"X-Forwarded-For": fmt.Sprintf("198.51.100.%d", i+1)}
+			},
+		},
+		{
+			Name:        "cors",
+			Description: "fails (429) with CORS headers readable by browser clients",
+			Expected: []utils.Expected{
+				{Code: http.StatusOK},
+				{Code: http.StatusOK},
+				{Code: http.StatusTooManyRequests},
+			},
+			Headers: map[string]string{"Origin": "http://localhost:3000"},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			conf, err := config.LoadConfiguration()
+			if err != nil {
+				t.Fatalf("configuration load error: %+v\n", err)
+			}
+			conf.HTTP.RateLimit.Default = config.RateLimitQuota{Limit: 2, Period: time.Minute}
+			conf.HTTP.RateLimit.Enabled = true
+			conf.HTTP.RateLimit.Routes = tc.Routes
+			conf.HTTP.RateLimit.Store = "memory"
+			conf.HTTP.Server.TrustedProxies = tc.TrustedProxies
+
+			r, err := utils.InitializeResolver(&resolver.Config{Authenticator: authenticate, Config: conf}, resolver.HTTP)
+			if err != nil {
+				t.Fatalf("app initialization error: %+v\n", err)
+			}
+			handler := r.HTTPServer().Server.Handler
+
+			if tc.Isolated {
+				// exhaust the quota of a different client to verify isolation
+				for range 3 {
+					req := httptest.NewRequest(http.MethodGet, "/domain/", nil)
+					req.RemoteAddr = "192.0.2.99:1234"
+					handler.ServeHTTP(httptest.NewRecorder(), req)
+				}
+			}
+
+			for i, expected := range tc.Expected {
+				headers := maps.Clone(tc.Headers)
+				if tc.RequestHeaders != nil {
+					headers = tc.RequestHeaders(i)
+				}
+				rd := &utils.RequestData{
+					Headers: headers,
+					Method:  http.MethodGet,
+					Route:   "/domain/",
+				}
+
+				req, err := rd.SetRequestData(nil)
+				if err != nil {
+					t.Fatalf("http request error: %+v\n", err)
+				}
+				req.RemoteAddr = "192.0.2.1:1234"
+				if tc.RemoteAddr != "" {
+					req.RemoteAddr = tc.RemoteAddr
+				}
+
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				res := rec.Result()
+				if res.StatusCode != expected.Code {
+					t.Fatalf("request %d: expected '%d', actual '%d'", i, expected.Code, res.StatusCode)
+				}
+				if res.Header.Get("RateLimit-Limit") == "" || res.Header.Get("RateLimit-Remaining") == "" {
+					t.Errorf("request %d: expected RateLimit headers to be set", i)
+				}
+
+				if res.StatusCode == http.StatusTooManyRequests {
+					if res.Header.Get("Retry-After") == "" {
+						t.Errorf("expected Retry-After header to be set")
+					}
+
+					var body jsonapi.ErrorResponse
+					if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+						t.Fatalf("response decode error: %+v\n", err)
+					}
+					if len(body.Errors) != 1 || body.Errors[0].Title != "TooManyRequestsError" {
+						t.Errorf("expected TooManyRequestsError, actual '%+v'", body.Errors)
+					}
+					if origin := tc.Headers["Origin"]; origin != "" && res.Header.Get("Access-Control-Allow-Origin") == "" {
+						t.Errorf("expected Access-Control-Allow-Origin header to be set")
+					}
+				}
+			}
+		})
+	}
+}
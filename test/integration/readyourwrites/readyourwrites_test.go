@@ -0,0 +1,94 @@
+package readyourwritestest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jasonsites/gosk/internal/database"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+)
+
+type ReadYourWritesSetup struct {
+	Name        string
+	Description string
+	Method      string
+	Headers     map[string]string
+	Expected    ReadYourWritesExpected
+}
+
+// ReadYourWritesExpected defines whether the request context is expected to be flagged for read-your-writes
+type ReadYourWritesExpected struct {
+	Primary bool
+}
+
+// Test_ReadYourWrites verifies that unsafe requests and requests with the read-your-writes header route reads
+// to the primary
+func Test_ReadYourWrites(t *testing.T) {
+	tests := []ReadYourWritesSetup{
+		{
+			Name:        "get",
+			Description: "routes safe request reads to replicas",
+			Method:      http.MethodGet,
+			Expected:    ReadYourWritesExpected{Primary: false},
+		},
+		{
+			Name:        "get_header",
+			Description: "routes safe request reads with the read-your-writes header to the primary",
+			Method:      http.MethodGet,
+			Headers:     map[string]string{"X-Read-Your-Writes": "true"},
+			Expected:    ReadYourWritesExpected{Primary: true},
+		},
+		{
+			Name:        "post",
+			Description: "routes unsafe request reads to the primary",
+			Method:      http.MethodPost,
+			Expected:    ReadYourWritesExpected{Primary: true},
+		},
+		{
+			Name:        "patch",
+			Description: "routes unsafe request reads to the primary",
+			Method:      http.MethodPatch,
+			Expected:    ReadYourWritesExpected{Primary: true},
+		},
+		{
+			Name:        "delete",
+			Description: "routes unsafe request reads to the primary",
+			Method:      http.MethodDelete,
+			Expected:    ReadYourWritesExpected{Primary: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			var primary bool
+			handler := mw.ReadYourWrites(&mw.ReadYourWritesConfig{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				primary = database.UsePrimary(r.Context())
+			}))
+
+			req := httptest.NewRequest(tc.Method, "/domain/examples", nil)
+			for k, v := range tc.Headers {
+				req.Header.Set(k, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if primary != tc.Expected.Primary {
+				t.Errorf("expected primary '%t', actual '%t'", tc.Expected.Primary, primary)
+			}
+		})
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		var primary bool
+		conf := &mw.ReadYourWritesConfig{Next: func(r *http.Request) bool { return true }}
+		handler := mw.ReadYourWrites(conf)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			primary = database.UsePrimary(r.Context())
+		}))
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/domain/examples", nil))
+
+		if primary {
+			t.Errorf("expected skipped request not to be flagged for read-your-writes")
+		}
+	})
+}
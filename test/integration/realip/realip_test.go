@@ -0,0 +1,118 @@
+package realiptest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+)
+
+type RealIPSetup struct {
+	Name        string
+	Description string
+	RemoteAddr  string
+	Headers     map[string][]string
+	Expected    string
+}
+
+// Test_RealIP verifies that the client IP address is only taken from forwarding headers of trusted proxies
+func Test_RealIP(t *testing.T) {
+	trusted := []string{"10.0.0.0/8", "192.0.2.10", "2001:db8::/32"}
+
+	tests := []RealIPSetup{
+		{
+			Name:        "direct",
+			Description: "keeps the peer address of untrusted peers",
+			RemoteAddr:  "203.0.113.7:1234",
+			Expected:    "203.0.113.7:1234",
+		},
+		{
+			Name:        "untrusted_forwarded",
+			Description: "ignores forwarding headers of untrusted peers",
+			RemoteAddr:  "203.0.113.7:1234",
+			Headers:     map[string][]string{"X-Forwarded-For": {"198.51.100.1"}, "X-Real-Ip": {"198.51.100.2"}},
+			Expected:    "203.0.113.7:1234",
+		},
+		{
+			Name:        "trusted_forwarded",
+			Description: "uses the forwarded client address of trusted proxies",
+			RemoteAddr:  "10.0.0.2:1234",
+			Headers:     map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			Expected:    "198.51.100.1",
+		},
+		{
+			Name:        "trusted_chain",
+			Description: "uses the last untrusted address of the forwarded chain, ignoring spoofed leading addresses",
+			RemoteAddr:  "10.0.0.2:1234",
+			Headers:     map[string][]string{"X-Forwarded-For": {"1.1.1.1, 198.51.100.1", "192.0.2.10, 10.0.0.3"}},
+			Expected:    "198.51.100.1",
+		},
+		{
+			Name:        "trusted_only",
+			Description: "uses the first forwarded address when all hops are trusted proxies",
+			RemoteAddr:  "10.0.0.2:1234",
+			Headers:     map[string][]string{"X-Forwarded-For": {"10.0.0.4, 10.0.0.3"}},
+			Expected:    "10.0.0.4",
+		},
+		{
+			Name:        "trusted_invalid_hop",
+			Description: "stops at invalid forwarded addresses",
+			RemoteAddr:  "10.0.0.2:1234",
+			Headers:     map[string][]string{"X-Forwarded-For": {"198.51.100.9, unknown, 10.0.0.3"}},
+			Expected:    "10.0.0.3",
+		},
+		{
+			Name:        "trusted_real_ip",
+			Description: "uses X-Real-IP of trusted proxies without X-Forwarded-For",
+			RemoteAddr:  "192.0.2.10:1234",
+			Headers:     map[string][]string{"X-Real-Ip": {"198.51.100.2"}},
+			Expected:    "198.51.100.2",
+		},
+		{
+			Name:        "trusted_ipv6",
+			Description: "supports ipv6 proxies and clients",
+			RemoteAddr:  "[2001:db8::1]:1234",
+			Headers:     map[string][]string{"X-Forwarded-For": {"2001:db9::7"}},
+			Expected:    "2001:db9::7",
+		},
+		{
+			Name:        "trusted_no_headers",
+			Description: "keeps the peer address of trusted proxies without forwarding headers",
+			RemoteAddr:  "10.0.0.2:1234",
+			Expected:    "10.0.0.2:1234",
+		},
+	}
+
+	handler := mw.RealIP(&mw.RealIPConfig{TrustedProxies: trusted})
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			var actual string
+			h := handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				actual = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tc.RemoteAddr
+			for k, values := range tc.Headers {
+				for _, v := range values {
+					req.Header.Add(k, v)
+				}
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			if actual != tc.Expected {
+				t.Errorf("expected '%s', actual '%s'", tc.Expected, actual)
+			}
+		})
+	}
+
+	t.Run("invalid_proxy", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected panic for invalid trusted proxy")
+			}
+		}()
+		mw.RealIP(&mw.RealIPConfig{TrustedProxies: []string{"not-an-ip"}})
+	})
+}
@@ -0,0 +1,145 @@
+package recoverytest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/logger"
+)
+
+// reporter records reported panics
+type reporter struct {
+	mutex   sync.Mutex
+	reports []mw.PanicReport
+}
+
+func (r *reporter) Report(ctx context.Context, report mw.PanicReport) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.reports = append(r.reports, report)
+}
+
+type RecoverySetup struct {
+	Name        string
+	Description string
+	Handler     http.HandlerFunc
+	Expected    RecoveryExpected
+}
+
+// RecoveryExpected defines the expected response status, and whether a JSON:API error response is expected
+type RecoveryExpected struct {
+	Code  int
+	Error bool
+}
+
+// Test_Recovery verifies that handler panics are logged (with stack and trace id), reported and counted, and
+// responded to with a JSON:API internal server error
+func Test_Recovery(t *testing.T) {
+	tests := []RecoverySetup{
+		{
+			Name:        "panic_value",
+			Description: "recovers non-error panic values",
+			Handler:     func(w http.ResponseWriter, r *http.Request) { panic("unexpected state") },
+			Expected:    RecoveryExpected{Code: http.StatusInternalServerError, Error: true},
+		},
+		{
+			Name:        "panic_error",
+			Description: "recovers error panic values",
+			Handler:     func(w http.ResponseWriter, r *http.Request) { panic(errors.New("unexpected error")) },
+			Expected:    RecoveryExpected{Code: http.StatusInternalServerError, Error: true},
+		},
+		{
+			Name:        "panic_after_write",
+			Description: "recovers panics after the response is written, without writing an error response",
+			Handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusAccepted)
+				panic("unexpected state")
+			},
+			Expected: RecoveryExpected{Code: http.StatusAccepted},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			var (
+				logs bytes.Buffer
+				rep  = &reporter{}
+			)
+			cLogger := &logger.CustomLogger{Level: "info", Log: slog.New(slog.NewJSONHandler(&logs, nil))}
+
+			recoverer, err := mw.NewRecoverer(&mw.RecovererConfig{Logger: cLogger, Reporter: rep})
+			if err != nil {
+				t.Fatalf("recoverer initialization error: %+v\n", err)
+			}
+			handler := mw.Correlation(&mw.CorrelationConfig{})(recoverer.Handler(tc.Handler))
+
+			req := httptest.NewRequest(http.MethodGet, "/domain/panic", nil)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.Expected.Code {
+				t.Fatalf("expected '%d', actual '%d'", tc.Expected.Code, rec.Code)
+			}
+			traceID := rec.Header().Get("X-Request-Id")
+
+			if tc.Expected.Error {
+				response := &jsonapi.ErrorResponse{}
+				if err := json.NewDecoder(rec.Body).Decode(response); err != nil {
+					t.Fatalf("response decode error: %+v\n", err)
+				}
+				if len(response.Errors) != 1 || response.Errors[0].Code != "internal_server_error" || response.Errors[0].ID != traceID {
+					t.Errorf("expected internal_server_error with id '%s', actual '%+v'", traceID, response.Errors)
+				}
+			}
+
+			if recoverer.Status().Panics != 1 {
+				t.Errorf("expected 1 panic, actual %d", recoverer.Status().Panics)
+			}
+			if len(rep.reports) != 1 || rep.reports[0].TraceID != traceID || len(rep.reports[0].Stack) == 0 {
+				t.Errorf("expected 1 report with trace id and stack, actual '%+v'", rep.reports)
+			}
+
+			entry := struct {
+				Level   string `json:"level"`
+				Msg     string `json:"msg"`
+				Stack   string `json:"stack"`
+				TraceID string `json:"trace_id"`
+			}{}
+			line, _, _ := strings.Cut(logs.String(), "\n")
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				t.Fatalf("log decode error: %+v\n", err)
+			}
+			if entry.Level != "ERROR" || !strings.HasPrefix(entry.Msg, "panic: unexpected") || entry.Stack == "" || entry.TraceID != traceID {
+				t.Errorf("expected panic error log with stack and trace id, actual '%+v'", entry)
+			}
+		})
+	}
+
+	t.Run("abort_handler", func(t *testing.T) {
+		cLogger := &logger.CustomLogger{Level: "info", Log: slog.New(slog.DiscardHandler)}
+		recoverer, err := mw.NewRecoverer(&mw.RecovererConfig{Logger: cLogger})
+		if err != nil {
+			t.Fatalf("recoverer initialization error: %+v\n", err)
+		}
+		handler := recoverer.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Errorf("expected http.ErrAbortHandler panic, actual '%v'", v)
+			}
+		}()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
@@ -0,0 +1,136 @@
+package redactiontest
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	mw "github.com/jasonsites/gosk/internal/http/middleware"
+	"github.com/jasonsites/gosk/internal/logger"
+)
+
+// credentials defines a DTO with a `redact` tagged field
+type credentials struct {
+	Username string `json:"username"`
+	Passcode string `json:"passcode" redact:"true"`
+}
+
+type RedactionSetup struct {
+	Name        string
+	Description string
+	Body        string
+	ContentType string
+	Header      map[string]string
+	// Secrets defines the values which must not be logged, and Logged the values which must be logged
+	Secrets []string
+	Logged  []string
+}
+
+// Test_Redaction verifies that sensitive headers and body fields are redacted from request logs by the redaction
+// policy (the configured defaults, with a field path mask and the tagged fields of a DTO)
+func Test_Redaction(t *testing.T) {
+	tests := []RedactionSetup{
+		{
+			Name:        "headers",
+			Description: "redacts denylisted headers",
+			Header:      map[string]string{"Authorization": "Bearer s3cr3t-token", "Cookie": "session=s3cr3t-session", "X-Custom": "visible"},
+			Secrets:     []string{"s3cr3t-token", "s3cr3t-session"},
+			Logged:      []string{"visible"},
+		},
+		{
+			Name:        "field_path",
+			Description: "redacts body fields by JSON path",
+			Body:        `{"data":{"type":"example","attributes":{"title":"visible","note":"s3cr3t-note"}}}`,
+			ContentType: jsonapi.MediaType,
+			Secrets:     []string{"s3cr3t-note"},
+			Logged:      []string{"visible"},
+		},
+		{
+			Name:        "field_key",
+			Description: "redacts body fields by key (at any depth)",
+			Body:        `[{"user":{"Password":"s3cr3t-password","api_key":"s3cr3t-key","name":"visible"}}]`,
+			ContentType: "application/json",
+			Secrets:     []string{"s3cr3t-password", "s3cr3t-key"},
+			Logged:      []string{"visible"},
+		},
+		{
+			Name:        "field_tag",
+			Description: "redacts body fields tagged on DTOs",
+			Body:        `{"data":{"type":"example","attributes":{"username":"visible","passcode":"s3cr3t-passcode"}}}`,
+			ContentType: jsonapi.MediaType,
+			Secrets:     []string{"s3cr3t-passcode"},
+			Logged:      []string{"visible"},
+		},
+		{
+			Name:        "text",
+			Description: "redacts url-encoded form fields of text bodies",
+			Body:        "username=visible&token=s3cr3t-token",
+			ContentType: "application/x-www-form-urlencoded",
+			Secrets:     []string{"s3cr3t-token"},
+			Logged:      []string{"visible"},
+		},
+	}
+
+	conf, err := config.LoadConfiguration()
+	if err != nil {
+		t.Fatalf("configuration load error: %+v\n", err)
+	}
+
+	fields := append([]string{"data.attributes.note"}, logger.TaggedFields("data.attributes", credentials{})...)
+	redactor := logger.NewRedactor(&logger.RedactionPolicy{
+		Fields:  fields,
+		Headers: conf.Logger.Redact.Headers,
+		Keys:    conf.Logger.Redact.Keys,
+	})
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			var out bytes.Buffer
+			handler := slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: redactor.ReplaceAttr})
+			log := &logger.CustomLogger{Level: logger.LevelDebug, Log: slog.New(handler)}
+
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+			h := mw.RequestLogger(&mw.RequestLoggerConfig{Logger: log})(next)
+
+			req := httptest.NewRequest(http.MethodPost, "/domain/examples", strings.NewReader(tc.Body))
+			if tc.ContentType != "" {
+				req.Header.Set("Content-Type", tc.ContentType)
+			}
+			for k, v := range tc.Header {
+				req.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			logged := out.String()
+			for _, secret := range tc.Secrets {
+				if strings.Contains(logged, secret) {
+					t.Errorf("expected '%s' to be redacted, actual '%s'", secret, logged)
+				}
+			}
+			for _, value := range append(tc.Logged, logger.RedactedValue) {
+				if !strings.Contains(logged, value) {
+					t.Errorf("expected '%s' to be logged, actual '%s'", value, logged)
+				}
+			}
+		})
+	}
+}
+
+// Test_Redaction_Struct verifies that `redact` tagged fields of structs are redacted at any log level
+func Test_Redaction_Struct(t *testing.T) {
+	var out bytes.Buffer
+	redactor := logger.NewRedactor(&logger.RedactionPolicy{})
+	log := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{ReplaceAttr: redactor.ReplaceAttr}))
+
+	log.Error("login failed", slog.Any("credentials", &credentials{Username: "visible", Passcode: "s3cr3t-passcode"}))
+
+	logged := out.String()
+	if strings.Contains(logged, "s3cr3t-passcode") || !strings.Contains(logged, "visible") {
+		t.Errorf("expected passcode to be redacted, actual '%s'", logged)
+	}
+}
@@ -0,0 +1,112 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	cerror "github.com/jasonsites/gosk/internal/cerror"
+	repo "github.com/jasonsites/gosk/internal/modules/common/repository"
+)
+
+type TranslateSetup struct {
+	Name        string
+	Description string
+	Err         error
+	ID          uuid.UUID
+	Expected    TranslateExpected
+}
+
+// TranslateExpected defines the expected error type, code and metadata constraint ("" for none)
+type TranslateExpected struct {
+	Type       string
+	Code       string
+	Constraint string
+}
+
+// Test_TranslateError verifies that database errors are translated to domain errors
+func Test_TranslateError(t *testing.T) {
+	id := uuid.New()
+	pgErr := func(code, constraint string) error {
+		return fmt.Errorf("query error: %w", &pgconn.PgError{Code: code, ConstraintName: constraint})
+	}
+
+	tests := []TranslateSetup{
+		{
+			Name:        "no_rows",
+			Description: "translates no rows to not found",
+			Err:         pgx.ErrNoRows,
+			ID:          id,
+			Expected:    TranslateExpected{cerror.ErrorType.NotFound, cerror.ErrorCode.NotFound, ""},
+		},
+		{
+			Name:        "unique",
+			Description: "translates unique violations to conflicts",
+			Err:         pgErr("23505", "example_entity_title_key"),
+			Expected:    TranslateExpected{cerror.ErrorType.Conflict, "conflict_unique_violation", "example_entity_title_key"},
+		},
+		{
+			Name:        "foreign_key",
+			Description: "translates foreign key violations to validation errors",
+			Err:         pgErr("23503", "example_tag_tag_id_fkey"),
+			Expected:    TranslateExpected{cerror.ErrorType.Validation, "validation_foreign_key_violation", "example_tag_tag_id_fkey"},
+		},
+		{
+			Name:        "check",
+			Description: "translates check violations to validation errors",
+			Err:         pgErr("23514", "example_entity_title_check"),
+			Expected:    TranslateExpected{cerror.ErrorType.Validation, "validation_check_violation", "example_entity_title_check"},
+		},
+		{
+			Name:        "query_canceled",
+			Description: "translates canceled statements to timeouts",
+			Err:         pgErr("57014", ""),
+			Expected:    TranslateExpected{cerror.ErrorType.Timeout, cerror.ErrorCode.Timeout, ""},
+		},
+		{
+			Name:        "deadline",
+			Description: "translates context deadlines to timeouts",
+			Err:         fmt.Errorf("query error: %w", context.DeadlineExceeded),
+			Expected:    TranslateExpected{cerror.ErrorType.Timeout, cerror.ErrorCode.Timeout, ""},
+		},
+		{
+			Name:        "other",
+			Description: "translates other errors to internal server errors",
+			Err:         errors.New("connection refused"),
+			Expected:    TranslateExpected{cerror.ErrorType.InternalServer, cerror.ErrorCode.InternalServer, ""},
+		},
+		{
+			Name:        "custom",
+			Description: "returns custom errors unchanged",
+			Err:         cerror.NewForbiddenError(nil, "forbidden"),
+			Expected:    TranslateExpected{cerror.ErrorType.Forbidden, cerror.ErrorCode.Forbidden, ""},
+		},
+	}
+
+	if err := repo.TranslateError(nil, "example_entity", id); err != nil {
+		t.Errorf("expected nil error, actual '%v'", err)
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			var e cerror.CustomError
+			if !errors.As(repo.TranslateError(tc.Err, "example_entity", tc.ID), &e) {
+				t.Fatalf("expected custom error, actual '%T'", e)
+			}
+
+			if e.Type() != tc.Expected.Type || e.Code() != tc.Expected.Code {
+				t.Errorf("expected type '%s' and code '%s', actual '%s' and '%s'", tc.Expected.Type, tc.Expected.Code, e.Type(), e.Code())
+			}
+			if constraint, _ := e.Meta()["constraint"].(string); constraint != tc.Expected.Constraint {
+				t.Errorf("expected constraint '%s', actual '%s'", tc.Expected.Constraint, constraint)
+			}
+			if !errors.Is(e, tc.Err) {
+				t.Errorf("expected wrapped source error '%v'", tc.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package schedulertest
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/resolver"
+	"github.com/jasonsites/gosk/internal/scheduler"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+func Test_Scheduler(t *testing.T) {
+	r, err := utils.InitializeResolver(&resolver.Config{}, resolver.Worker)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	if err := utils.Migrate(r); err != nil {
+		t.Fatalf("db migration error: %+v\n", err)
+	}
+	defer utils.Cleanup(r)
+
+	var runs atomic.Int32
+	task := scheduler.Task{
+		Name:     "test.count",
+		Schedule: "@every 1s",
+		Run: func(ctx context.Context) error {
+			runs.Add(1)
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		},
+	}
+
+	// run two schedulers (replicas) with the same task, which should only run once per scheduled time
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+
+	var (
+		replicas []*scheduler.Scheduler
+		wg       sync.WaitGroup
+	)
+	for range 2 {
+		s, err := scheduler.NewScheduler(&scheduler.SchedulerConfig{
+			DBClient: r.PostgreSQLClient(),
+			Logger:   &logger.CustomLogger{Level: logger.LevelError, Log: slog.Default()},
+			Tasks:    []scheduler.Task{task},
+		})
+		if err != nil {
+			t.Fatalf("scheduler initialization error: %+v\n", err)
+		}
+		replicas = append(replicas, s)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	if n := runs.Load(); n < 1 || n > 3 {
+		t.Errorf("expected task to run once per scheduled time (1-3 runs), got %d", n)
+	}
+
+	status, err := replicas[0].Status(context.Background())
+	if err != nil {
+		t.Fatalf("task status error: %+v\n", err)
+	}
+	if len(status) != 1 || status[0].LastStatus == nil || *status[0].LastStatus != scheduler.StatusSucceeded {
+		t.Errorf("expected last task status '%s', got %+v", scheduler.StatusSucceeded, status)
+	}
+	if summary := status[0].Summary(); summary.Name != task.Name || summary.LastStatus != status[0].LastStatus {
+		t.Errorf("expected task summary of '%s', actual %+v", task.Name, summary)
+	}
+
+	// cached status is served without querying until it expires
+	cached, err := replicas[0].CachedStatus(context.Background())
+	if err != nil {
+		t.Fatalf("cached task status error: %+v\n", err)
+	}
+	if _, err := r.PostgreSQLClient().Exec(context.Background(), "DELETE FROM scheduled_task WHERE name = $1", task.Name); err != nil {
+		t.Fatalf("task status delete error: %+v\n", err)
+	}
+	cached, err = replicas[0].CachedStatus(context.Background())
+	if err != nil {
+		t.Fatalf("cached task status error: %+v\n", err)
+	}
+	if len(cached) != 1 || cached[0].LastStatus == nil || *cached[0].LastStatus != scheduler.StatusSucceeded {
+		t.Errorf("expected cached last task status '%s', got %+v", scheduler.StatusSucceeded, cached)
+	}
+}
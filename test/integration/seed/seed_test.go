@@ -0,0 +1,154 @@
+package seedtest
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	dbfs "github.com/jasonsites/gosk/database"
+	"github.com/jasonsites/gosk/internal/database"
+	"github.com/jasonsites/gosk/internal/logger"
+	"github.com/jasonsites/gosk/internal/modules/example"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+// schema defines the schema isolating seeded tables (migrated from the embedded migrations) from the app schema
+const schema = "seed_test"
+
+// environments defines all app environments, each of which must define seed sets
+var environments = []string{"development", "production"}
+
+// tables defines the seeded tables, whose row counts are compared across seeding runs
+var tables = []string{"example_entity", "example_entity_tag", "tag"}
+
+// Test_Seeder_Sets verifies that every environment defines seed sets for registered tables only
+func Test_Seeder_Sets(t *testing.T) {
+	r, err := utils.InitializeResolver(&resolver.Config{}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+
+	registered := map[string]bool{}
+	for _, table := range tables {
+		registered[table] = true
+	}
+
+	for _, env := range environments {
+		t.Run(env, func(t *testing.T) {
+			sets, err := r.Seeder().Sets(env)
+			if err != nil {
+				t.Fatalf("seed sets error: %+v\n", err)
+			}
+			if len(sets) == 0 {
+				t.Errorf("expected %s seed sets", env)
+			}
+			for _, set := range sets {
+				if !registered[set.Table] {
+					t.Errorf("expected registered table, actual '%s'", set.Table)
+				}
+			}
+		})
+	}
+}
+
+// Test_Seeder_Idempotent verifies that repeated upsert seeding runs (with the same random seed) leave the seeded
+// tables unchanged, and that repeated insert runs fail on conflict
+func Test_Seeder_Idempotent(t *testing.T) {
+	r, err := utils.InitializeResolver(&resolver.Config{}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	ctx := context.Background()
+	cLogger := &logger.CustomLogger{Level: logger.LevelError, Log: slog.Default()}
+
+	if _, err := r.PostgreSQLClient().Exec(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %[1]s CASCADE; CREATE SCHEMA %[1]s", schema)); err != nil {
+		t.Fatalf("schema create error: %+v\n", err)
+	}
+	defer r.PostgreSQLClient().Exec(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema))
+
+	conf := r.PostgreSQLClient().Config().Copy()
+	conf.ConnConfig.RuntimeParams["search_path"] = schema + ",public"
+	db, err := pgxpool.NewWithConfig(ctx, conf)
+	if err != nil {
+		t.Fatalf("db pool error: %+v\n", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(&database.MigratorConfig{
+		DBClient: db,
+		FS:       dbfs.Migrations,
+		Logger:   cLogger,
+		Path:     "migrations",
+	})
+	if err != nil {
+		t.Fatalf("migrator initialization error: %+v\n", err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migration error: %+v\n", err)
+	}
+
+	seeder, err := database.NewSeeder(&database.SeederConfig{
+		DBClient: db,
+		FS:       dbfs.Seeds,
+		Logger:   cLogger,
+		Path:     "seeds",
+		Tables: []database.SeedTable{
+			example.ExampleSeedTable(),
+			example.TagSeedTable(),
+			example.ExampleTagSeedTable(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("seeder initialization error: %+v\n", err)
+	}
+
+	snapshot := func(t *testing.T) map[string]string {
+		t.Helper()
+		result := make(map[string]string, len(tables))
+		for _, table := range tables {
+			// row contents are compared as an ordered aggregate of all non-audit columns
+			query := fmt.Sprintf(
+				"SELECT count(*)::text || ':' || coalesce(md5(string_agg((to_jsonb(t) - 'created_on' - 'modified_on')::text, ',' ORDER BY to_jsonb(t)::text)), '') FROM %s.%s t",
+				schema, table,
+			)
+			var state string
+			if err := db.QueryRow(ctx, query).Scan(&state); err != nil {
+				t.Fatalf("%s snapshot error: %+v\n", table, err)
+			}
+			result[table] = state
+		}
+		return result
+	}
+
+	for _, env := range environments {
+		t.Run(env, func(t *testing.T) {
+			opts := &database.SeedOptions{Environment: env, Seed: 1, Upsert: true}
+
+			if err := seeder.Run(ctx, opts); err != nil {
+				t.Fatalf("first seed error: %+v\n", err)
+			}
+			first := snapshot(t)
+
+			if err := seeder.Run(ctx, opts); err != nil {
+				t.Fatalf("repeated seed error: %+v\n", err)
+			}
+			second := snapshot(t)
+
+			for _, table := range tables {
+				if first[table] != second[table] {
+					t.Errorf("expected %s '%s', actual '%s'", table, first[table], second[table])
+				}
+			}
+
+			if err := seeder.Run(ctx, &database.SeedOptions{Environment: env, Seed: 1}); err == nil {
+				t.Errorf("expected repeated insert seed error")
+			}
+			if third := snapshot(t); third["tag"] != second["tag"] {
+				t.Errorf("expected failed seed to be rolled back, actual '%s'", third["tag"])
+			}
+		})
+	}
+}
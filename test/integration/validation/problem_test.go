@@ -0,0 +1,161 @@
+package validationtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/http/problem"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+type ProblemSetup struct {
+	Name        string
+	Description string
+	// Format defines the configured default error format
+	Format string
+	Accept string
+	Method string
+	Route  string
+	Body   string
+	// Expected defines the expected response
+	Expected ProblemExpected
+}
+
+// ProblemExpected defines the expected response media type, status code and (first) error code
+type ProblemExpected struct {
+	MediaType string
+	Code      int
+	ErrorCode string
+}
+
+// Test_ProblemDetails verifies that error responses are negotiated as JSON:API errors or RFC 9457 Problem Details,
+// by the request Accept header and the configured default error format
+func Test_ProblemDetails(t *testing.T) {
+	tests := []ProblemSetup{
+		{
+			Name:        "accept_problem",
+			Description: "returns problem details when accepted",
+			Format:      "jsonapi",
+			Accept:      problem.MediaType,
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Body:        `{"data":{"type":"example","attributes":{"title":"a"}}}`,
+			Expected:    ProblemExpected{problem.MediaType, http.StatusBadRequest, "validation_failed"},
+		},
+		{
+			Name:        "accept_problem_not_found",
+			Description: "returns problem details for unmatched paths when accepted",
+			Format:      "jsonapi",
+			Accept:      "application/problem+json, */*;q=0.1",
+			Method:      http.MethodGet,
+			Route:       "/domain/unknown",
+			Expected:    ProblemExpected{problem.MediaType, http.StatusNotFound, "not_found"},
+		},
+		{
+			Name:        "accept_preferred",
+			Description: "returns JSON:API errors when preferred over problem details",
+			Format:      "jsonapi",
+			Accept:      "application/problem+json;q=0.5, application/vnd.api+json",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Body:        `{"data":{"type":"example","attributes":{"title":"a"}}}`,
+			Expected:    ProblemExpected{jsonapi.MediaType, http.StatusBadRequest, "validation_length_too_short"},
+		},
+		{
+			Name:        "default_problem",
+			Description: "returns problem details by default when configured",
+			Format:      "problem",
+			Accept:      "*/*",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Body:        `{"data":{"type":"example","attributes":{"title":"a"}}}`,
+			Expected:    ProblemExpected{problem.MediaType, http.StatusBadRequest, "validation_failed"},
+		},
+		{
+			Name:        "default_problem_accept_jsonapi",
+			Description: "returns JSON:API errors when accepted, overriding the configured default",
+			Format:      "problem",
+			Accept:      jsonapi.MediaType,
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Body:        `{"data":{"type":"example","attributes":{"title":"a"}}}`,
+			Expected:    ProblemExpected{jsonapi.MediaType, http.StatusBadRequest, "validation_length_too_short"},
+		},
+	}
+
+	handlers := make(map[string]http.Handler)
+	for _, format := range []string{"jsonapi", "problem"} {
+		conf, err := config.LoadConfiguration()
+		if err != nil {
+			t.Fatalf("configuration load error: %+v\n", err)
+		}
+		conf.HTTP.RateLimit.Enabled = false
+		conf.HTTP.Router.ErrorFormat = format
+
+		r, err := utils.InitializeResolver(&resolver.Config{Config: conf}, resolver.HTTP)
+		if err != nil {
+			t.Fatalf("app initialization error: %+v\n", err)
+		}
+		handlers[format] = r.HTTPServer().Server.Handler
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			rd := &utils.RequestData{
+				Headers: map[string]string{"Accept": tc.Accept},
+				Method:  tc.Method,
+				Route:   tc.Route,
+			}
+			if tc.Body != "" {
+				rd.Body = strings.NewReader(tc.Body)
+			}
+			req, err := rd.SetRequestData(nil)
+			if err != nil {
+				t.Fatalf("http request error: %+v\n", err)
+			}
+
+			rec := httptest.NewRecorder()
+			handlers[tc.Format].ServeHTTP(rec, req)
+			if rec.Code != tc.Expected.Code {
+				t.Fatalf("expected '%d', actual '%d' (%s)", tc.Expected.Code, rec.Code, rec.Body.String())
+			}
+			if mediaType := rec.Header().Get("Content-Type"); mediaType != tc.Expected.MediaType {
+				t.Fatalf("expected media type '%s', actual '%s'", tc.Expected.MediaType, mediaType)
+			}
+
+			if tc.Expected.MediaType != problem.MediaType {
+				response := &jsonapi.ErrorResponse{}
+				if err := json.NewDecoder(rec.Body).Decode(response); err != nil {
+					t.Fatalf("response decode error: %+v\n", err)
+				}
+				if len(response.Errors) != 1 || response.Errors[0].Code != tc.Expected.ErrorCode {
+					t.Errorf("expected error code '%s', actual '%+v'", tc.Expected.ErrorCode, response.Errors)
+				}
+				return
+			}
+
+			details := &problem.Details{}
+			if err := json.NewDecoder(rec.Body).Decode(details); err != nil {
+				t.Fatalf("response decode error: %+v\n", err)
+			}
+			if details.Type != problem.DefaultType || details.Status != tc.Expected.Code || details.Code != tc.Expected.ErrorCode {
+				t.Errorf("expected type, status and code '%s', '%d', '%s', actual '%+v'",
+					problem.DefaultType, tc.Expected.Code, tc.Expected.ErrorCode, details)
+			}
+			if details.Instance != tc.Route || details.TraceID != rec.Header().Get("X-Request-Id") {
+				t.Errorf("expected instance '%s' and request trace id, actual '%+v'", tc.Route, details)
+			}
+			if details.Code == "validation_failed" {
+				if len(details.Errors) != 1 || details.Errors[0].Pointer != "/data/attributes/title" {
+					t.Errorf("expected /data/attributes/title error, actual '%+v'", details.Errors)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package validationtest
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jasonsites/gosk/config"
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
+	"github.com/jasonsites/gosk/internal/resolver"
+	utils "github.com/jasonsites/gosk/test/testutils"
+)
+
+type ValidationSetup struct {
+	Name        string
+	Description string
+	Method      string
+	Route       string
+	Body        string
+	// Errors defines the expected error codes by error source (pointer or parameter)
+	Errors map[string]string
+}
+
+// Test_Validation verifies that request body and query parameter validation failures (rejected before any
+// database access) are returned as JSON:API errors located by source pointer or parameter, with stable error codes
+func Test_Validation(t *testing.T) {
+	tests := []ValidationSetup{
+		{
+			Name:        "body_tags",
+			Description: "fails (400) on DTO struct tag and Validate() violations",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Body:        `{"data":{"type":"example","attributes":{"title":"a","description":"xy"}}}`,
+			Errors:      map[string]string{"/data/attributes/description": "validation_length_too_short", "/data/attributes/title": "validation_length_too_short"},
+		},
+		{
+			Name:        "body_required",
+			Description: "fails (400) on missing required attributes",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Body:        `{"data":{"type":"example","attributes":{}}}`,
+			Errors:      map[string]string{"/data/attributes/title": "validation_required"},
+		},
+		{
+			Name:        "body_envelope",
+			Description: "fails (400) on an invalid JSON:API envelope",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Body:        `{"data":{"id":"not-a-uuid","attributes":{"title":"valid title"}}}`,
+			Errors:      map[string]string{"/data/id": "validation_is_uuid", "/data/type": "validation_required"},
+		},
+		{
+			Name:        "body_type",
+			Description: "fails (400) on attribute type mismatches",
+			Method:      http.MethodPost,
+			Route:       "/domain/examples",
+			Body:        `{"data":{"type":"example","attributes":{"title":42}}}`,
+			Errors:      map[string]string{"/data/attributes/title": "validation_invalid_type"},
+		},
+		{
+			Name:        "query_page",
+			Description: "fails (400) on invalid page parameters",
+			Method:      http.MethodGet,
+			Route:       "/domain/examples?page[limit]=0&page[offset]=abc",
+			Errors:      map[string]string{"page[limit]": "validation_min_greater_equal_than_required", "page[offset]": "validation_invalid_type"},
+		},
+		{
+			Name:        "query_sort",
+			Description: "fails (400) on unsupported sort fields",
+			Method:      http.MethodGet,
+			Route:       "/domain/examples?sort=-unknown",
+			Errors:      map[string]string{"sort": "validation_invalid_sort"},
+		},
+		{
+			Name:        "query_sort_relevance",
+			Description: "fails (400) on relevance sort without a search (q) query",
+			Method:      http.MethodGet,
+			Route:       "/domain/examples?sort=-relevance",
+			Errors:      map[string]string{"sort": "validation_invalid_sort"},
+		},
+		{
+			Name:        "query_unsupported",
+			Description: "fails (400) on unsupported query parameters",
+			Method:      http.MethodGet,
+			Route:       "/domain/examples?unknown=1",
+			Errors:      map[string]string{"unknown": "validation_unsupported_parameter"},
+		},
+		{
+			Name:        "query_include",
+			Description: "fails (400) on unsupported include paths",
+			Method:      http.MethodGet,
+			Route:       "/domain/examples/00000000-0000-0000-0000-000000000000?include=unknown",
+			Errors:      map[string]string{"include": "validation_unsupported_include"},
+		},
+	}
+
+	conf, err := config.LoadConfiguration()
+	if err != nil {
+		t.Fatalf("configuration load error: %+v\n", err)
+	}
+	conf.HTTP.RateLimit.Enabled = false
+	conf.HTTP.Router.ErrorAbout = "https://docs.example.com/errors/{code}"
+
+	r, err := utils.InitializeResolver(&resolver.Config{Config: conf}, resolver.HTTP)
+	if err != nil {
+		t.Fatalf("app initialization error: %+v\n", err)
+	}
+	handler := r.HTTPServer().Server.Handler
+
+	for _, tc := range tests {
+		t.Run(tc.Name, func(t *testing.T) {
+			rd := &utils.RequestData{Method: tc.Method, Route: tc.Route}
+			if tc.Body != "" {
+				rd.Body = strings.NewReader(tc.Body)
+			}
+			req, err := rd.SetRequestData(nil)
+			if err != nil {
+				t.Fatalf("http request error: %+v\n", err)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("expected '%d', actual '%d' (%s)", http.StatusBadRequest, rec.Code, rec.Body.String())
+			}
+
+			response := &jsonapi.ErrorResponse{}
+			if err := json.NewDecoder(rec.Body).Decode(response); err != nil {
+				t.Fatalf("response decode error: %+v\n", err)
+			}
+
+			traceID := rec.Header().Get("X-Request-Id")
+			errs := make(map[string]string, len(response.Errors))
+			for _, e := range response.Errors {
+				if e.ID != traceID || e.Status != "400" {
+					t.Errorf("expected id '%s' and status '400', actual '%s' and '%s'", traceID, e.ID, e.Status)
+				}
+				if e.Links == nil || e.Links.About != "https://docs.example.com/errors/"+e.Code {
+					t.Errorf("expected links.about for code '%s', actual '%+v'", e.Code, e.Links)
+				}
+				if e.Source == nil {
+					t.Errorf("expected error source, actual none (%s)", e.Detail)
+					continue
+				}
+				errs[e.Source.Pointer+e.Source.Parameter] = e.Code
+			}
+			if !maps.Equal(errs, tc.Errors) {
+				t.Errorf("expected errors %v, actual %v (%+v)", tc.Errors, errs, response.Errors)
+			}
+		})
+	}
+}
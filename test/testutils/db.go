@@ -7,11 +7,16 @@ import (
 	"github.com/jasonsites/gosk/internal/resolver"
 )
 
+// Migrate applies all pending up migrations using the embedded migration runner
+func Migrate(r *resolver.Resolver) error {
+	return r.Migrator().Up(context.TODO())
+}
+
 // Cleanup deletes all rows on all database tables
 func Cleanup(r *resolver.Resolver) error {
 	db := r.PostgreSQLClient()
 
-	tables := []string{"example_entity"}
+	tables := []string{"example_entity", "jobs", "scheduled_task", "tag"}
 
 	for _, t := range tables {
 		sql := fmt.Sprintf("DELETE from %s", t)
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+
+	"github.com/jasonsites/gosk/internal/http/jsonapi"
 )
 
 // Expected
@@ -46,7 +48,7 @@ func (r *RequestData) SetRequestData(opts *RequestOptions) (*http.Request, error
 // SetRequestHeaders set all headers on the given request
 func (r *RequestData) SetRequestHeaders(req *http.Request, headers map[string]string, opts *RequestOptions) *http.Request {
 	if opts != nil && opts.JSON {
-		req.Header.Add("Content-Type", "application/json")
+		req.Header.Add("Content-Type", jsonapi.MediaType)
 	}
 	for k, v := range headers {
 		req.Header.Add(k, v)